@@ -98,6 +98,7 @@ func init() {
 		Type:             reflect.String,
 		IsSlice:          false,
 		AuthorizedValues: []any{},
+		Secret:           true,
 	})
 }
 
@@ -29,24 +29,27 @@ type ExtraJWTClaims struct{}
 
 func init() {
 	config.Register("auth.jwt.expiry", config.Entry{
-		Value:            300,
-		Type:             reflect.Int,
+		Value:            300 * time.Second,
+		Min:              0,
+		Type:             config.KindDuration,
+		Unit:             int64(time.Second),
 		IsSlice:          false,
 		AuthorizedValues: []any{},
 	})
-	registerKeyConfigEntry("auth.jwt.secret")
-	registerKeyConfigEntry("auth.jwt.rsa.public")
-	registerKeyConfigEntry("auth.jwt.rsa.private")
-	registerKeyConfigEntry("auth.jwt.ecdsa.public")
-	registerKeyConfigEntry("auth.jwt.ecdsa.private")
+	registerKeyConfigEntry("auth.jwt.secret", true)
+	registerKeyConfigEntry("auth.jwt.rsa.public", false)
+	registerKeyConfigEntry("auth.jwt.rsa.private", false)
+	registerKeyConfigEntry("auth.jwt.ecdsa.public", false)
+	registerKeyConfigEntry("auth.jwt.ecdsa.private", false)
 }
 
-func registerKeyConfigEntry(name string) {
+func registerKeyConfigEntry(name string, secret bool) {
 	config.Register(name, config.Entry{
 		Value:            nil,
 		Type:             reflect.String,
 		IsSlice:          false,
 		AuthorizedValues: []any{},
+		Secret:           secret,
 	})
 }
 
@@ -76,7 +79,7 @@ func (s *JWTService) Name() string {
 // GenerateToken generate a new JWT.
 // The token is created using the HMAC SHA256 method and signed using
 // the `auth.jwt.secret` config entry.
-// The token is set to expire in the amount of seconds defined by
+// The token is set to expire after the duration defined by
 // the `auth.jwt.expiry` config entry.
 //
 // The generated token will contain the following claims:
@@ -88,7 +91,7 @@ func (s *JWTService) GenerateToken(username any) (string, error) {
 }
 
 // GenerateTokenWithClaims generates a new JWT with custom claims.
-// The token is set to expire in the amount of seconds defined by
+// The token is set to expire after the duration defined by
 // the `auth.jwt.expiry` config entry.
 // Depending on the given signing method, the following configuration entries
 // will be used:
@@ -102,7 +105,7 @@ func (s *JWTService) GenerateToken(username any) (string, error) {
 //
 // `nbf` and `exp` can be overridden if they are set in the `claims` parameter.
 func (s *JWTService) GenerateTokenWithClaims(claims jwt.MapClaims, signingMethod jwt.SigningMethod) (string, error) {
-	exp := time.Duration(s.config.GetInt("auth.jwt.expiry")) * time.Second
+	exp := s.config.GetDuration("auth.jwt.expiry")
 	now := time.Now()
 	customClaims := jwt.MapClaims{
 		"nbf": now.Unix(),          // Not Before
@@ -1,30 +1,43 @@
 package config
 
 import (
-	"encoding/json"
 	"fmt"
 	"io/fs"
-	"os"
 	"reflect"
 	"strings"
 	"sync"
+	"time"
 
+	"github.com/samber/lo"
 	"goyave.dev/goyave/v5/util/errors"
 	"goyave.dev/goyave/v5/util/fsutil/osfs"
 )
 
 type object map[string]any
 
-type readFunc func(string) (object, error)
-
 // Config structure holding a configuration that should be used for a single
 // instance of `goyave.Server`.
 //
-// This structure is not protected for safe concurrent access in order to increase
-// performance. Therefore, you should never use the `Set()` function when the configuration
-// is in use by an already running server.
+// This structure is safe for concurrent use so it can be reloaded while the server is running.
+// See `Config.Reload()`.
 type Config struct {
 	config object
+
+	// sources the name of the layer each entry's value comes from.
+	sources map[string]string
+
+	// loader the loader used to load this config and reload it.
+	loader *loader
+
+	layers      []*layer
+	files       []*layer
+	subscribers []*subscriber
+
+	mu       sync.RWMutex
+	reloadMu sync.Mutex
+
+	// applyEnv true if the environment variable overrides are applied when reloading.
+	applyEnv bool
 }
 
 // Error returned when the configuration could not
@@ -43,12 +56,20 @@ func (e *Error) Unwrap() error {
 }
 
 type loader struct {
-	defaults object
-	mu       sync.RWMutex
+	defaults        object
+	secretProviders map[string]SecretProvider
+	envPrefix       string
+	envSeparator    string
+	validators      []Validator
+	mu              sync.RWMutex
 }
 
 var defaultLoader = &loader{
-	defaults: configDefaults,
+	defaults:        configDefaults,
+	secretProviders: defaultSecretProviders(),
+	envPrefix:       DefaultEnvPrefix,
+	envSeparator:    DefaultEnvSeparator,
+	validators:      defaultValidators(),
 }
 
 // Register a new config entry and its validation.
@@ -87,55 +108,121 @@ func (l *loader) register(key string, entry Entry) {
 }
 
 func (l *loader) loadFrom(fs fs.FS, path string) (*Config, error) {
-	return l.load(func(_ string) (object, error) { return l.readConfigFile(fs, path) }, path)
+	return l.load(fileLayer(fs, path, false))
 }
 
 func (l *loader) loadJSON(cfg string) (*Config, error) {
-	return l.load(l.readString, cfg)
+	return l.load(stringLayer(&osfs.FS{}, cfg, "json", decodeJSON))
+}
+
+func (l *loader) load(layers ...*layer) (*Config, error) {
+	return l.loadWithEnv(true, layers...)
+}
+
+func (l *loader) loadWithEnv(applyEnv bool, layers ...*layer) (*Config, error) {
+	result, err := l.build(applyEnv, layers)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Config{
+		config:   result.config,
+		sources:  result.sources,
+		files:    result.files,
+		loader:   l,
+		layers:   layers,
+		applyEnv: applyEnv,
+	}, nil
 }
 
-func (l *loader) load(readFunc readFunc, source string) (*Config, error) {
+// build merges the defaults and the given layers, applies the environment variable
+// overrides if `applyEnv` is true, resolves the secrets and validates the result with
+// the entries' constraints, then with the registered validators.
+func (l *loader) build(applyEnv bool, layers []*layer) (*loadResult, error) {
+	layers, err := readRemoteLayers(layers)
+	if err != nil {
+		return nil, errors.New(&Error{err})
+	}
+
 	l.mu.Lock()
 	defer l.mu.Unlock()
-	config := make(object, len(l.defaults))
-	loadDefaults(l.defaults, config)
+	result := &loadResult{
+		config:  make(object, len(l.defaults)),
+		sources: map[string]string{},
+	}
+	loadDefaults(l.defaults, result.config)
 
-	if readFunc != nil {
-		conf, err := readFunc(source)
-		if err != nil {
+	for _, layer := range layers {
+		if err := l.merge(result, layer, nil); err != nil {
 			return nil, errors.New(&Error{err})
 		}
+	}
 
-		if err := override(conf, config); err != nil {
+	if applyEnv {
+		if err := l.applyEnvOverrides(result.config, "", result.sources); err != nil {
 			return nil, errors.New(&Error{err})
 		}
 	}
 
-	if err := config.validate(""); err != nil {
+	if err := l.resolveSecrets(result.config, ""); err != nil {
 		return nil, errors.New(&Error{err})
 	}
 
-	return &Config{
-		config: config,
-	}, nil
+	if err := result.config.validate(""); err != nil {
+		return nil, errors.New(&Error{err})
+	}
+
+	if err := l.runValidators(&Config{config: result.config, sources: result.sources}); err != nil {
+		return nil, errors.New(&Error{err})
+	}
+	return result, nil
 }
 
-// Load loads the config.json file in the current working directory.
-// If the "GOYAVE_ENV" env variable is set, the config file will be picked like so:
-//   - "production": "config.production.json"
-//   - "test": "config.test.json"
-//   - By default: "config.json"
+// Load loads the config files in the current working directory. The configuration is made of
+// the following layers, each one overriding the values of the previous ones:
+//   - The base file "config.json". Required if the "GOYAVE_ENV" env variable is not set.
+//   - The environment profile file, if "GOYAVE_ENV" is set. For example "config.production.json"
+//     if "GOYAVE_ENV" is "production". Required if "GOYAVE_ENV" is set.
+//   - The local override file "config.local.json", if it exists. This file is meant to be
+//     excluded from version control. It is ignored if "GOYAVE_ENV" is "test".
+//   - The environment variables (see `SetEnvPrefix()`).
+//
+// The extension of the files is not necessarily ".json": the first existing file with one of the
+// supported extensions is used, in this order: ".json", ".yaml", ".yml", ".toml".
+//
+// Each file can include other files with the root-level "include" directive, either a single
+// path or an array of paths, relative to the including file. Included files are merged before the
+// including file so the latter can override their values.
+//
+//	{
+//		"include": ["database.json", "../shared/cors.json"],
+//		"app": {"name": "my-app"}
+//	}
+//
+// Use `Config.Source()` to know which layer an entry's value comes from.
 func Load() (*Config, error) {
-	return defaultLoader.loadFrom(&osfs.FS{}, getConfigFilePath())
+	return defaultLoader.load(profileLayers(&osfs.FS{})...)
 }
 
 // LoadDefault loads default config.
+// Environment variable overrides are not applied.
 func LoadDefault() *Config {
-	cfg, _ := defaultLoader.load(nil, "")
+	cfg, _ := defaultLoader.loadWithEnv(false)
 	return cfg
 }
 
+// LoadEnv loads the default config and overrides its entries with the matching
+// environment variables, without reading any config file. See `SetEnvPrefix()`.
+//
+// Environment variable overrides are also applied by all the other "Load" functions
+// (except `LoadDefault()`), after reading the config file.
+func LoadEnv() (*Config, error) {
+	return defaultLoader.load()
+}
+
 // LoadFrom loads a config file from the given path.
+// The format of the file is detected from its extension: ".json", ".yaml", ".yml" or ".toml".
+// Files with another extension are decoded as JSON.
 func LoadFrom(path string) (*Config, error) {
 	return defaultLoader.loadFrom(&osfs.FS{}, path)
 }
@@ -167,41 +254,16 @@ func LoadJSON(cfg string) (*Config, error) {
 	return defaultLoader.loadJSON(cfg)
 }
 
-func getConfigFilePath() string {
-	env := strings.ToLower(os.Getenv("GOYAVE_ENV"))
-	if env == "local" || env == "localhost" || env == "" {
-		return "config.json"
-	}
-	return "config." + env + ".json"
+// LoadYAML load a configuration file from raw YAML. Can be used in combination with
+// Go's embed directive. See `LoadJSON()` for an example.
+func LoadYAML(cfg string) (*Config, error) {
+	return defaultLoader.load(stringLayer(&osfs.FS{}, cfg, "yaml", decodeYAML))
 }
 
-func (l *loader) readConfigFile(filesystem fs.FS, file string) (o object, err error) {
-	var configFile fs.File
-	o = make(object, len(l.defaults))
-	configFile, err = filesystem.Open(file)
-
-	if err == nil {
-		defer func() {
-			e := configFile.Close()
-			if err == nil && e != nil {
-				err = errors.New(e)
-			}
-		}()
-		jsonParser := json.NewDecoder(configFile)
-		err = errors.New(jsonParser.Decode(&o))
-	} else {
-		err = errors.New(err)
-	}
-
-	return
-}
-
-func (l *loader) readString(str string) (object, error) {
-	conf := make(object, len(l.defaults))
-	if err := json.NewDecoder(strings.NewReader(str)).Decode(&conf); err != nil {
-		return nil, err
-	}
-	return conf, nil
+// LoadTOML load a configuration file from raw TOML. Can be used in combination with
+// Go's embed directive. See `LoadJSON()` for an example.
+func LoadTOML(cfg string) (*Config, error) {
+	return defaultLoader.load(stringLayer(&osfs.FS{}, cfg, "toml", decodeTOML))
 }
 
 // walk the config using the key. Returns the deepest category, the entry key
@@ -290,8 +352,14 @@ func createMissingCategories(currentCategory object, path string) object {
 	}
 }
 
-func override(src object, dst object) error {
+// override the entries of `dst` with the values of `src`. The given source is recorded
+// in `sources` for each overridden entry. `key` is the path of the category being overridden.
+func override(src object, dst object, source string, key string, sources map[string]string) error {
 	for k, v := range src {
+		subKey := k
+		if key != "" {
+			subKey = key + "." + k
+		}
 		if obj, ok := v.(map[string]any); ok {
 			if dstObj, ok := dst[k]; !ok {
 				dst[k] = make(object, len(obj))
@@ -299,10 +367,13 @@ func override(src object, dst object) error {
 				// Conflict: destination is not a category
 				return fmt.Errorf("\n\t- cannot override entry %q with a category", k)
 			}
-			if err := override(obj, dst[k].(object)); err != nil {
+			if err := override(obj, dst[k].(object), source, subKey, sources); err != nil {
 				return err
 			}
-		} else if entry, ok := dst[k]; ok {
+			continue
+		}
+
+		if entry, ok := dst[k]; ok {
 			e, ok := entry.(*Entry)
 			if !ok {
 				// Conflict: override category with an entry
@@ -315,6 +386,7 @@ func override(src object, dst object) error {
 			// and "any" authorized values.
 			dst[k] = makeEntryFromValue(v)
 		}
+		sources[subKey] = source
 	}
 	return nil
 }
@@ -357,6 +429,20 @@ func (c *Config) Get(key string) any {
 }
 
 func (c *Config) get(key string) (any, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.lookup(key)
+}
+
+func (c *Config) lookup(key string) (any, bool) {
+	entry, ok := c.lookupEntry(key)
+	if !ok {
+		return nil, false
+	}
+	return entry.Value, entry.Value != nil // nil means unset
+}
+
+func (c *Config) lookupEntry(key string) (*Entry, bool) {
 	currentCategory := c.config
 	start := 0
 	dotIndex := strings.Index(key, ".")
@@ -372,8 +458,7 @@ func (c *Config) get(key string) (any, bool) {
 		if category, ok := entry.(object); ok {
 			currentCategory = category
 		} else {
-			val := entry.(*Entry).Value
-			return val, val != nil // nil means unset
+			return entry.(*Entry), true
 		}
 
 		if dotIndex+1 <= len(key) {
@@ -389,6 +474,27 @@ func (c *Config) get(key string) (any, bool) {
 	return nil, false
 }
 
+// getInUnit returns the value of a `KindDuration` or `KindByteSize` entry divided by
+// its `Entry.Unit`, so these entries can still be read as numbers in their legacy unit.
+// Returns false if the entry doesn't exist, is unset or is of another kind.
+func (c *Config) getInUnit(key string) (float64, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	entry, ok := c.lookupEntry(key)
+	if !ok || entry.IsSlice {
+		return 0, false
+	}
+	unit := float64(lo.Ternary(entry.Unit == 0, 1, entry.Unit))
+	switch val := entry.Value.(type) {
+	case time.Duration:
+		return float64(val) / unit, entry.Type == KindDuration
+	case int64:
+		return float64(val) / unit, entry.Type == KindByteSize
+	default:
+		return 0, false
+	}
+}
+
 // GetString a config entry as string.
 // Panics if entry is not a string or if it doesn't exist.
 func (c *Config) GetString(key string) string {
@@ -410,8 +516,12 @@ func (c *Config) GetBool(key string) bool {
 }
 
 // GetInt a config entry as int.
+// Durations and byte sizes are returned as a whole number of `Entry.Unit`.
 // Panics if entry is not an int or if it doesn't exist.
 func (c *Config) GetInt(key string) int {
+	if val, ok := c.getInUnit(key); ok {
+		return int(val)
+	}
 	val, ok := c.Get(key).(int)
 	if !ok {
 		panic(errors.Errorf("config entry \"%s\" is not an int", key))
@@ -420,8 +530,12 @@ func (c *Config) GetInt(key string) int {
 }
 
 // GetFloat a config entry as float64.
+// Durations and byte sizes are returned as a number of `Entry.Unit`.
 // Panics if entry is not a float64 or if it doesn't exist.
 func (c *Config) GetFloat(key string) float64 {
+	if val, ok := c.getInUnit(key); ok {
+		return val
+	}
 	val, ok := c.Get(key).(float64)
 	if !ok {
 		panic(errors.Errorf("config entry \"%s\" is not a float64", key))
@@ -429,6 +543,26 @@ func (c *Config) GetFloat(key string) float64 {
 	return val
 }
 
+// GetDuration a config entry as `time.Duration`. See `KindDuration`.
+// Panics if entry is not a duration or if it doesn't exist.
+func (c *Config) GetDuration(key string) time.Duration {
+	val, ok := c.Get(key).(time.Duration)
+	if !ok {
+		panic(errors.Errorf("config entry \"%s\" is not a duration", key))
+	}
+	return val
+}
+
+// GetByteSize a config entry as a number of bytes. See `KindByteSize`.
+// Panics if entry is not a byte size or if it doesn't exist.
+func (c *Config) GetByteSize(key string) int64 {
+	val, ok := c.Get(key).(int64)
+	if !ok {
+		panic(errors.Errorf("config entry \"%s\" is not a byte size", key))
+	}
+	return val
+}
+
 // GetStringSlice a config entry as []string.
 // Panics if entry is not a string slice or if it doesn't exist.
 func (c *Config) GetStringSlice(key string) []string {
@@ -469,6 +603,46 @@ func (c *Config) GetFloatSlice(key string) []float64 {
 	return str
 }
 
+// Source returns the name of the layer the value of the given entry comes from:
+//   - the path of the config file (or "<json>", "<yaml>" or "<toml>" for raw configs)
+//   - `SourceEnvPrefix` followed by the name of the environment variable ("env:GOYAVE_SERVER_PORT")
+//   - `SourceRuntime` if the value was changed using `Config.Set()`
+//   - `SourceDefault` if the entry kept its default value
+//
+// Returns an empty string if the entry doesn't exist.
+func (c *Config) Source(key string) string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if source, ok := c.sources[key]; ok {
+		return source
+	}
+	if _, ok := c.lookup(key); !ok && !c.isRegistered(key) {
+		return ""
+	}
+	return SourceDefault
+}
+
+// isRegistered returns true if the given key identifies an entry, even if it has no value.
+func (c *Config) isRegistered(key string) bool {
+	current := c.config
+	segments := strings.Split(key, ".")
+	for i, segment := range segments {
+		value, ok := current[segment]
+		if !ok {
+			return false
+		}
+		category, isCategory := value.(object)
+		if i == len(segments)-1 {
+			return !isCategory
+		}
+		if !isCategory {
+			return false
+		}
+		current = category
+	}
+	return false
+}
+
 // Has check if a config entry exists.
 func (c *Config) Has(key string) bool {
 	_, ok := c.get(key)
@@ -488,9 +662,12 @@ func (c *Config) Has(key string) bool {
 //
 // Panics and revert changes in case of error.
 //
-// This operation is not concurrently safe and should not be used when the configuration
-// is in use by an already running server.
+// Changes made with this function are lost when the configuration is reloaded.
+// Entries that are only read when the server starts can be changed but the change
+// won't be taken into account by the running server.
 func (c *Config) Set(key string, value any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	category, entryKey, exists := walk(c.config, key)
 	if exists {
 		entry := category[entryKey].(*Entry)
@@ -504,4 +681,8 @@ func (c *Config) Set(key string, value any) {
 	} else {
 		category[entryKey] = makeEntryFromValue(value)
 	}
+	if c.sources == nil {
+		c.sources = map[string]string{}
+	}
+	c.sources[key] = SourceRuntime
 }
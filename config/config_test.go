@@ -5,6 +5,7 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -19,17 +20,6 @@ func TestConfigError(t *testing.T) {
 	assert.Equal(t, e.err, e.Unwrap())
 }
 
-func TestGetConfigFilePath(t *testing.T) {
-	t.Setenv("GOYAVE_ENV", "localhost")
-	assert.Equal(t, "config.json", getConfigFilePath())
-
-	t.Setenv("GOYAVE_ENV", "test")
-	assert.Equal(t, "config.test.json", getConfigFilePath())
-
-	t.Setenv("GOYAVE_ENV", "production")
-	assert.Equal(t, "config.production.json", getConfigFilePath())
-}
-
 func TestRegister(t *testing.T) {
 	entry := Entry{
 		Value:            "",
@@ -138,6 +128,96 @@ func TestLoad(t *testing.T) {
 		assert.Equal(t, expected, cfg.config["app"].(object)["name"])
 	})
 
+	t.Run("LoadFrom_formats", func(t *testing.T) {
+		for _, path := range []string{"../resources/custom_config.yml", "../resources/custom_config.toml"} {
+			t.Run(path, func(t *testing.T) {
+				cfg, err := LoadFrom(path)
+				require.NoError(t, err)
+
+				assert.Equal(t, "value", cfg.GetString("custom-entry"))
+				assert.Equal(t, "yaml-toml", cfg.GetString("app.name"))
+				assert.Equal(t, 1234, cfg.GetInt("server.port"))
+				assert.Equal(t, int64(20<<20), cfg.GetByteSize("server.maxUploadSize"))
+				assert.True(t, cfg.GetBool("database.config.dryRun"))
+				assert.Equal(t, []any{"a", "b"}, cfg.Get("custom.list"))
+				assert.InEpsilon(t, 1.5, cfg.Get("custom.number"), 0)
+				assert.Equal(t, "en-US", cfg.GetString("app.defaultLanguage")) // Default config also loaded
+			})
+		}
+	})
+
+	t.Run("LoadYAML", func(t *testing.T) {
+		cfg, err := LoadYAML(`
+# Comment
+custom-entry: value
+server:
+  port: 1234
+  maxUploadSize: 20
+`)
+		require.NoError(t, err)
+		assert.Equal(t, "value", cfg.GetString("custom-entry"))
+		assert.Equal(t, 1234, cfg.GetInt("server.port"))
+		assert.Equal(t, int64(20<<20), cfg.GetByteSize("server.maxUploadSize"))
+
+		cfg, err = LoadYAML("")
+		require.NoError(t, err)
+		assert.Equal(t, "goyave", cfg.GetString("app.name"))
+
+		cfg, err = LoadYAML(`app: {name: 123}`)
+		assert.Nil(t, cfg)
+		require.Error(t, err)
+		assert.Equal(t, "Config error: \n\t- \"app.name\" type must be string", err.Error())
+
+		cfg, err = LoadYAML("app: [")
+		assert.Nil(t, cfg)
+		require.Error(t, err)
+	})
+
+	t.Run("LoadTOML", func(t *testing.T) {
+		cfg, err := LoadTOML(`
+# Comment
+custom-entry = "value"
+
+[server]
+port = 1_234
+maxUploadSize = 20
+`)
+		require.NoError(t, err)
+		assert.Equal(t, "value", cfg.GetString("custom-entry"))
+		assert.Equal(t, 1234, cfg.GetInt("server.port"))
+		assert.Equal(t, int64(20<<20), cfg.GetByteSize("server.maxUploadSize"))
+
+		cfg, err = LoadTOML(`app = "value"`)
+		assert.Nil(t, cfg)
+		require.Error(t, err)
+		assert.Equal(t, "Config error: \n\t- cannot override category \"app\" with an entry", err.Error())
+
+		cfg, err = LoadTOML(`app = `)
+		assert.Nil(t, cfg)
+		require.Error(t, err)
+	})
+
+	t.Run("fractional_units", func(t *testing.T) {
+		cfg, err := LoadJSON(`{"server": {"maxUploadSize": 2.3, "writeTimeout": 0.5}}`)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2411725), cfg.GetByteSize("server.maxUploadSize"))
+		assert.Equal(t, 500*time.Millisecond, cfg.GetDuration("server.writeTimeout"))
+
+		cfg, err = LoadJSON(`{"server": {"maxUploadSize": 0.1}}`)
+		require.NoError(t, err)
+		assert.Equal(t, int64(104858), cfg.GetByteSize("server.maxUploadSize"))
+	})
+
+	t.Run("legacy_units", func(t *testing.T) {
+		cfg, err := LoadJSON(`{"server": {"maxUploadSize": "2MiB", "writeTimeout": "1m30s"}, "database": {"defaultReadQueryTimeout": 250}}`)
+		require.NoError(t, err)
+		assert.InEpsilon(t, 2.0, cfg.GetFloat("server.maxUploadSize"), 0)
+		assert.Equal(t, 2, cfg.GetInt("server.maxUploadSize"))
+		assert.Equal(t, 90, cfg.GetInt("server.writeTimeout"))
+		assert.InEpsilon(t, 90.0, cfg.GetFloat("server.writeTimeout"), 0)
+		assert.Equal(t, 250, cfg.GetInt("database.defaultReadQueryTimeout"))
+	})
+
 	t.Run("LoadJSON Invalid", func(t *testing.T) {
 		cfg, err := LoadJSON(`{"unclosed":`)
 		assert.Nil(t, cfg)
@@ -451,12 +531,28 @@ func TestConfig(t *testing.T) {
 		IsSlice:          true,
 	})
 
+	loader.register("testCategory.duration", Entry{
+		Value:            nil,
+		AuthorizedValues: []any{},
+		Type:             KindDuration,
+		Unit:             int64(time.Second),
+	})
+
+	loader.register("testCategory.byteSize", Entry{
+		Value:            nil,
+		AuthorizedValues: []any{},
+		Type:             KindByteSize,
+		Unit:             1 << 20,
+	})
+
 	cfgJSON := `{
 		"rootLevel": "root",
 		"testCategory": {
 			"string": "hello",
 			"int": 123,
 			"float": 123.456,
+			"duration": "1m30s",
+			"byteSize": 1.5,
 			"bool": true,
 			"stringSlice": ["a", "b"],
 			"intSlice": [1, 2],
@@ -524,6 +620,24 @@ func TestConfig(t *testing.T) {
 		})
 	})
 
+	t.Run("GetDuration", func(t *testing.T) {
+		v := cfg.GetDuration("testCategory.duration")
+		assert.Equal(t, 90*time.Second, v)
+
+		assert.Panics(t, func() {
+			cfg.GetDuration("testCategory.int")
+		})
+	})
+
+	t.Run("GetByteSize", func(t *testing.T) {
+		v := cfg.GetByteSize("testCategory.byteSize")
+		assert.Equal(t, int64(1536*1024), v)
+
+		assert.Panics(t, func() {
+			cfg.GetByteSize("testCategory.int")
+		})
+	})
+
 	t.Run("GetStringSlice", func(t *testing.T) {
 		v := cfg.GetStringSlice("testCategory.stringSlice")
 		assert.Equal(t, []string{"a", "b"}, v)
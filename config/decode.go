@@ -0,0 +1,98 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// decodeFunc decodes a configuration document. The returned object must only contain values of the
+// types produced by the standard JSON decoder (`map[string]any`, `[]any`, `float64`, `string`, `bool`
+// and `nil`) so the validation and type conversion of the entries is identical regardless of the format.
+type decodeFunc func(io.Reader) (object, error)
+
+// decoders the supported configuration file formats, identified by their extension.
+var decoders = map[string]decodeFunc{
+	".json": decodeJSON,
+	".yaml": decodeYAML,
+	".yml":  decodeYAML,
+	".toml": decodeTOML,
+}
+
+// extensions the supported configuration file extensions, in order of priority.
+var extensions = []string{".json", ".yaml", ".yml", ".toml"}
+
+// decoderFor returns the decoder matching the extension of the given file.
+// Files without a known extension are considered to be JSON.
+func decoderFor(file string) decodeFunc {
+	if decode, ok := decoders[strings.ToLower(path.Ext(file))]; ok {
+		return decode
+	}
+	return decodeJSON
+}
+
+func decodeJSON(r io.Reader) (object, error) {
+	conf := object{}
+	if err := json.NewDecoder(r).Decode(&conf); err != nil {
+		return nil, err
+	}
+	return conf, nil
+}
+
+func decodeYAML(r io.Reader) (object, error) {
+	conf := map[string]any{}
+	if err := yaml.NewDecoder(r).Decode(&conf); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	return object(normalize(conf).(map[string]any)), nil
+}
+
+func decodeTOML(r io.Reader) (object, error) {
+	conf := map[string]any{}
+	if err := toml.NewDecoder(r).Decode(&conf); err != nil {
+		return nil, err
+	}
+	return object(normalize(conf).(map[string]any)), nil
+}
+
+// normalize converts the values decoded by the YAML and TOML decoders to the types
+// the JSON decoder would have produced. Dates and times are converted to strings.
+func normalize(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for k, val := range v {
+			v[k] = normalize(val)
+		}
+		return v
+	case map[any]any:
+		m := make(map[string]any, len(v))
+		for k, val := range v {
+			m[fmt.Sprint(k)] = normalize(val)
+		}
+		return m
+	case []any:
+		for i, val := range v {
+			v[i] = normalize(val)
+		}
+		return v
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case uint64:
+		return float64(v)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case toml.LocalDate, toml.LocalTime, toml.LocalDateTime:
+		return fmt.Sprint(v)
+	default:
+		return v
+	}
+}
@@ -0,0 +1,106 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecoderFor(t *testing.T) {
+	cases := map[string]string{
+		"config.json":      `{"key": 1}`,
+		"config.yaml":      "key: 1",
+		"config.YML":       "key: 1",
+		"config.toml":      "key = 1",
+		"config.unknown":   `{"key": 1}`,
+		"no_extension":     `{"key": 1}`,
+		"dir.yaml/cfg.tml": `{"key": 1}`,
+	}
+	for file, doc := range cases {
+		t.Run(file, func(t *testing.T) {
+			o, err := decoderFor(file)(strings.NewReader(doc))
+			require.NoError(t, err)
+			assert.Equal(t, object{"key": 1.0}, o)
+		})
+	}
+}
+
+func TestDecodeYAML(t *testing.T) {
+	o, err := decodeYAML(strings.NewReader(`
+int: 12
+float: 1.5
+string: "str"
+bool: true
+nothing: ~
+date: 2024-01-02T03:04:05Z
+list: [1, "a", {1: b}]
+nested:
+  key: value
+`))
+	require.NoError(t, err)
+	assert.Equal(t, object{
+		"int":     12.0,
+		"float":   1.5,
+		"string":  "str",
+		"bool":    true,
+		"nothing": nil,
+		"date":    "2024-01-02T03:04:05Z",
+		"list":    []any{1.0, "a", map[string]any{"1": "b"}},
+		"nested":  map[string]any{"key": "value"},
+	}, o)
+}
+
+func TestDecodeTOML(t *testing.T) {
+	o, err := decodeTOML(strings.NewReader(`
+int = 0xff
+float = 1.5
+string = "str"
+bool = true
+date = 2024-01-02T03:04:05Z
+local_date = 1979-05-27
+local_time = 07:32:00
+local_date_time = 1979-05-27T07:32:00
+list = [1, "a", { b = 2 }]
+dotted.key = "value"
+
+[nested]
+key = "value"
+
+[[array]]
+name = "a"
+
+[[array]]
+name = "b"
+`))
+	require.NoError(t, err)
+	assert.Equal(t, object{
+		"int":             255.0,
+		"float":           1.5,
+		"string":          "str",
+		"bool":            true,
+		"date":            "2024-01-02T03:04:05Z",
+		"local_date":      "1979-05-27",
+		"local_time":      "07:32:00",
+		"local_date_time": "1979-05-27T07:32:00",
+		"list":            []any{1.0, "a", map[string]any{"b": 2.0}},
+		"dotted":          map[string]any{"key": "value"},
+		"nested":          map[string]any{"key": "value"},
+		"array":           []any{map[string]any{"name": "a"}, map[string]any{"name": "b"}},
+	}, o)
+
+	cases := []string{
+		"a = 1\na = 2",
+		"[a]\nb = 1\n[a]\nc = 2",
+		"a = ",
+		"[a",
+	}
+	for _, doc := range cases {
+		t.Run(doc, func(t *testing.T) {
+			o, err := decodeTOML(strings.NewReader(doc))
+			assert.Nil(t, o)
+			require.Error(t, err)
+		})
+	}
+}
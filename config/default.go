@@ -1,52 +1,347 @@
 package config
 
-import "reflect"
+import (
+	"reflect"
+	"time"
+)
 
 var configDefaults = object{
 	"app": object{
-		"name":            &Entry{"goyave", []any{}, reflect.String, false, true},
-		"environment":     &Entry{"localhost", []any{}, reflect.String, false, true},
-		"debug":           &Entry{true, []any{}, reflect.Bool, false, true},
-		"defaultLanguage": &Entry{"en-US", []any{}, reflect.String, false, true},
+		"name": &Entry{
+			Value:            "goyave",
+			AuthorizedValues: []any{},
+			Type:             reflect.String,
+			IsSlice:          false,
+			Required:         true,
+		},
+		"environment": &Entry{
+			Value:            "localhost",
+			AuthorizedValues: []any{},
+			Type:             reflect.String,
+			IsSlice:          false,
+			Required:         true,
+		},
+		"debug": &Entry{
+			Value:            true,
+			AuthorizedValues: []any{},
+			Type:             reflect.Bool,
+			IsSlice:          false,
+			Required:         true,
+		},
+		"defaultLanguage": &Entry{
+			Value:            "en-US",
+			AuthorizedValues: []any{},
+			Type:             reflect.String,
+			IsSlice:          false,
+			Required:         true,
+			RequiresRestart:  true,
+		},
 	},
 	"server": object{
-		"host":                  &Entry{"127.0.0.1", []any{}, reflect.String, false, true},
-		"domain":                &Entry{"", []any{}, reflect.String, false, true},
-		"port":                  &Entry{8080, []any{}, reflect.Int, false, true},
-		"writeTimeout":          &Entry{10, []any{}, reflect.Int, false, true},
-		"readTimeout":           &Entry{10, []any{}, reflect.Int, false, true},
-		"readHeaderTimeout":     &Entry{10, []any{}, reflect.Int, false, true},
-		"idleTimeout":           &Entry{20, []any{}, reflect.Int, false, true},
-		"websocketCloseTimeout": &Entry{10, []any{}, reflect.Int, false, true},
-		"maxUploadSize":         &Entry{10.0, []any{}, reflect.Float64, false, true},
+		"host": &Entry{
+			Value:            "127.0.0.1",
+			AuthorizedValues: []any{},
+			Type:             reflect.String,
+			IsSlice:          false,
+			Required:         true,
+			RequiresRestart:  true,
+		},
+		"domain": &Entry{
+			Value:            "",
+			AuthorizedValues: []any{},
+			Type:             reflect.String,
+			IsSlice:          false,
+			Required:         true,
+			RequiresRestart:  true,
+		},
+		"port": &Entry{
+			Value:            8080,
+			Min:              0,
+			Max:              65535,
+			AuthorizedValues: []any{},
+			Type:             reflect.Int,
+			IsSlice:          false,
+			Required:         true,
+			RequiresRestart:  true,
+		},
+		"writeTimeout": &Entry{
+			Value:            10 * time.Second,
+			Min:              0,
+			AuthorizedValues: []any{},
+			Type:             KindDuration,
+			Unit:             int64(time.Second),
+			IsSlice:          false,
+			Required:         true,
+			RequiresRestart:  true,
+		},
+		"readTimeout": &Entry{
+			Value:            10 * time.Second,
+			Min:              0,
+			AuthorizedValues: []any{},
+			Type:             KindDuration,
+			Unit:             int64(time.Second),
+			IsSlice:          false,
+			Required:         true,
+			RequiresRestart:  true,
+		},
+		"readHeaderTimeout": &Entry{
+			Value:            10 * time.Second,
+			Min:              0,
+			AuthorizedValues: []any{},
+			Type:             KindDuration,
+			Unit:             int64(time.Second),
+			IsSlice:          false,
+			Required:         true,
+			RequiresRestart:  true,
+		},
+		"idleTimeout": &Entry{
+			Value:            20 * time.Second,
+			Min:              0,
+			AuthorizedValues: []any{},
+			Type:             KindDuration,
+			Unit:             int64(time.Second),
+			IsSlice:          false,
+			Required:         true,
+			RequiresRestart:  true,
+		},
+		"websocketCloseTimeout": &Entry{
+			Value:            10 * time.Second,
+			Min:              0,
+			AuthorizedValues: []any{},
+			Type:             KindDuration,
+			Unit:             int64(time.Second),
+			IsSlice:          false,
+			Required:         true,
+		},
+		"maxUploadSize": &Entry{
+			Value:            int64(10 << 20),
+			Min:              0,
+			AuthorizedValues: []any{},
+			Type:             KindByteSize,
+			Unit:             1 << 20,
+			IsSlice:          false,
+			Required:         true,
+		},
 		"proxy": object{
-			"protocol": &Entry{"http", []any{"http", "https"}, reflect.String, false, true},
-			"host":     &Entry{nil, []any{}, reflect.String, false, false},
-			"port":     &Entry{80, []any{}, reflect.Int, false, true},
-			"base":     &Entry{"", []any{}, reflect.String, false, true},
+			"protocol": &Entry{
+				Value:            "http",
+				AuthorizedValues: []any{"http", "https"},
+				Type:             reflect.String,
+				IsSlice:          false,
+				Required:         true,
+				RequiresRestart:  true,
+			},
+			"host": &Entry{
+				Value:            nil,
+				AuthorizedValues: []any{},
+				Type:             reflect.String,
+				IsSlice:          false,
+				RequiresRestart:  true,
+			},
+			"port": &Entry{
+				Value:            80,
+				Min:              0,
+				Max:              65535,
+				AuthorizedValues: []any{},
+				Type:             reflect.Int,
+				IsSlice:          false,
+				Required:         true,
+				RequiresRestart:  true,
+			},
+			"base": &Entry{
+				Value:            "",
+				AuthorizedValues: []any{},
+				Type:             reflect.String,
+				IsSlice:          false,
+				Required:         true,
+				RequiresRestart:  true,
+			},
 		},
 	},
 	"database": object{
-		"connection":               &Entry{"none", []any{}, reflect.String, false, true},
-		"host":                     &Entry{"127.0.0.1", []any{}, reflect.String, false, true},
-		"port":                     &Entry{0, []any{}, reflect.Int, false, true},
-		"name":                     &Entry{"", []any{}, reflect.String, false, true},
-		"username":                 &Entry{"", []any{}, reflect.String, false, true},
-		"password":                 &Entry{"", []any{}, reflect.String, false, true},
-		"options":                  &Entry{"", []any{}, reflect.String, false, true},
-		"maxOpenConnections":       &Entry{20, []any{}, reflect.Int, false, true},
-		"maxIdleConnections":       &Entry{20, []any{}, reflect.Int, false, true},
-		"maxLifetime":              &Entry{300, []any{}, reflect.Int, false, true},
-		"defaultReadQueryTimeout":  &Entry{20000, []any{}, reflect.Int, false, true},
-		"defaultWriteQueryTimeout": &Entry{40000, []any{}, reflect.Int, false, true},
+		"connection": &Entry{
+			Value:            "none",
+			AuthorizedValues: []any{},
+			Type:             reflect.String,
+			IsSlice:          false,
+			Required:         true,
+			RequiresRestart:  true,
+		},
+		"host": &Entry{
+			Value:            "127.0.0.1",
+			AuthorizedValues: []any{},
+			Type:             reflect.String,
+			IsSlice:          false,
+			Required:         true,
+			RequiresRestart:  true,
+		},
+		"port": &Entry{
+			Value:            0,
+			Min:              0,
+			Max:              65535,
+			AuthorizedValues: []any{},
+			Type:             reflect.Int,
+			IsSlice:          false,
+			Required:         true,
+			RequiresRestart:  true,
+		},
+		"name": &Entry{
+			Value:            "",
+			AuthorizedValues: []any{},
+			Type:             reflect.String,
+			IsSlice:          false,
+			Required:         true,
+			RequiresRestart:  true,
+		},
+		"username": &Entry{
+			Value:            "",
+			AuthorizedValues: []any{},
+			Type:             reflect.String,
+			IsSlice:          false,
+			Required:         true,
+			RequiresRestart:  true,
+		},
+		"password": &Entry{
+			Value:            "",
+			AuthorizedValues: []any{},
+			Type:             reflect.String,
+			IsSlice:          false,
+			Required:         true,
+			RequiresRestart:  true,
+			Secret:           true,
+		},
+		"options": &Entry{
+			Value:            "",
+			AuthorizedValues: []any{},
+			Type:             reflect.String,
+			IsSlice:          false,
+			Required:         true,
+			RequiresRestart:  true,
+		},
+		"maxOpenConnections": &Entry{
+			Value:            20,
+			Min:              0,
+			AuthorizedValues: []any{},
+			Type:             reflect.Int,
+			IsSlice:          false,
+			Required:         true,
+			RequiresRestart:  true,
+		},
+		"maxIdleConnections": &Entry{
+			Value:            20,
+			Min:              0,
+			AuthorizedValues: []any{},
+			Type:             reflect.Int,
+			IsSlice:          false,
+			Required:         true,
+			RequiresRestart:  true,
+		},
+		"maxLifetime": &Entry{
+			Value:            300 * time.Second,
+			Min:              0,
+			AuthorizedValues: []any{},
+			Type:             KindDuration,
+			Unit:             int64(time.Second),
+			IsSlice:          false,
+			Required:         true,
+			RequiresRestart:  true,
+		},
+		"defaultReadQueryTimeout": &Entry{
+			Value:            20 * time.Second,
+			Min:              0,
+			AuthorizedValues: []any{},
+			Type:             KindDuration,
+			Unit:             int64(time.Millisecond),
+			IsSlice:          false,
+			Required:         true,
+			RequiresRestart:  true,
+		},
+		"defaultWriteQueryTimeout": &Entry{
+			Value:            40 * time.Second,
+			Min:              0,
+			AuthorizedValues: []any{},
+			Type:             KindDuration,
+			Unit:             int64(time.Millisecond),
+			IsSlice:          false,
+			Required:         true,
+			RequiresRestart:  true,
+		},
+		"slowQueryThreshold": &Entry{
+			Value:            200 * time.Millisecond,
+			Min:              0,
+			AuthorizedValues: []any{},
+			Type:             KindDuration,
+			Unit:             int64(time.Millisecond),
+			IsSlice:          false,
+			Required:         true,
+			RequiresRestart:  true,
+		},
+		"repeatedQueryThreshold": &Entry{
+			Value:            5,
+			Min:              0,
+			AuthorizedValues: []any{},
+			Type:             reflect.Int,
+			IsSlice:          false,
+			Required:         true,
+			RequiresRestart:  true,
+		},
 		"config": object{
-			"skipDefaultTransaction":                   &Entry{false, []any{}, reflect.Bool, false, true},
-			"dryRun":                                   &Entry{false, []any{}, reflect.Bool, false, true},
-			"prepareStmt":                              &Entry{true, []any{}, reflect.Bool, false, true},
-			"disableNestedTransaction":                 &Entry{false, []any{}, reflect.Bool, false, true},
-			"allowGlobalUpdate":                        &Entry{false, []any{}, reflect.Bool, false, true},
-			"disableAutomaticPing":                     &Entry{false, []any{}, reflect.Bool, false, true},
-			"disableForeignKeyConstraintWhenMigrating": &Entry{false, []any{}, reflect.Bool, false, true},
+			"skipDefaultTransaction": &Entry{
+				Value:            false,
+				AuthorizedValues: []any{},
+				Type:             reflect.Bool,
+				IsSlice:          false,
+				Required:         true,
+				RequiresRestart:  true,
+			},
+			"dryRun": &Entry{
+				Value:            false,
+				AuthorizedValues: []any{},
+				Type:             reflect.Bool,
+				IsSlice:          false,
+				Required:         true,
+				RequiresRestart:  true,
+			},
+			"prepareStmt": &Entry{
+				Value:            true,
+				AuthorizedValues: []any{},
+				Type:             reflect.Bool,
+				IsSlice:          false,
+				Required:         true,
+				RequiresRestart:  true,
+			},
+			"disableNestedTransaction": &Entry{
+				Value:            false,
+				AuthorizedValues: []any{},
+				Type:             reflect.Bool,
+				IsSlice:          false,
+				Required:         true,
+				RequiresRestart:  true,
+			},
+			"allowGlobalUpdate": &Entry{
+				Value:            false,
+				AuthorizedValues: []any{},
+				Type:             reflect.Bool,
+				IsSlice:          false,
+				Required:         true,
+				RequiresRestart:  true,
+			},
+			"disableAutomaticPing": &Entry{
+				Value:            false,
+				AuthorizedValues: []any{},
+				Type:             reflect.Bool,
+				IsSlice:          false,
+				Required:         true,
+				RequiresRestart:  true,
+			},
+			"disableForeignKeyConstraintWhenMigrating": &Entry{
+				Value:            false,
+				AuthorizedValues: []any{},
+				Type:             reflect.Bool,
+				IsSlice:          false,
+				Required:         true,
+				RequiresRestart:  true,
+			},
 		},
 	},
 }
@@ -70,7 +365,9 @@ func loadDefaults(src object, dst object) {
 				}
 				value = slice.Interface()
 			}
-			dst[k] = &Entry{value, entry.AuthorizedValues, entry.Type, entry.IsSlice, entry.Required}
+			copied := *entry
+			copied.Value = value
+			dst[k] = &copied
 		}
 	}
 }
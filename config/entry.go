@@ -1,9 +1,9 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"reflect"
-	"strconv"
 	"strings"
 
 	"github.com/samber/lo"
@@ -14,12 +14,48 @@ import (
 // It contains the entry value, its expected type (for validation)
 // and a slice of authorized values (for validation too). If this slice
 // is empty, it means any value can be used, provided it is of the correct type.
+//
+// The type can be one of the custom kinds `KindDuration`, `KindByteSize`, `KindURL` and `KindHost`.
+//
+// For slices, the authorized values, bounds and pattern apply to each element of the slice.
+//
+//nolint:govet // The original fields come first so unkeyed literals keep compiling
 type Entry struct {
 	Value            any
 	AuthorizedValues []any // Leave empty for "any"
 	Type             reflect.Kind
 	IsSlice          bool
 	Required         bool
+
+	// Min the minimum value (inclusive) of numbers, durations and byte sizes, or the minimum
+	// length of strings. Durations and byte sizes bounds are converted like values, so they can
+	// be written as strings ("1s", "1KiB"). Leave nil for no minimum.
+	Min any
+
+	// Max the maximum value (inclusive) of numbers, durations and byte sizes, or the maximum
+	// length of strings. Durations and byte sizes bounds are converted like values, so they can
+	// be written as strings ("1h", "10MiB"). Leave nil for no maximum.
+	Max any
+
+	// Pattern a regular expression string values must match. Leave empty to accept any value.
+	Pattern string
+
+	// Unit the number of nanoseconds (`KindDuration`) or bytes (`KindByteSize`) represented
+	// by a numeric value. For example, if `Unit` is `int64(time.Second)`, the value `10` is
+	// interpreted as 10 seconds. If zero, numeric values are interpreted as nanoseconds or bytes.
+	// Ignored for other kinds.
+	Unit int64
+
+	// RequiresRestart marks entries that are only read when the server starts.
+	// Changing the value of such entries when reloading the config is rejected
+	// with a `*RestartRequiredError`.
+	RequiresRestart bool
+
+	// Secret marks entries containing sensitive data. The value of secret entries
+	// is never included in error messages nor in config dumps. Entries whose value
+	// is resolved from a secret provider are automatically marked as secret.
+	// See `RegisterSecretProvider()`.
+	Secret bool
 }
 
 func makeEntryFromValue(value any) *Entry {
@@ -30,7 +66,15 @@ func makeEntryFromValue(value any) *Entry {
 		kind = t.Elem().Kind()
 		isSlice = true
 	}
-	return &Entry{value, []any{}, kind, isSlice, false}
+	if t == durationType || (isSlice && t.Elem() == durationType) {
+		kind = KindDuration
+	}
+	return &Entry{
+		Value:            value,
+		AuthorizedValues: []any{},
+		Type:             kind,
+		IsSlice:          isSlice,
+	}
 }
 
 func (e *Entry) validate(key string) error {
@@ -51,7 +95,7 @@ func (e *Entry) validate(key string) error {
 	if e.IsSlice && kind == reflect.Slice {
 		kind = t.Elem().Kind()
 	}
-	if kind != e.Type && !e.tryConversion(kind) {
+	if (kind != e.Type || isCustomKind(e.Type)) && !e.tryConversion(kind) {
 		var message string
 		if e.IsSlice {
 			message = "%q must be a slice of %s"
@@ -59,28 +103,42 @@ func (e *Entry) validate(key string) error {
 			message = "%q type must be %s"
 		}
 
-		return errors.Errorf(message, key, e.Type)
+		return errors.Errorf(message, key, kindName(e.Type))
 	}
 
-	if len(e.AuthorizedValues) > 0 {
-		if e.IsSlice {
-			// Accepted values for slices define the values that can be used inside the slice
-			// It doesn't represent the value of the slice itself (content and order)
-			length := v.Len()
-			for i := 0; i < length; i++ {
-				if !lo.Contains(e.AuthorizedValues, v.Index(i).Interface()) {
-					return errors.Errorf("%q elements must have one of the following values: %v", key, e.AuthorizedValues)
-				}
+	violations := e.violations(key)
+	if len(violations) > 0 {
+		return errors.Errorf("%s", strings.Join(violations, "\n\t- "))
+	}
+	return nil
+}
+
+// violations returns all the constraints violated by the value of this entry.
+func (e *Entry) violations(key string) []string {
+	if !e.IsSlice {
+		return e.checkConstraints(fmt.Sprintf("%q", key), e.Value)
+	}
+
+	// Constraints for slices apply to the values that can be used inside the slice
+	// They don't represent the value of the slice itself (content and order)
+	v := reflect.ValueOf(e.Value)
+	subject := fmt.Sprintf("%q elements", key)
+	violations := []string{}
+	length := v.Len()
+	for i := 0; i < length; i++ {
+		for _, violation := range e.checkConstraints(subject, v.Index(i).Interface()) {
+			if !lo.Contains(violations, violation) {
+				violations = append(violations, violation)
 			}
-		} else if !lo.Contains(e.AuthorizedValues, e.Value) {
-			return errors.Errorf("%q must have one of the following values: %v", key, e.AuthorizedValues)
 		}
 	}
-
-	return nil
+	return violations
 }
 
 func (e *Entry) tryConversion(kind reflect.Kind) bool {
+	if isCustomKind(e.Type) {
+		return e.convertCustomKind()
+	}
 	if !e.IsSlice && kind == reflect.Float64 && e.Type == reflect.Int {
 		intVal, ok := convertInt(e.Value.(float64))
 		if ok {
@@ -152,10 +210,6 @@ func (e *Entry) tryEnvVarConversion(key string) error {
 	if ok {
 		val, err := e.convertEnvVar(str, key)
 		if err == nil && val != nil {
-			if e.IsSlice {
-				return errors.Errorf("%q is a slice entry, it cannot be loaded from env", key)
-			}
-
 			e.Value = val
 		}
 		return err
@@ -172,26 +226,14 @@ func (e *Entry) convertEnvVar(str, key string) (any, error) {
 			return nil, errors.Errorf("%q: %q environment variable is not set", key, varName)
 		}
 
-		switch e.Type {
-		case reflect.Int:
-			if i, err := strconv.Atoi(value); err == nil {
-				return i, nil
-			}
-			return nil, errors.Errorf("%q could not be converted to int from environment variable %q of value %q", key, varName, value)
-		case reflect.Float64:
-			if f, err := strconv.ParseFloat(value, 64); err == nil {
-				return f, nil
-			}
-			return nil, errors.Errorf("%q could not be converted to float64 from environment variable %q of value %q", key, varName, value)
-		case reflect.Bool:
-			if b, err := strconv.ParseBool(value); err == nil {
-				return b, nil
+		val, err := e.convertString(value)
+		if err != nil {
+			if e.Secret {
+				value = Redacted
 			}
-			return nil, errors.Errorf("%q could not be converted to bool from environment variable %q of value %q", key, varName, value)
-		default:
-			// Keep value as string if type is not supported and let validation do its job
-			return value, nil
+			return nil, errors.Errorf("%q could not be converted to %s from environment variable %q of value %q", key, e.typeName(), varName, value)
 		}
+		return val, nil
 	}
 
 	return nil, nil
@@ -0,0 +1,193 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"goyave.dev/goyave/v5/util/errors"
+)
+
+const (
+	// DefaultEnvPrefix the default prefix of the environment variables overriding config entries.
+	DefaultEnvPrefix = "GOYAVE"
+
+	// DefaultEnvSeparator the default separator used in the name of the environment variables
+	// overriding config entries, between the prefix and each segment of the entry path.
+	DefaultEnvSeparator = "_"
+)
+
+// SetEnvPrefix defines how the names of the environment variables overriding config entries are
+// generated. The name of the variable is made of the prefix and of each segment of the entry's path converted
+// to upper snake case, joined with the given separator. For example, with prefix "APP" and separator "__",
+// "database.maxOpenConnections" is overridden by "APP__DATABASE__MAX_OPEN_CONNECTIONS".
+//
+// By default, the prefix is "GOYAVE" and the separator is "_" ("GOYAVE_SERVER_PORT").
+// An empty prefix disables the environment variable overrides.
+func SetEnvPrefix(prefix, separator string) {
+	defaultLoader.mu.Lock()
+	defer defaultLoader.mu.Unlock()
+	defaultLoader.envPrefix = prefix
+	defaultLoader.envSeparator = separator
+}
+
+// EnvVarName returns the name of the environment variable overriding the config entry
+// identified by the given dot-separated path. Returns an empty string if environment
+// variable overrides are disabled. See `SetEnvPrefix()`.
+func EnvVarName(key string) string {
+	defaultLoader.mu.RLock()
+	defer defaultLoader.mu.RUnlock()
+	return defaultLoader.envVarName(key)
+}
+
+func (l *loader) envVarName(key string) string {
+	if l.envPrefix == "" {
+		return ""
+	}
+	segments := strings.Split(key, ".")
+	for i, s := range segments {
+		segments[i] = toUpperSnakeCase(s)
+	}
+	return l.envPrefix + l.envSeparator + strings.Join(segments, l.envSeparator)
+}
+
+// toUpperSnakeCase converts a camel case config key to upper snake case
+// ("maxOpenConnections" -> "MAX_OPEN_CONNECTIONS").
+func toUpperSnakeCase(str string) string {
+	runes := []rune(str)
+	var b strings.Builder
+	b.Grow(len(str) + 4)
+	for i, r := range runes {
+		if r == '-' || r == ' ' {
+			b.WriteRune('_')
+			continue
+		}
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextIsLower) {
+				b.WriteRune('_')
+			}
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
+
+// applyEnvOverrides replaces the value of the entries of the given category (identified by the given
+// path) with the value of their matching environment variable, if it is set. The variable is recorded
+// as the source of the overridden entries.
+func (l *loader) applyEnvOverrides(category object, key string, sources map[string]string) error {
+	if l.envPrefix == "" {
+		return nil
+	}
+	message := ""
+	for k, entry := range category {
+		subKey := k
+		if key != "" {
+			subKey = key + "." + k
+		}
+		if subCategory, ok := entry.(object); ok {
+			if err := l.applyEnvOverrides(subCategory, subKey, sources); err != nil {
+				message += err.Error()
+			}
+			continue
+		}
+
+		varName := l.envVarName(subKey)
+		value, set := os.LookupEnv(varName)
+		if !set {
+			continue
+		}
+		e := entry.(*Entry)
+		sources[subKey] = SourceEnvPrefix + varName
+		if strings.HasPrefix(value, SecretScheme) {
+			// Secret references are resolved and converted later
+			e.Value = value
+			continue
+		}
+		v, err := e.convertString(value)
+		if err != nil {
+			reason := err.Error()
+			if e.Secret {
+				reason = Redacted
+			}
+			message += fmt.Sprintf("\n\t- %q could not be converted to %s from environment variable %q: %s", subKey, e.typeName(), varName, reason)
+			continue
+		}
+		e.Value = v
+	}
+	if message != "" {
+		return fmt.Errorf("%s", message)
+	}
+	return nil
+}
+
+// convertString converts a string, usually coming from an environment variable, to the type
+// of this entry. Slices can be represented either as comma-separated values or as a JSON array.
+// Unsupported types are kept as string so validation can do its job.
+func (e *Entry) convertString(str string) (any, error) {
+	if !e.IsSlice {
+		return e.convertElement(str)
+	}
+
+	trimmed := strings.TrimSpace(str)
+	if strings.HasPrefix(trimmed, "[") {
+		slice := []any{}
+		if err := json.Unmarshal([]byte(trimmed), &slice); err != nil {
+			return nil, err
+		}
+		return slice, nil
+	}
+
+	if trimmed == "" {
+		return []any{}, nil
+	}
+	parts := strings.Split(str, ",")
+	slice := make([]any, 0, len(parts))
+	for _, p := range parts {
+		v, err := e.convertElement(strings.TrimSpace(p))
+		if err != nil {
+			return nil, err
+		}
+		slice = append(slice, v)
+	}
+	return slice, nil
+}
+
+// convertElement converts the given string to the type of the entry's values
+// (or slice elements).
+func (e *Entry) convertElement(str string) (any, error) {
+	if isCustomKind(e.Type) {
+		v, ok := e.convertKindValue(str)
+		if !ok {
+			return nil, errors.Errorf("invalid %s %q", kindName(e.Type), str)
+		}
+		return v, nil
+	}
+	return convertScalar(str, e.Type)
+}
+
+func convertScalar(str string, kind reflect.Kind) (any, error) {
+	switch kind {
+	case reflect.Int:
+		return strconv.Atoi(str)
+	case reflect.Float64:
+		return strconv.ParseFloat(str, 64)
+	case reflect.Bool:
+		return strconv.ParseBool(str)
+	default:
+		return str, nil
+	}
+}
+
+func (e *Entry) typeName() string {
+	if e.IsSlice {
+		return "[]" + kindName(e.Type)
+	}
+	return kindName(e.Type)
+}
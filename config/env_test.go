@@ -0,0 +1,155 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToUpperSnakeCase(t *testing.T) {
+	cases := map[string]string{
+		"port":               "PORT",
+		"maxOpenConnections": "MAX_OPEN_CONNECTIONS",
+		"custom-entry":       "CUSTOM_ENTRY",
+		"JWTSecret":          "JWT_SECRET",
+		"http2Enabled":       "HTTP2_ENABLED",
+		"ALREADY_UPPER":      "ALREADY_UPPER",
+		"":                   "",
+	}
+	for input, want := range cases {
+		assert.Equal(t, want, toUpperSnakeCase(input), input)
+	}
+}
+
+func TestEnvVarName(t *testing.T) {
+	assert.Equal(t, "GOYAVE_SERVER_PORT", EnvVarName("server.port"))
+	assert.Equal(t, "GOYAVE_DATABASE_CONFIG_DRY_RUN", EnvVarName("database.config.dryRun"))
+
+	SetEnvPrefix("APP", "__")
+	t.Cleanup(func() {
+		SetEnvPrefix(DefaultEnvPrefix, DefaultEnvSeparator)
+	})
+	assert.Equal(t, "APP__DATABASE__HOST", EnvVarName("database.host"))
+	assert.Equal(t, "APP__DATABASE__MAX_OPEN_CONNECTIONS", EnvVarName("database.maxOpenConnections"))
+
+	SetEnvPrefix("", "")
+	assert.Empty(t, EnvVarName("database.host"))
+}
+
+func newEnvTestLoader() *loader {
+	l := &loader{
+		defaults:     object{},
+		envPrefix:    "TEST",
+		envSeparator: "__",
+	}
+	l.register("app.name", Entry{Value: "goyave", AuthorizedValues: []any{}, Type: reflect.String})
+	l.register("server.port", Entry{Value: 8080, AuthorizedValues: []any{}, Type: reflect.Int})
+	l.register("server.ratio", Entry{Value: 0.5, AuthorizedValues: []any{}, Type: reflect.Float64})
+	l.register("server.debug", Entry{Value: false, AuthorizedValues: []any{}, Type: reflect.Bool})
+	l.register("cors.origins", Entry{Value: []string{}, AuthorizedValues: []any{}, Type: reflect.String, IsSlice: true})
+	l.register("cors.ports", Entry{Value: []int{}, AuthorizedValues: []any{}, Type: reflect.Int, IsSlice: true})
+	l.register("cors.weights", Entry{Value: []float64{}, AuthorizedValues: []any{}, Type: reflect.Float64, IsSlice: true})
+	l.register("cors.flags", Entry{Value: []bool{}, AuthorizedValues: []any{}, Type: reflect.Bool, IsSlice: true})
+	l.register("auth.secret", Entry{Value: nil, AuthorizedValues: []any{}, Type: reflect.String, Required: true})
+	return l
+}
+
+func TestEnvOverrides(t *testing.T) {
+	t.Run("override", func(t *testing.T) {
+		l := newEnvTestLoader()
+		t.Setenv("TEST__APP__NAME", "from env")
+		t.Setenv("TEST__SERVER__PORT", "1234")
+		t.Setenv("TEST__SERVER__RATIO", "1.5")
+		t.Setenv("TEST__SERVER__DEBUG", "true")
+		t.Setenv("TEST__CORS__ORIGINS", "https://a.example.org, https://b.example.org")
+		t.Setenv("TEST__CORS__PORTS", "[80, 443]")
+		t.Setenv("TEST__CORS__WEIGHTS", "0.5,1")
+		t.Setenv("TEST__CORS__FLAGS", "")
+		t.Setenv("TEST__AUTH__SECRET", "secret")
+		t.Setenv("TEST__CUSTOM__ENTRY", "from env")
+
+		cfg, err := l.loadJSON(`{"server": {"port": 9000}, "custom": {"entry": "from file"}}`)
+		require.NoError(t, err)
+
+		assert.Equal(t, "from env", cfg.GetString("app.name"))
+		assert.Equal(t, 1234, cfg.GetInt("server.port"))
+		assert.InEpsilon(t, 1.5, cfg.GetFloat("server.ratio"), 0)
+		assert.True(t, cfg.GetBool("server.debug"))
+		assert.Equal(t, []string{"https://a.example.org", "https://b.example.org"}, cfg.GetStringSlice("cors.origins"))
+		assert.Equal(t, []int{80, 443}, cfg.GetIntSlice("cors.ports"))
+		assert.Equal(t, []float64{0.5, 1}, cfg.GetFloatSlice("cors.weights"))
+		assert.Equal(t, []bool{}, cfg.GetBoolSlice("cors.flags"))
+		assert.Equal(t, "secret", cfg.GetString("auth.secret"))
+		assert.Equal(t, "from env", cfg.GetString("custom.entry")) // Entries defined in the file can be overridden too
+	})
+
+	t.Run("no_file", func(t *testing.T) {
+		l := newEnvTestLoader()
+		t.Setenv("TEST__AUTH__SECRET", "secret")
+		cfg, err := l.load()
+		require.NoError(t, err)
+		assert.Equal(t, "secret", cfg.GetString("auth.secret"))
+
+		// Required entry not set
+		t.Setenv("TEST__AUTH__SECRET", "")
+		_, err = l.loadWithEnv(false)
+		require.Error(t, err)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		l := newEnvTestLoader()
+		l.envPrefix = ""
+		t.Setenv("__APP__NAME", "from env")
+		cfg, err := l.loadJSON(`{"auth": {"secret": "secret"}}`)
+		require.NoError(t, err)
+		assert.Equal(t, "goyave", cfg.GetString("app.name"))
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		l := newEnvTestLoader()
+		t.Setenv("TEST__AUTH__SECRET", "secret")
+		t.Setenv("TEST__SERVER__PORT", "abc")
+		cfg, err := l.load()
+		assert.Nil(t, cfg)
+		require.EqualError(t, err, "Config error: \n\t- \"server.port\" could not be converted to int from environment variable \"TEST__SERVER__PORT\": strconv.Atoi: parsing \"abc\": invalid syntax")
+
+		t.Setenv("TEST__SERVER__PORT", "1")
+		t.Setenv("TEST__CORS__PORTS", "1,a")
+		t.Setenv("TEST__CORS__WEIGHTS", "[1,")
+		cfg, err = l.load()
+		assert.Nil(t, cfg)
+		require.ErrorContains(t, err, "\"cors.ports\" could not be converted to []int from environment variable \"TEST__CORS__PORTS\"")
+		require.ErrorContains(t, err, "\"cors.weights\" could not be converted to []float64 from environment variable \"TEST__CORS__WEIGHTS\"")
+
+		t.Setenv("TEST__CORS__PORTS", `["a"]`)
+		t.Setenv("TEST__CORS__WEIGHTS", "1")
+		cfg, err = l.load()
+		assert.Nil(t, cfg)
+		require.EqualError(t, err, "Config error: \n\t- \"cors.ports\" must be a slice of int")
+	})
+
+	t.Run("placeholder_slice", func(t *testing.T) {
+		l := newEnvTestLoader()
+		l.envPrefix = ""
+		t.Setenv("TEST_ORIGINS", "a,b")
+		cfg, err := l.loadJSON(`{"cors": {"origins": "${TEST_ORIGINS}"}, "auth": {"secret": "secret"}}`)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a", "b"}, cfg.GetStringSlice("cors.origins"))
+	})
+
+	t.Run("LoadEnv", func(t *testing.T) {
+		t.Setenv("GOYAVE_SERVER_PORT", "1234")
+		cfg, err := LoadEnv()
+		require.NoError(t, err)
+		assert.Equal(t, 1234, cfg.GetInt("server.port"))
+
+		cfg, err = LoadJSON(`{"server": {"port": 9000}}`)
+		require.NoError(t, err)
+		assert.Equal(t, 1234, cfg.GetInt("server.port"))
+
+		// Not applied to the default config
+		assert.Equal(t, 8080, LoadDefault().GetInt("server.port"))
+	})
+}
@@ -0,0 +1,351 @@
+package config
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/samber/lo"
+	"goyave.dev/goyave/v5/util/errors"
+)
+
+// Entry kinds in addition to the `reflect.Kind` natively supported by config entries
+// (`reflect.String`, `reflect.Int`, `reflect.Float64` and `reflect.Bool`).
+const (
+	// KindDuration entries contain a `time.Duration`. Config files define them as strings
+	// in the format accepted by `time.ParseDuration()` ("30s", "1h30m", "250ms") or as
+	// numbers multiplied by `Entry.Unit`.
+	KindDuration reflect.Kind = 1000 + iota
+
+	// KindURL entries contain an absolute URL (with a scheme and a host) as a string.
+	KindURL
+
+	// KindHost entries contain a host name or an IP address (without port) as a string.
+	KindHost
+
+	// KindByteSize entries contain a size in bytes as an `int64`. Config files define them as
+	// strings with a unit ("512B", "10MiB", "1.5GB", see `ParseByteSize()`) or as numbers
+	// multiplied by `Entry.Unit`.
+	KindByteSize
+)
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	byteSizeType = reflect.TypeOf(int64(0))
+)
+
+// hostnameRegex matches RFC 1123 host names.
+var hostnameRegex = regexp.MustCompile(`^([a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)(\.[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*\.?$`)
+
+// patternCache the compiled `Entry.Pattern` regular expressions.
+var patternCache sync.Map
+
+func kindName(kind reflect.Kind) string {
+	switch kind {
+	case KindDuration:
+		return "duration"
+	case KindURL:
+		return "url"
+	case KindHost:
+		return "host"
+	case KindByteSize:
+		return "byte size"
+	default:
+		return kind.String()
+	}
+}
+
+func isCustomKind(kind reflect.Kind) bool {
+	return kind >= KindDuration
+}
+
+// storageType returns the Go type of the values (or slice elements) of the given custom kind.
+func storageType(kind reflect.Kind) reflect.Type {
+	switch kind {
+	case KindDuration:
+		return durationType
+	case KindByteSize:
+		return byteSizeType
+	default:
+		return reflect.TypeOf("")
+	}
+}
+
+// convertKindValue converts a single value to the storage type of the entry's custom kind.
+func (e *Entry) convertKindValue(value any) (any, bool) {
+	switch e.Type {
+	case KindDuration:
+		if d, ok := value.(time.Duration); ok {
+			return d, true
+		}
+		if str, ok := value.(string); ok {
+			if d, err := time.ParseDuration(str); err == nil {
+				return d, true
+			}
+		}
+		n, ok := e.applyUnit(value)
+		return time.Duration(n), ok
+	case KindByteSize:
+		if str, ok := value.(string); ok {
+			if _, err := strconv.ParseFloat(strings.TrimSpace(str), 64); err == nil {
+				// Numbers without unit are multiplied by the entry's unit
+				return e.applyUnit(str)
+			}
+			size, err := ParseByteSize(str)
+			return size, err == nil
+		}
+		if size, ok := value.(int64); ok {
+			return size, true
+		}
+		return e.applyUnit(value)
+	default:
+		str, ok := value.(string)
+		return str, ok
+	}
+}
+
+// applyUnit converts a number (or a string representing a number) to the base unit
+// of the entry by multiplying it with `Entry.Unit`. The result is rounded to the nearest
+// whole number.
+func (e *Entry) applyUnit(value any) (int64, bool) {
+	unit := e.Unit
+	if unit == 0 {
+		unit = 1
+	}
+	var n float64
+	switch v := value.(type) {
+	case int:
+		return int64(v) * unit, true
+	case float64:
+		n = v
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return 0, false
+		}
+		n = f
+	default:
+		return 0, false
+	}
+	result := math.Round(n * float64(unit))
+	if math.IsNaN(result) || math.Abs(result) >= math.MaxInt64 {
+		return 0, false
+	}
+	return int64(result), true
+}
+
+// byteSizeUnits the multiplier of each unit accepted by `ParseByteSize()`.
+var byteSizeUnits = map[string]int64{
+	"b":   1,
+	"kb":  1000,
+	"mb":  1000 * 1000,
+	"gb":  1000 * 1000 * 1000,
+	"tb":  1000 * 1000 * 1000 * 1000,
+	"kib": 1 << 10,
+	"mib": 1 << 20,
+	"gib": 1 << 30,
+	"tib": 1 << 40,
+}
+
+// ParseByteSize parses a size written as a number followed by a unit, such as "512B",
+// "10MiB" or "1.5 GB". Units are case-insensitive. Decimal units ("KB", "MB", "GB", "TB")
+// are powers of 1000 and binary units ("KiB", "MiB", "GiB", "TiB") are powers of 1024.
+// A number without unit is a number of bytes. The result is rounded to the nearest byte.
+func ParseByteSize(str string) (int64, error) {
+	str = strings.TrimSpace(str)
+	i := strings.IndexFunc(str, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	number, unit := str, "b"
+	if i != -1 {
+		number, unit = str[:i], strings.ToLower(strings.TrimSpace(str[i:]))
+	}
+	multiplier, ok := byteSizeUnits[unit]
+	if !ok {
+		return 0, errors.Errorf("invalid byte size %q: unknown unit %q", str, unit)
+	}
+	n, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, errors.Errorf("invalid byte size %q", str)
+	}
+	size := math.Round(n * float64(multiplier))
+	if size >= math.MaxInt64 {
+		return 0, errors.Errorf("invalid byte size %q", str)
+	}
+	return int64(size), nil
+}
+
+// formatByteSize returns the representation of the given size using the largest
+// binary unit dividing it.
+func formatByteSize(size int64) string {
+	units := []string{"TiB", "GiB", "MiB", "KiB"}
+	for _, unit := range units {
+		multiplier := byteSizeUnits[strings.ToLower(unit)]
+		if size != 0 && size%multiplier == 0 {
+			return strconv.FormatInt(size/multiplier, 10) + unit
+		}
+	}
+	return strconv.FormatInt(size, 10) + "B"
+}
+
+// convertCustomKind converts the value of an entry having a custom kind to its storage type.
+func (e *Entry) convertCustomKind() bool {
+	if !e.IsSlice {
+		v, ok := e.convertKindValue(e.Value)
+		if ok {
+			e.Value = v
+		}
+		return ok
+	}
+
+	list := reflect.ValueOf(e.Value)
+	if list.Kind() != reflect.Slice {
+		return false
+	}
+	slice := reflect.MakeSlice(reflect.SliceOf(storageType(e.Type)), list.Len(), list.Len())
+	for i := 0; i < list.Len(); i++ {
+		v, ok := e.convertKindValue(list.Index(i).Interface())
+		if !ok {
+			return false
+		}
+		slice.Index(i).Set(reflect.ValueOf(v))
+	}
+	e.Value = slice.Interface()
+	return true
+}
+
+// checkKind returns a violation message if the given value is not valid for the given
+// custom kind. The value must already be of the kind's storage type.
+func checkKind(kind reflect.Kind, value any) string {
+	switch kind {
+	case KindURL:
+		u, err := url.Parse(value.(string))
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return "be a valid absolute URL"
+		}
+	case KindHost:
+		str := value.(string)
+		if net.ParseIP(str) == nil && (len(str) > 253 || !hostnameRegex.MatchString(str)) {
+			return "be a valid host name or IP address"
+		}
+	}
+	return ""
+}
+
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if re, ok := patternCache.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	patternCache.Store(pattern, re)
+	return re, nil
+}
+
+// toFloat converts the given numeric value to float64. For strings, returns their length.
+func toFloat(value any) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	case time.Duration:
+		return float64(v), true
+	case string:
+		return float64(len([]rune(v))), true
+	default:
+		return 0, false
+	}
+}
+
+// boundValue converts a `Min` or `Max` bound to float64 so it can be compared with values
+// converted by `toFloat()`. Duration and byte size bounds are converted like values.
+func (e *Entry) boundValue(bound any) (float64, bool) {
+	if e.Type == KindDuration || e.Type == KindByteSize {
+		v, ok := e.convertKindValue(bound)
+		if !ok {
+			return 0, false
+		}
+		return toFloat(v)
+	}
+	if _, ok := bound.(string); ok {
+		return 0, false
+	}
+	return toFloat(bound)
+}
+
+// isLengthBound returns true if the `Min` and `Max` bounds of the entry apply to the
+// length of its values instead of the values themselves.
+func (e *Entry) isLengthBound() bool {
+	return e.Type == reflect.String || e.Type == KindURL || e.Type == KindHost
+}
+
+// checkConstraints returns the violations of the authorized values, bounds, pattern and
+// kind constraints by the given value. `subject` identifies the value in the messages: the quoted
+// key of the entry, or the quoted key followed by "elements" for slices.
+func (e *Entry) checkConstraints(subject string, value any) []string {
+	violations := []string{}
+	if len(e.AuthorizedValues) > 0 && !lo.Contains(e.AuthorizedValues, value) {
+		violations = append(violations, fmt.Sprintf("%s must have one of the following values: %v", subject, e.AuthorizedValues))
+	}
+
+	if e.Min != nil || e.Max != nil {
+		violations = append(violations, e.checkBounds(subject, value)...)
+	}
+
+	if e.Pattern != "" {
+		if str, ok := value.(string); ok {
+			re, err := compilePattern(e.Pattern)
+			if err != nil {
+				violations = append(violations, fmt.Sprintf("%s cannot be validated: invalid pattern: %s", subject, err.Error()))
+			} else if !re.MatchString(str) {
+				violations = append(violations, fmt.Sprintf("%s must match the pattern %q", subject, e.Pattern))
+			}
+		}
+	}
+
+	if isCustomKind(e.Type) {
+		if v := checkKind(e.Type, value); v != "" {
+			violations = append(violations, subject+" must "+v)
+		}
+	}
+	return violations
+}
+
+func (e *Entry) checkBounds(subject string, value any) []string {
+	violations := []string{}
+	v, ok := toFloat(value)
+	if !ok {
+		return violations
+	}
+	must := "must be"
+	if e.isLengthBound() {
+		must = "must have a length"
+	}
+	if e.Min != nil {
+		if min, ok := e.boundValue(e.Min); !ok {
+			violations = append(violations, fmt.Sprintf("%s cannot be validated: invalid minimum %v", subject, e.Min))
+		} else if v < min {
+			violations = append(violations, fmt.Sprintf("%s %s greater than or equal to %v", subject, must, e.Min))
+		}
+	}
+	if e.Max != nil {
+		if max, ok := e.boundValue(e.Max); !ok {
+			violations = append(violations, fmt.Sprintf("%s cannot be validated: invalid maximum %v", subject, e.Max))
+		} else if v > max {
+			violations = append(violations, fmt.Sprintf("%s %s less than or equal to %v", subject, must, e.Max))
+		}
+	}
+	return violations
+}
@@ -0,0 +1,199 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEntryConstraints(t *testing.T) {
+	cases := []struct {
+		value any
+		entry *Entry
+		want  any
+		desc  string
+		err   string
+	}{
+		{desc: "duration", entry: &Entry{Value: "1m30s", AuthorizedValues: []any{}, Type: KindDuration}, want: 90 * time.Second},
+		{desc: "duration_value", entry: &Entry{Value: time.Second, AuthorizedValues: []any{}, Type: KindDuration}, want: time.Second},
+		{desc: "duration_slice", entry: &Entry{Value: []any{"1s", "2ms"}, AuthorizedValues: []any{}, Type: KindDuration, IsSlice: true}, want: []time.Duration{time.Second, 2 * time.Millisecond}},
+		{desc: "duration_invalid", entry: &Entry{Value: "abc", AuthorizedValues: []any{}, Type: KindDuration}, err: "\"key\" type must be duration"},
+		{desc: "duration_invalid_type", entry: &Entry{Value: true, AuthorizedValues: []any{}, Type: KindDuration}, err: "\"key\" type must be duration"},
+		{desc: "duration_slice_invalid", entry: &Entry{Value: []any{"1s", "abc"}, AuthorizedValues: []any{}, Type: KindDuration, IsSlice: true}, err: "\"key\" must be a slice of duration"},
+		{desc: "duration_bounds", entry: &Entry{Value: "1ms", Min: "1s", Max: "1m", AuthorizedValues: []any{}, Type: KindDuration}, err: "\"key\" must be greater than or equal to 1s"},
+		{desc: "duration_bounds_value", entry: &Entry{Value: "2m", Min: time.Second, Max: time.Minute, AuthorizedValues: []any{}, Type: KindDuration}, err: "\"key\" must be less than or equal to 1m0s"},
+		{desc: "duration_unit", entry: &Entry{Value: 10, AuthorizedValues: []any{}, Type: KindDuration, Unit: int64(time.Second)}, want: 10 * time.Second},
+		{desc: "duration_unit_float", entry: &Entry{Value: 1.5, AuthorizedValues: []any{}, Type: KindDuration, Unit: int64(time.Second)}, want: 1500 * time.Millisecond},
+		{desc: "duration_unit_rounded", entry: &Entry{Value: 1.0000000004, AuthorizedValues: []any{}, Type: KindDuration, Unit: int64(time.Second)}, want: time.Second},
+		{desc: "duration_unit_string", entry: &Entry{Value: "250", AuthorizedValues: []any{}, Type: KindDuration, Unit: int64(time.Millisecond)}, want: 250 * time.Millisecond},
+		{desc: "duration_unit_bound", entry: &Entry{Value: "500ms", Min: 1, AuthorizedValues: []any{}, Type: KindDuration, Unit: int64(time.Second)}, err: "\"key\" must be greater than or equal to 1"},
+		{desc: "byte_size", entry: &Entry{Value: "10MiB", AuthorizedValues: []any{}, Type: KindByteSize}, want: int64(10 << 20)},
+		{desc: "byte_size_value", entry: &Entry{Value: int64(512), AuthorizedValues: []any{}, Type: KindByteSize, Unit: 1 << 20}, want: int64(512)},
+		{desc: "byte_size_unit", entry: &Entry{Value: 2.5, AuthorizedValues: []any{}, Type: KindByteSize, Unit: 1 << 20}, want: int64(2.5 * (1 << 20))},
+		{desc: "byte_size_slice", entry: &Entry{Value: []any{"1KB", 2.0}, AuthorizedValues: []any{}, Type: KindByteSize, Unit: 1 << 10, IsSlice: true}, want: []int64{1000, 2048}},
+		{desc: "byte_size_invalid", entry: &Entry{Value: "10 potatoes", AuthorizedValues: []any{}, Type: KindByteSize}, err: "\"key\" type must be byte size"},
+		{desc: "byte_size_rounded", entry: &Entry{Value: 0.1, AuthorizedValues: []any{}, Type: KindByteSize, Unit: 1 << 20}, want: int64(104858)},
+		{desc: "byte_size_invalid_type", entry: &Entry{Value: true, AuthorizedValues: []any{}, Type: KindByteSize}, err: "\"key\" type must be byte size"},
+		{desc: "byte_size_bounds", entry: &Entry{Value: "2MiB", Min: "1KiB", Max: "1MiB", AuthorizedValues: []any{}, Type: KindByteSize}, err: "\"key\" must be less than or equal to 1MiB"},
+		{desc: "url", entry: &Entry{Value: "https://example.org/path", AuthorizedValues: []any{}, Type: KindURL}, want: "https://example.org/path"},
+		{desc: "url_invalid", entry: &Entry{Value: "/path", AuthorizedValues: []any{}, Type: KindURL}, err: "\"key\" must be a valid absolute URL"},
+		{desc: "url_slice_invalid", entry: &Entry{Value: []any{"https://example.org", "example"}, AuthorizedValues: []any{}, Type: KindURL, IsSlice: true}, err: "\"key\" elements must be a valid absolute URL"},
+		{desc: "host", entry: &Entry{Value: "db.example.org", AuthorizedValues: []any{}, Type: KindHost}, want: "db.example.org"},
+		{desc: "host_ip", entry: &Entry{Value: "::1", AuthorizedValues: []any{}, Type: KindHost}, want: "::1"},
+		{desc: "host_invalid", entry: &Entry{Value: "example.org:8080", AuthorizedValues: []any{}, Type: KindHost}, err: "\"key\" must be a valid host name or IP address"},
+		{desc: "host_invalid_type", entry: &Entry{Value: 1, AuthorizedValues: []any{}, Type: KindHost}, err: "\"key\" type must be host"},
+		{desc: "int_bounds", entry: &Entry{Value: 8080, Min: 0, Max: 65535, AuthorizedValues: []any{}, Type: reflect.Int}, want: 8080},
+		{desc: "int_min", entry: &Entry{Value: -1, Min: 0, Max: 65535, AuthorizedValues: []any{}, Type: reflect.Int}, err: "\"key\" must be greater than or equal to 0"},
+		{desc: "int_max", entry: &Entry{Value: 70000.0, Min: 0, Max: 65535, AuthorizedValues: []any{}, Type: reflect.Int}, err: "\"key\" must be less than or equal to 65535"},
+		{desc: "float_bounds", entry: &Entry{Value: 0.5, Min: 0.1, Max: 1, AuthorizedValues: []any{}, Type: reflect.Float64}, want: 0.5},
+		{desc: "float_min", entry: &Entry{Value: 0.05, Min: 0.1, Max: 1, AuthorizedValues: []any{}, Type: reflect.Float64}, err: "\"key\" must be greater than or equal to 0.1"},
+		{desc: "slice_bounds", entry: &Entry{Value: []any{1.0, 20.0, 30.0}, Min: 0, Max: 10, AuthorizedValues: []any{}, Type: reflect.Int, IsSlice: true}, err: "\"key\" elements must be less than or equal to 10"},
+		{desc: "string_length", entry: &Entry{Value: "héllo", Min: 1, Max: 5, AuthorizedValues: []any{}, Type: reflect.String}, want: "héllo"},
+		{desc: "string_too_long", entry: &Entry{Value: "hello world", Min: 1, Max: 5, AuthorizedValues: []any{}, Type: reflect.String}, err: "\"key\" must have a length less than or equal to 5"},
+		{desc: "pattern", entry: &Entry{Value: "abc", Pattern: "^[a-z]+$", AuthorizedValues: []any{}, Type: reflect.String}, want: "abc"},
+		{desc: "pattern_mismatch", entry: &Entry{Value: "ABC", Pattern: "^[a-z]+$", AuthorizedValues: []any{}, Type: reflect.String}, err: "\"key\" must match the pattern \"^[a-z]+$\""},
+		{desc: "pattern_slice", entry: &Entry{Value: []any{"a", "1", "2"}, Pattern: "^[a-z]+$", AuthorizedValues: []any{}, Type: reflect.String, IsSlice: true}, err: "\"key\" elements must match the pattern \"^[a-z]+$\""},
+		{desc: "invalid_pattern", entry: &Entry{Value: "abc", Pattern: "[", AuthorizedValues: []any{}, Type: reflect.String}, err: "\"key\" cannot be validated: invalid pattern: error parsing regexp: missing closing ]: `[`"},
+		{desc: "invalid_bound", entry: &Entry{Value: 1, Min: "a", Max: []int{}, AuthorizedValues: []any{}, Type: reflect.Int}, err: "\"key\" cannot be validated: invalid minimum a\n\t- \"key\" cannot be validated: invalid maximum []"},
+		{
+			desc:  "aggregated",
+			entry: &Entry{Value: "ABCDEF", Max: 5, Pattern: "^[a-z]+$", AuthorizedValues: []any{"abc", "def"}, Type: reflect.String},
+			err:   "\"key\" must have one of the following values: [abc def]\n\t- \"key\" must have a length less than or equal to 5\n\t- \"key\" must match the pattern \"^[a-z]+$\"",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			err := c.entry.validate("key")
+			if c.err != "" {
+				require.Error(t, err)
+				assert.Equal(t, c.err, err.Error())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, c.want, c.entry.Value)
+		})
+	}
+}
+
+func TestCustomKinds(t *testing.T) {
+	l := newTestLoader()
+	l.register("custom.timeout", Entry{Value: time.Minute, Min: "1s", AuthorizedValues: []any{}, Type: KindDuration, Required: true})
+	l.register("custom.endpoint", Entry{Value: nil, AuthorizedValues: []any{}, Type: KindURL})
+	l.register("custom.hosts", Entry{Value: []string{}, AuthorizedValues: []any{}, Type: KindHost, IsSlice: true})
+
+	t.Run("load", func(t *testing.T) {
+		t.Setenv("GOYAVE_CUSTOM_HOSTS", "127.0.0.1,db.example.org")
+		cfg, err := l.loadJSON(`{"custom": {"timeout": "30s", "endpoint": "https://example.org"}}`)
+		require.NoError(t, err)
+		assert.Equal(t, 30*time.Second, cfg.Get("custom.timeout"))
+		assert.Equal(t, "https://example.org", cfg.GetString("custom.endpoint"))
+		assert.Equal(t, []string{"127.0.0.1", "db.example.org"}, cfg.GetStringSlice("custom.hosts"))
+	})
+
+	t.Run("env", func(t *testing.T) {
+		t.Setenv("GOYAVE_CUSTOM_TIMEOUT", "2m")
+		cfg, err := l.loadJSON(`{}`)
+		require.NoError(t, err)
+		assert.Equal(t, 2*time.Minute, cfg.Get("custom.timeout"))
+
+		t.Setenv("GOYAVE_CUSTOM_TIMEOUT", "abc")
+		_, err = l.loadJSON(`{}`)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "\"custom.timeout\" could not be converted to duration from environment variable \"GOYAVE_CUSTOM_TIMEOUT\"")
+	})
+
+	t.Run("all_violations", func(t *testing.T) {
+		cfg, err := l.loadJSON(`{
+			"server": {"port": -1},
+			"custom": {"timeout": "10ms", "endpoint": "example.org", "hosts": ["db:5432"]}
+		}`)
+		assert.Nil(t, cfg)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "\n\t- \"server.port\" must be greater than or equal to 0")
+		assert.Contains(t, err.Error(), "\n\t- \"custom.timeout\" must be greater than or equal to 1s")
+		assert.Contains(t, err.Error(), "\n\t- \"custom.endpoint\" must be a valid absolute URL")
+		assert.Contains(t, err.Error(), "\n\t- \"custom.hosts\" elements must be a valid host name or IP address")
+	})
+}
+
+func TestParseByteSize(t *testing.T) {
+	cases := []struct {
+		str  string
+		err  string
+		want int64
+	}{
+		{str: "512", want: 512},
+		{str: "512B", want: 512},
+		{str: "1KB", want: 1000},
+		{str: "1kb", want: 1000},
+		{str: "1KiB", want: 1024},
+		{str: "10MiB", want: 10 << 20},
+		{str: "1.5 GB", want: 1500 * 1000 * 1000},
+		{str: "2GiB", want: 2 << 30},
+		{str: "1TiB", want: 1 << 40},
+		{str: " 3 MB ", want: 3 * 1000 * 1000},
+		{str: "10 potatoes", err: "invalid byte size \"10 potatoes\": unknown unit \"potatoes\""},
+		{str: "MiB", err: "invalid byte size \"MiB\""},
+		{str: "1.2.3MiB", err: "invalid byte size \"1.2.3MiB\""},
+		{str: "0.1MiB", want: 104858},
+		{str: "0.4B", want: 0},
+	}
+	for _, c := range cases {
+		t.Run(c.str, func(t *testing.T) {
+			size, err := ParseByteSize(c.str)
+			if c.err != "" {
+				require.Error(t, err)
+				assert.Equal(t, c.err, err.Error())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, c.want, size)
+		})
+	}
+}
+
+func TestFormatByteSize(t *testing.T) {
+	assert.Equal(t, "0B", formatByteSize(0))
+	assert.Equal(t, "1000B", formatByteSize(1000))
+	assert.Equal(t, "1KiB", formatByteSize(1024))
+	assert.Equal(t, "10MiB", formatByteSize(10<<20))
+	assert.Equal(t, "1536KiB", formatByteSize(1536<<10))
+	assert.Equal(t, "3GiB", formatByteSize(3<<30))
+	assert.Equal(t, "2TiB", formatByteSize(2<<40))
+}
+
+func TestLegacyUnits(t *testing.T) {
+	cfg, err := newTestLoader().loadJSON(`{
+		"server": {"writeTimeout": 5, "readTimeout": "1m", "maxUploadSize": 20},
+		"database": {"defaultReadQueryTimeout": 1500, "maxLifetime": "1h"}
+	}`)
+	require.NoError(t, err)
+	assert.Equal(t, 5*time.Second, cfg.GetDuration("server.writeTimeout"))
+	assert.Equal(t, time.Minute, cfg.GetDuration("server.readTimeout"))
+	assert.Equal(t, 20*time.Second, cfg.GetDuration("server.idleTimeout"))
+	assert.Equal(t, int64(20<<20), cfg.GetByteSize("server.maxUploadSize"))
+	assert.Equal(t, 1500*time.Millisecond, cfg.GetDuration("database.defaultReadQueryTimeout"))
+	assert.Equal(t, time.Hour, cfg.GetDuration("database.maxLifetime"))
+
+	cfg.Set("server.maxUploadSize", "1GiB")
+	assert.Equal(t, int64(1<<30), cfg.GetByteSize("server.maxUploadSize"))
+	cfg.Set("server.writeTimeout", 30)
+	assert.Equal(t, 30*time.Second, cfg.GetDuration("server.writeTimeout"))
+	cfg.Set("server.writeTimeout", 2*time.Minute)
+	assert.Equal(t, 2*time.Minute, cfg.GetDuration("server.writeTimeout"))
+
+	t.Setenv("GOYAVE_SERVER_MAX_UPLOAD_SIZE", "5")
+	t.Setenv("GOYAVE_SERVER_IDLE_TIMEOUT", "90s")
+	cfg, err = newTestLoader().loadJSON(`{}`)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5<<20), cfg.GetByteSize("server.maxUploadSize"))
+	assert.Equal(t, 90*time.Second, cfg.GetDuration("server.idleTimeout"))
+
+	_, err = newTestLoader().loadJSON(`{"server": {"writeTimeout": -1}}`)
+	require.Error(t, err)
+	assert.Equal(t, "Config error: \n\t- \"server.writeTimeout\" must be greater than or equal to 0", err.Error())
+}
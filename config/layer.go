@@ -0,0 +1,206 @@
+package config
+
+import (
+	stderrors "errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+
+	"goyave.dev/goyave/v5/util/errors"
+)
+
+const (
+	// SourceDefault the source of the entries that kept their default value.
+	SourceDefault = "default"
+
+	// SourceRuntime the source of the entries modified with `Config.Set()`.
+	SourceRuntime = "runtime"
+
+	// SourceEnvPrefix the prefix of the source of the entries overridden by an environment
+	// variable. The source is followed by the name of the variable ("env:GOYAVE_SERVER_PORT").
+	SourceEnvPrefix = "env:"
+
+	// includeKey the root-level key of the include directive.
+	includeKey = "include"
+)
+
+// layer a single source of configuration values. Layers are merged in order,
+// each layer overriding the values of the previous ones.
+type layer struct {
+	fs   fs.FS
+	read func() (object, error)
+
+	// remote the remote source read by this layer, if any.
+	remote *RemoteSource
+
+	// name identifies the layer in the source of the entries.
+	name string
+
+	// dir the directory from which the files included by this layer are resolved.
+	dir string
+
+	// file true if the layer reads a file, which can be watched for changes.
+	file bool
+}
+
+// loadResult the configuration resulting from the merge of layers.
+type loadResult struct {
+	config  object
+	sources map[string]string
+
+	// files the file layers that have been merged, including the included files
+	// and the optional files that don't exist.
+	files []*layer
+}
+
+// fileLayer returns a layer reading the given config file. The format of the file is detected using
+// its extension. If `optional` is true and the file doesn't exist, the layer is empty.
+func fileLayer(filesystem fs.FS, file string, optional bool) *layer {
+	return &layer{
+		fs:   filesystem,
+		name: file,
+		dir:  path.Dir(file),
+		file: true,
+		read: func() (object, error) {
+			if optional {
+				if _, err := fs.Stat(filesystem, file); stderrors.Is(err, fs.ErrNotExist) {
+					return nil, nil
+				}
+			}
+			return readConfigFile(filesystem, file)
+		},
+	}
+}
+
+// stringLayer returns a layer decoding the given raw config. Included files are resolved
+// from the given file system, relative to its root.
+func stringLayer(filesystem fs.FS, cfg, format string, decode decodeFunc) *layer {
+	return &layer{
+		fs:   filesystem,
+		name: "<" + format + ">",
+		dir:  ".",
+		read: func() (object, error) {
+			return decode(strings.NewReader(cfg))
+		},
+	}
+}
+
+// profileLayers returns the layers used by `Load()`:
+//   - the base file "config.*", required if "GOYAVE_ENV" is not set, optional otherwise
+//   - the environment profile file "config.<GOYAVE_ENV>.*", required if "GOYAVE_ENV" is set
+//   - the local override file "config.local.*", optional and ignored in the "test" environment
+func profileLayers(filesystem fs.FS) []*layer {
+	env := strings.ToLower(os.Getenv("GOYAVE_ENV"))
+	hasProfile := env != "local" && env != "localhost" && env != ""
+
+	layers := []*layer{fileLayer(filesystem, findConfigFile(filesystem, "config"), hasProfile)}
+	if hasProfile {
+		layers = append(layers, fileLayer(filesystem, findConfigFile(filesystem, "config."+env), false))
+	}
+	if env != "test" {
+		layers = append(layers, fileLayer(filesystem, findConfigFile(filesystem, "config.local"), true))
+	}
+	return layers
+}
+
+// findConfigFile returns the path of the first existing file having the given name
+// and one of the supported extensions. If none exist, returns the name with the
+// ".json" extension.
+func findConfigFile(filesystem fs.FS, name string) string {
+	for _, ext := range extensions {
+		if _, err := fs.Stat(filesystem, name+ext); err == nil {
+			return name + ext
+		}
+	}
+	return name + ".json"
+}
+
+func readConfigFile(filesystem fs.FS, file string) (o object, err error) {
+	var configFile fs.File
+	configFile, err = filesystem.Open(file)
+
+	if err == nil {
+		defer func() {
+			e := configFile.Close()
+			if err == nil && e != nil {
+				err = errors.New(e)
+			}
+		}()
+		o, err = decoderFor(file)(configFile)
+		err = errors.New(err)
+	} else {
+		err = errors.New(err)
+	}
+
+	return
+}
+
+// merge the given layer and the files it includes into the given result.
+// The included files are merged first so the including layer can override their values.
+// `chain` contains the names of the layers currently being merged and is used to detect
+// include cycles.
+func (l *loader) merge(result *loadResult, ly *layer, chain []string) error {
+	if ly.file {
+		result.files = append(result.files, ly)
+	}
+	conf, err := ly.read()
+	if err != nil {
+		return err
+	}
+	if conf == nil {
+		return nil
+	}
+
+	includes, err := extractIncludes(conf)
+	if err != nil {
+		return fmt.Errorf("%s: %w", ly.name, err)
+	}
+	if len(includes) > 0 && ly.fs == nil {
+		return fmt.Errorf("%s: %q is not supported in remote sources", ly.name, includeKey)
+	}
+	chain = append(chain, ly.name)
+	for _, include := range includes {
+		if !path.IsAbs(include) {
+			include = path.Join(ly.dir, include)
+		}
+		for _, name := range chain {
+			if name == include {
+				return fmt.Errorf("include cycle detected: %s -> %s", strings.Join(chain, " -> "), include)
+			}
+		}
+		if err := l.merge(result, fileLayer(ly.fs, include, false), chain); err != nil {
+			return err
+		}
+	}
+
+	return override(conf, result.config, ly.name, "", result.sources)
+}
+
+// extractIncludes removes the include directive from the given raw config and
+// returns the paths of the files to include. The directive can either be a single
+// path or an array of paths.
+func extractIncludes(conf object) ([]string, error) {
+	value, ok := conf[includeKey]
+	if !ok {
+		return nil, nil
+	}
+	delete(conf, includeKey)
+	switch v := value.(type) {
+	case string:
+		return []string{v}, nil
+	case []any:
+		includes := make([]string, 0, len(v))
+		for _, include := range v {
+			str, ok := include.(string)
+			if !ok {
+				return nil, fmt.Errorf("%q must be a string or an array of strings", includeKey)
+			}
+			includes = append(includes, str)
+		}
+		return includes, nil
+	default:
+		return nil, fmt.Errorf("%q must be a string or an array of strings", includeKey)
+	}
+}
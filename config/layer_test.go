@@ -0,0 +1,221 @@
+package config
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLoader() *loader {
+	defaultLoader.mu.Lock()
+	defer defaultLoader.mu.Unlock()
+	l := &loader{
+		defaults:        make(object, len(defaultLoader.defaults)),
+		secretProviders: defaultSecretProviders(),
+		envPrefix:       DefaultEnvPrefix,
+		envSeparator:    DefaultEnvSeparator,
+		validators:      append([]Validator{}, defaultLoader.validators...),
+	}
+	loadDefaults(defaultLoader.defaults, l.defaults)
+	return l
+}
+
+func TestFindConfigFile(t *testing.T) {
+	fs := fstest.MapFS{
+		"config.staging.yml":  &fstest.MapFile{},
+		"config.preprod.toml": &fstest.MapFile{},
+		"config.preprod.yaml": &fstest.MapFile{},
+		"config.dev.json":     &fstest.MapFile{},
+		"config.dev.toml":     &fstest.MapFile{},
+	}
+
+	assert.Equal(t, "config.json", findConfigFile(fs, "config"))
+	assert.Equal(t, "config.production.json", findConfigFile(fs, "config.production"))
+	assert.Equal(t, "config.staging.yml", findConfigFile(fs, "config.staging"))
+	assert.Equal(t, "config.preprod.yaml", findConfigFile(fs, "config.preprod"))
+	assert.Equal(t, "config.dev.json", findConfigFile(fs, "config.dev"))
+}
+
+func TestProfileLayers(t *testing.T) {
+	fs := fstest.MapFS{
+		"config.yml":         &fstest.MapFile{},
+		"config.staging.yml": &fstest.MapFile{},
+		"config.local.toml":  &fstest.MapFile{},
+	}
+
+	names := func(layers []*layer) []string {
+		n := make([]string, 0, len(layers))
+		for _, l := range layers {
+			n = append(n, l.name)
+		}
+		return n
+	}
+
+	cases := []struct {
+		env  string
+		want []string
+	}{
+		{env: "", want: []string{"config.yml", "config.local.toml"}},
+		{env: "localhost", want: []string{"config.yml", "config.local.toml"}},
+		{env: "local", want: []string{"config.yml", "config.local.toml"}},
+		{env: "staging", want: []string{"config.yml", "config.staging.yml", "config.local.toml"}},
+		{env: "production", want: []string{"config.yml", "config.production.json", "config.local.toml"}},
+		{env: "test", want: []string{"config.yml", "config.test.json"}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.env, func(t *testing.T) {
+			t.Setenv("GOYAVE_ENV", c.env)
+			assert.Equal(t, c.want, names(profileLayers(fs)))
+		})
+	}
+}
+
+func TestLayers(t *testing.T) {
+	fs := fstest.MapFS{
+		"config.json": &fstest.MapFile{Data: []byte(`{
+			"app": {"name": "base", "environment": "base"},
+			"server": {"port": 1234},
+			"custom": {"entry": "base", "other": "base"}
+		}`)},
+		"config.staging.yml": &fstest.MapFile{Data: []byte(`
+app:
+  environment: staging
+custom:
+  entry: staging
+`)},
+		"config.local.toml": &fstest.MapFile{Data: []byte(`
+[custom]
+entry = "local"
+`)},
+	}
+
+	t.Run("profiles", func(t *testing.T) {
+		t.Setenv("GOYAVE_ENV", "staging")
+		t.Setenv("GOYAVE_SERVER_PORT", "8080")
+		cfg, err := newTestLoader().load(profileLayers(fs)...)
+		require.NoError(t, err)
+
+		assert.Equal(t, "base", cfg.GetString("app.name"))
+		assert.Equal(t, "staging", cfg.GetString("app.environment"))
+		assert.Equal(t, "local", cfg.GetString("custom.entry"))
+		assert.Equal(t, "base", cfg.GetString("custom.other"))
+		assert.Equal(t, 8080, cfg.GetInt("server.port"))
+
+		assert.Equal(t, "config.json", cfg.Source("app.name"))
+		assert.Equal(t, "config.staging.yml", cfg.Source("app.environment"))
+		assert.Equal(t, "config.local.toml", cfg.Source("custom.entry"))
+		assert.Equal(t, "config.json", cfg.Source("custom.other"))
+		assert.Equal(t, "env:GOYAVE_SERVER_PORT", cfg.Source("server.port"))
+		assert.Equal(t, SourceDefault, cfg.Source("server.host"))
+		assert.Empty(t, cfg.Source("custom"))
+		assert.Empty(t, cfg.Source("notanentry"))
+
+		cfg.Set("custom.entry", "runtime")
+		cfg.Set("custom.new", "runtime")
+		assert.Equal(t, SourceRuntime, cfg.Source("custom.entry"))
+		assert.Equal(t, SourceRuntime, cfg.Source("custom.new"))
+	})
+
+	t.Run("base_optional_with_profile", func(t *testing.T) {
+		t.Setenv("GOYAVE_ENV", "staging")
+		fs := fstest.MapFS{
+			"config.staging.json": &fstest.MapFile{Data: []byte(`{"app": {"name": "staging"}}`)},
+		}
+		cfg, err := newTestLoader().load(profileLayers(fs)...)
+		require.NoError(t, err)
+		assert.Equal(t, "staging", cfg.GetString("app.name"))
+	})
+
+	t.Run("base_required_without_profile", func(t *testing.T) {
+		t.Setenv("GOYAVE_ENV", "")
+		cfg, err := newTestLoader().load(profileLayers(fstest.MapFS{})...)
+		assert.Nil(t, cfg)
+		require.Error(t, err)
+	})
+
+	t.Run("missing_profile", func(t *testing.T) {
+		t.Setenv("GOYAVE_ENV", "production")
+		cfg, err := newTestLoader().load(profileLayers(fs)...)
+		assert.Nil(t, cfg)
+		require.Error(t, err)
+	})
+
+	t.Run("local_ignored_in_test_env", func(t *testing.T) {
+		t.Setenv("GOYAVE_ENV", "test")
+		fs := fstest.MapFS{
+			"config.test.json":  &fstest.MapFile{Data: []byte(`{"custom": {"entry": "test"}}`)},
+			"config.local.json": &fstest.MapFile{Data: []byte(`{"custom": {"entry": "local"}}`)},
+		}
+		cfg, err := newTestLoader().load(profileLayers(fs)...)
+		require.NoError(t, err)
+		assert.Equal(t, "test", cfg.GetString("custom.entry"))
+	})
+}
+
+func TestInclude(t *testing.T) {
+	fs := fstest.MapFS{
+		"config.json": &fstest.MapFile{Data: []byte(`{
+			"include": ["conf/database.yml", "conf/server.json"],
+			"custom": {"entry": "root"}
+		}`)},
+		"conf/database.yml": &fstest.MapFile{Data: []byte(`
+include: shared/common.json
+database:
+  name: included
+`)},
+		"conf/server.json":         &fstest.MapFile{Data: []byte(`{"server": {"port": 1234}, "custom": {"entry": "server"}}`)},
+		"conf/shared/common.json":  &fstest.MapFile{Data: []byte(`{"custom": {"common": "common"}, "database": {"name": "common"}}`)},
+		"cycle.json":               &fstest.MapFile{Data: []byte(`{"include": "cycle/a.json"}`)},
+		"cycle/a.json":             &fstest.MapFile{Data: []byte(`{"include": "b.json"}`)},
+		"cycle/b.json":             &fstest.MapFile{Data: []byte(`{"include": "../cycle.json"}`)},
+		"invalid.json":             &fstest.MapFile{Data: []byte(`{"include": 123}`)},
+		"invalid_array.json":       &fstest.MapFile{Data: []byte(`{"include": ["a.json", 123]}`)},
+		"missing.json":             &fstest.MapFile{Data: []byte(`{"include": "notafile.json"}`)},
+		"conf/shared/invalid.json": &fstest.MapFile{Data: []byte(`{"unclosed":`)},
+	}
+
+	t.Run("include", func(t *testing.T) {
+		cfg, err := newTestLoader().load(fileLayer(fs, "config.json", false))
+		require.NoError(t, err)
+
+		assert.Equal(t, "root", cfg.GetString("custom.entry"))
+		assert.Equal(t, "common", cfg.GetString("custom.common"))
+		assert.Equal(t, "included", cfg.GetString("database.name"))
+		assert.Equal(t, 1234, cfg.GetInt("server.port"))
+		assert.False(t, cfg.Has("include"))
+
+		assert.Equal(t, "config.json", cfg.Source("custom.entry"))
+		assert.Equal(t, "conf/shared/common.json", cfg.Source("custom.common"))
+		assert.Equal(t, "conf/database.yml", cfg.Source("database.name"))
+		assert.Equal(t, "conf/server.json", cfg.Source("server.port"))
+	})
+
+	t.Run("string_layer", func(t *testing.T) {
+		cfg, err := newTestLoader().load(stringLayer(fs, `{"include": "conf/server.json", "app": {"name": "json"}}`, "json", decodeJSON))
+		require.NoError(t, err)
+		assert.Equal(t, 1234, cfg.GetInt("server.port"))
+		assert.Equal(t, "conf/server.json", cfg.Source("server.port"))
+		assert.Equal(t, "<json>", cfg.Source("app.name"))
+	})
+
+	cases := []struct {
+		file string
+		want string
+	}{
+		{file: "cycle.json", want: "Config error: include cycle detected: cycle.json -> cycle/a.json -> cycle/b.json -> cycle.json"},
+		{file: "invalid.json", want: "Config error: invalid.json: \"include\" must be a string or an array of strings"},
+		{file: "invalid_array.json", want: "Config error: invalid_array.json: \"include\" must be a string or an array of strings"},
+		{file: "missing.json", want: "Config error: open notafile.json: file does not exist"},
+	}
+	for _, c := range cases {
+		t.Run(c.file, func(t *testing.T) {
+			cfg, err := newTestLoader().load(fileLayer(fs, c.file, false))
+			assert.Nil(t, cfg)
+			require.Error(t, err)
+			assert.Equal(t, c.want, err.Error())
+		})
+	}
+}
@@ -0,0 +1,286 @@
+package config
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"goyave.dev/goyave/v5/util/errors"
+)
+
+// RestartRequiredError returned by `Config.Reload()` when the new configuration
+// changes the value of entries that cannot be changed without restarting the server.
+// See `Entry.RequiresRestart`.
+type RestartRequiredError struct {
+	// Keys the sorted dot-separated paths of the entries requiring a restart.
+	Keys []string
+}
+
+func (e *RestartRequiredError) Error() string {
+	quoted := make([]string, 0, len(e.Keys))
+	for _, k := range e.Keys {
+		quoted = append(quoted, fmt.Sprintf("%q", k))
+	}
+	return fmt.Sprintf("cannot reload: changing %s requires a restart", strings.Join(quoted, ", "))
+}
+
+// subscriber a function notified of the changes made to the config when it is reloaded.
+type subscriber struct {
+	handler func(changed []string)
+	keys    []string
+}
+
+// matching returns the keys from the given list matching the keys this subscriber
+// is interested in.
+func (s *subscriber) matching(changed []string) []string {
+	if len(s.keys) == 0 {
+		return changed
+	}
+	result := make([]string, 0, len(changed))
+	for _, c := range changed {
+		for _, k := range s.keys {
+			if c == k || strings.HasPrefix(c, k+".") {
+				result = append(result, c)
+				break
+			}
+		}
+	}
+	return result
+}
+
+// Subscribe registers a handler notified when the configuration is reloaded with the list of
+// changed entries (dot-separated paths, sorted). If keys are given, the handler is only called if at
+// least one of the matching entries changed, and only receives the matching entries. A key can
+// identify either an entry or a category, in which case all the entries inside this category match.
+//
+//	cfg.Subscribe(func(changed []string) {
+//		corsOptions.AllowedOrigins = cfg.GetStringSlice("cors.origins")
+//	}, "cors")
+//
+// Handlers are executed synchronously, in the order they were registered, after the new
+// configuration has been swapped in. Changes made with `Config.Set()` don't trigger the handlers.
+//
+// Returns a function removing the subscription.
+func (c *Config) Subscribe(handler func(changed []string), keys ...string) (unsubscribe func()) {
+	s := &subscriber{handler: handler, keys: keys}
+	c.mu.Lock()
+	c.subscribers = append(c.subscribers, s)
+	c.mu.Unlock()
+	return func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.subscribers = slices.DeleteFunc(c.subscribers, func(sub *subscriber) bool { return sub == s })
+	}
+}
+
+// Reload loads the configuration again from the same sources it was originally loaded
+// from (config files, raw config, remote sources, environment variables), validates it and atomically
+// replaces the current values. Values changed with `Config.Set()` are lost.
+//
+// If the new configuration is invalid, or if it changes the value of entries requiring
+// a restart (returns a `*RestartRequiredError`), the current configuration is left
+// untouched.
+//
+// On success, the subscribers are notified (see `Config.Subscribe()`) and the sorted list
+// of changed entries is returned.
+func (c *Config) Reload() ([]string, error) {
+	if c.loader == nil {
+		return nil, errors.New("config cannot be reloaded: it was not created by a loader")
+	}
+	c.reloadMu.Lock()
+	defer c.reloadMu.Unlock()
+
+	result, err := c.loader.build(c.applyEnv, c.layers)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	changed := diff(c.config, result.config, "")
+	restart := []string{}
+	for _, key := range changed {
+		if requiresRestart(c.config, key) || requiresRestart(result.config, key) {
+			restart = append(restart, key)
+		}
+	}
+	if len(restart) > 0 {
+		c.mu.Unlock()
+		return nil, errors.New(&Error{&RestartRequiredError{Keys: restart}})
+	}
+
+	c.config = result.config
+	c.sources = result.sources
+	c.files = result.files
+	subscribers := slices.Clone(c.subscribers)
+	c.mu.Unlock()
+
+	if len(changed) > 0 {
+		for _, s := range subscribers {
+			if keys := s.matching(changed); len(keys) > 0 {
+				s.handler(keys)
+			}
+		}
+	}
+	return changed, nil
+}
+
+// Watch polls the config files every `interval` and reloads the configuration when
+// one of them is created, modified or removed. The included files are watched too.
+// The configuration is also reloaded when the refresh interval of one of its remote sources
+// has elapsed (see `RemoteSource.RefreshInterval`), so `interval` should be shorter than the
+// refresh intervals. Blocks until the given context is canceled.
+//
+// After each reload attempt, `onReload` is called with the result of `Config.Reload()`. If the
+// reload succeeded but some remote sources were unavailable, the error is a `*SourceError` (or
+// several joined with `errors.Join()`) and the last known good values of these sources were used.
+//
+// Configurations loaded from raw strings are only reloaded if they include files.
+//
+//	ctx, cancel := context.WithCancel(context.Background())
+//	defer cancel()
+//	go cfg.Watch(ctx, 5*time.Second, func(changed []string, err error) {
+//		if err != nil {
+//			server.Logger.Error(err)
+//		}
+//	})
+func (c *Config) Watch(ctx context.Context, interval time.Duration, onReload func(changed []string, err error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	state := c.filesState()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			newState := c.filesState()
+			if maps.Equal(state, newState) && !c.refreshDue() {
+				continue
+			}
+			changed, err := c.Reload()
+			if err == nil {
+				err = c.sourcesError()
+			}
+			if onReload != nil {
+				onReload(changed, err)
+			}
+			// Take the state after reloading so newly included files are watched.
+			state = c.filesState()
+		}
+	}
+}
+
+// refreshDue returns true if the refresh interval of at least one of the remote sources
+// of the config has elapsed.
+func (c *Config) refreshDue() bool {
+	for _, ly := range c.layers {
+		if ly.remote != nil && ly.remote.refreshDue() {
+			return true
+		}
+	}
+	return false
+}
+
+// sourcesError returns the errors of the last fetch of the remote sources of the config.
+func (c *Config) sourcesError() error {
+	errs := []error{}
+	for _, ly := range c.layers {
+		if ly.remote == nil {
+			continue
+		}
+		if err := ly.remote.Err(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0]
+	default:
+		return stderrors.Join(errs...)
+	}
+}
+
+// fileState the state of a watched file. A file that doesn't exist has a zero state.
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+func (c *Config) filesState() map[string]fileState {
+	c.mu.RLock()
+	files := c.files
+	c.mu.RUnlock()
+
+	state := make(map[string]fileState, len(files))
+	for _, f := range files {
+		s := fileState{}
+		if info, err := fs.Stat(f.fs, f.name); err == nil {
+			s.modTime = info.ModTime()
+			s.size = info.Size()
+		}
+		state[f.name] = s
+	}
+	return state
+}
+
+// diff returns the sorted paths of the entries that are different between the two given
+// categories, including the entries that only exist in one of them.
+func diff(previous, current object, key string) []string {
+	changed := []string{}
+	for k, v := range current {
+		subKey := joinKey(key, k)
+		if category, ok := v.(object); ok {
+			prev, _ := previous[k].(object)
+			changed = append(changed, diff(prev, category, subKey)...)
+			continue
+		}
+		prev, ok := previous[k].(*Entry)
+		if !ok || !reflect.DeepEqual(prev.Value, v.(*Entry).Value) {
+			changed = append(changed, subKey)
+		}
+	}
+	for k, v := range previous {
+		if _, ok := current[k]; ok {
+			continue
+		}
+		subKey := joinKey(key, k)
+		if category, ok := v.(object); ok {
+			changed = append(changed, diff(category, nil, subKey)...)
+			continue
+		}
+		changed = append(changed, subKey)
+	}
+	slices.Sort(changed)
+	return changed
+}
+
+// requiresRestart returns true if the entry identified by the given key exists in the given
+// category and requires a restart.
+func requiresRestart(category object, key string) bool {
+	current := category
+	segments := strings.Split(key, ".")
+	for _, segment := range segments[:len(segments)-1] {
+		sub, ok := current[segment].(object)
+		if !ok {
+			return false
+		}
+		current = sub
+	}
+	entry, ok := current[segments[len(segments)-1]].(*Entry)
+	return ok && entry.RequiresRestart
+}
+
+func joinKey(category, key string) string {
+	if category == "" {
+		return key
+	}
+	return category + "." + key
+}
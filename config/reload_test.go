@@ -0,0 +1,186 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path"
+	"reflect"
+	"sync"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRestartRequiredError(t *testing.T) {
+	err := &RestartRequiredError{Keys: []string{"server.host", "server.port"}}
+	assert.Equal(t, "cannot reload: changing \"server.host\", \"server.port\" requires a restart", err.Error())
+}
+
+func TestDiff(t *testing.T) {
+	previous := object{
+		"a": &Entry{Value: 1},
+		"b": &Entry{Value: []int{1, 2}},
+		"c": object{
+			"d": &Entry{Value: "d"},
+			"e": &Entry{Value: "e"},
+		},
+		"removed":    &Entry{Value: "removed"},
+		"removedCat": object{"f": &Entry{Value: "f"}},
+	}
+	current := object{
+		"a": &Entry{Value: 1},
+		"b": &Entry{Value: []int{1, 3}},
+		"c": object{
+			"d": &Entry{Value: "d"},
+			"e": &Entry{Value: "changed"},
+		},
+		"added":    &Entry{Value: "added"},
+		"addedCat": object{"g": &Entry{Value: "g"}},
+	}
+
+	assert.Equal(t, []string{"added", "addedCat.g", "b", "c.e", "removed", "removedCat.f"}, diff(previous, current, ""))
+	assert.Empty(t, diff(current, current, ""))
+}
+
+func TestReload(t *testing.T) {
+	newLoader := func() *loader {
+		l := newTestLoader()
+		l.register("custom.restart", Entry{Value: "initial", AuthorizedValues: []any{}, Type: reflect.String, RequiresRestart: true})
+		return l
+	}
+
+	t.Run("reload", func(t *testing.T) {
+		fs := fstest.MapFS{
+			"config.json": &fstest.MapFile{Data: []byte(`{"app": {"name": "initial"}, "custom": {"entry": "initial"}}`)},
+		}
+		cfg, err := newLoader().load(fileLayer(fs, "config.json", false))
+		require.NoError(t, err)
+
+		calls := [][]string{}
+		appCalls := [][]string{}
+		unsubscribe := cfg.Subscribe(func(changed []string) {
+			calls = append(calls, changed)
+		})
+		cfg.Subscribe(func(changed []string) {
+			appCalls = append(appCalls, changed)
+		}, "app.name", "server")
+
+		cfg.Set("custom.runtime", "runtime")
+
+		changed, err := cfg.Reload()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"custom.runtime"}, changed)
+		assert.False(t, cfg.Has("custom.runtime"))
+		assert.Equal(t, [][]string{{"custom.runtime"}}, calls)
+		assert.Empty(t, appCalls)
+
+		fs["config.json"] = &fstest.MapFile{Data: []byte(`{"app": {"name": "reloaded"}, "custom": {"new": "new"}}`)}
+		t.Setenv("GOYAVE_APP_DEBUG", "false")
+		changed, err = cfg.Reload()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"app.debug", "app.name", "custom.entry", "custom.new"}, changed)
+		assert.Equal(t, "reloaded", cfg.GetString("app.name"))
+		assert.False(t, cfg.GetBool("app.debug"))
+		assert.Equal(t, "env:GOYAVE_APP_DEBUG", cfg.Source("app.debug"))
+		assert.Equal(t, [][]string{{"custom.runtime"}, {"app.debug", "app.name", "custom.entry", "custom.new"}}, calls)
+		assert.Equal(t, [][]string{{"app.name"}}, appCalls)
+
+		// No change: subscribers not called
+		unsubscribe()
+		changed, err = cfg.Reload()
+		require.NoError(t, err)
+		assert.Empty(t, changed)
+		assert.Len(t, calls, 2)
+		assert.Len(t, appCalls, 1)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		fs := fstest.MapFS{
+			"config.json": &fstest.MapFile{Data: []byte(`{"app": {"name": "initial"}}`)},
+		}
+		cfg, err := newLoader().load(fileLayer(fs, "config.json", false))
+		require.NoError(t, err)
+
+		fs["config.json"] = &fstest.MapFile{Data: []byte(`{"app": {"name": 123}}`)}
+		changed, err := cfg.Reload()
+		assert.Nil(t, changed)
+		require.Error(t, err)
+		assert.Equal(t, "Config error: \n\t- \"app.name\" type must be string", err.Error())
+		assert.Equal(t, "initial", cfg.GetString("app.name"))
+	})
+
+	t.Run("requires_restart", func(t *testing.T) {
+		fs := fstest.MapFS{
+			"config.json": &fstest.MapFile{Data: []byte(`{"app": {"name": "initial"}}`)},
+		}
+		cfg, err := newLoader().load(fileLayer(fs, "config.json", false))
+		require.NoError(t, err)
+
+		called := false
+		cfg.Subscribe(func(_ []string) {
+			called = true
+		})
+
+		fs["config.json"] = &fstest.MapFile{Data: []byte(`{"app": {"name": "reloaded"}, "server": {"port": 1234}, "custom": {"restart": "changed"}}`)}
+		changed, err := cfg.Reload()
+		assert.Nil(t, changed)
+		require.Error(t, err)
+		assert.Equal(t, "Config error: cannot reload: changing \"custom.restart\", \"server.port\" requires a restart", err.Error())
+
+		var restartErr *RestartRequiredError
+		require.ErrorAs(t, err, &restartErr)
+		assert.Equal(t, []string{"custom.restart", "server.port"}, restartErr.Keys)
+
+		assert.Equal(t, "initial", cfg.GetString("app.name"))
+		assert.Equal(t, 8080, cfg.GetInt("server.port"))
+		assert.False(t, called)
+	})
+
+	t.Run("not_reloadable", func(t *testing.T) {
+		cfg := &Config{config: object{}}
+		_, err := cfg.Reload()
+		require.Error(t, err)
+	})
+}
+
+func TestWatch(t *testing.T) {
+	dir := t.TempDir()
+	file := path.Join(dir, "config.json")
+	require.NoError(t, os.WriteFile(file, []byte(`{"include": "included.json", "app": {"name": "initial"}}`), 0o644))
+	require.NoError(t, os.WriteFile(path.Join(dir, "included.json"), []byte(`{"custom": {"entry": "initial"}}`), 0o644))
+
+	cfg, err := newTestLoader().load(fileLayer(os.DirFS(dir), "config.json", false))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	results := make(chan []string, 10)
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		cfg.Watch(ctx, 10*time.Millisecond, func(changed []string, err error) {
+			assert.NoError(t, err)
+			results <- changed
+		})
+	}()
+
+	// Give the watcher the time to take the initial state
+	time.Sleep(30 * time.Millisecond)
+	require.NoError(t, os.WriteFile(path.Join(dir, "included.json"), []byte(`{"custom": {"entry": "modified"}}`), 0o644))
+
+	select {
+	case changed := <-results:
+		assert.Equal(t, []string{"custom.entry"}, changed)
+		assert.Equal(t, "modified", cfg.GetString("custom.entry"))
+	case <-time.After(2 * time.Second):
+		assert.Fail(t, "config not reloaded")
+	}
+
+	cancel()
+	wg.Wait()
+}
@@ -0,0 +1,209 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/samber/lo"
+)
+
+// placeholderPattern matches the string values that are resolved when the config is loaded
+// (environment variable placeholders and secret references) so they are accepted by the schema
+// for entries that are not strings.
+const placeholderPattern = `^(\$\{.+\}|secret://.+/.*)$`
+
+// JSONSchema returns the JSON Schema (draft 2020-12) describing the config files, generated from
+// the registered config entries. It can be used for editor auto-completion and to validate
+// config files in CI:
+//
+//	schema, err := json.MarshalIndent(config.JSONSchema(), "", "  ")
+//	// ...
+//	os.WriteFile("config.schema.json", schema, 0o644)
+//
+// Entries can be omitted in config files if they have a default value, so required entries are
+// only listed in the schema's "required" properties if they don't have a default value. The default
+// value of secret entries is not included.
+//
+// Entries that are not registered are allowed by the schema.
+func JSONSchema() map[string]any {
+	defaultLoader.mu.RLock()
+	defer defaultLoader.mu.RUnlock()
+	schema := categorySchema(defaultLoader.defaults)
+	schema["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	schema["properties"].(map[string]any)[includeKey] = map[string]any{
+		"description": "Config files to include, relative to this file.",
+		"anyOf": []any{
+			map[string]any{"type": "string"},
+			map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+		},
+	}
+	return schema
+}
+
+func categorySchema(category object) map[string]any {
+	properties := make(map[string]any, len(category))
+	required := []string{}
+	for k, v := range category {
+		if sub, ok := v.(object); ok {
+			properties[k] = categorySchema(sub)
+			continue
+		}
+		entry := v.(*Entry)
+		properties[k] = entry.schema()
+		if entry.Required && entry.Value == nil {
+			required = append(required, k)
+		}
+	}
+	schema := map[string]any{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		slices.Sort(required)
+		schema["required"] = required
+	}
+	return schema
+}
+
+func (e *Entry) schema() map[string]any {
+	item := map[string]any{}
+	if t := jsonType(e.Type); t != "" {
+		item["type"] = t
+	}
+	if e.Type == KindDuration || e.Type == KindByteSize {
+		// Numbers are accepted and multiplied by the entry's unit
+		item["type"] = []string{"string", "number"}
+	}
+	if len(e.AuthorizedValues) > 0 {
+		item["enum"] = e.AuthorizedValues
+	}
+	e.constraintsSchema(item)
+
+	schema := item
+	if e.IsSlice {
+		schema = map[string]any{
+			"type":  "array",
+			"items": item,
+		}
+	}
+
+	if e.Type != reflect.String || e.IsSlice || e.Pattern != "" || e.Min != nil || e.Max != nil {
+		schema = map[string]any{
+			"anyOf": []any{
+				schema,
+				map[string]any{"type": "string", "pattern": placeholderPattern},
+			},
+		}
+	}
+
+	if e.Value != nil && !e.Secret {
+		schema["default"] = e.schemaValue()
+	}
+	if e.Secret {
+		schema["writeOnly"] = true
+	}
+	if e.RequiresRestart {
+		schema["description"] = "Changing this entry requires a restart."
+	}
+	return schema
+}
+
+// constraintsSchema adds the bounds, pattern and format of the entry to the given item schema.
+func (e *Entry) constraintsSchema(item map[string]any) {
+	switch e.Type {
+	case KindURL:
+		item["format"] = "uri"
+	case KindHost:
+		item["format"] = "hostname"
+	}
+	if e.Pattern != "" {
+		item["pattern"] = e.Pattern
+	}
+
+	if e.Type == KindDuration || e.Type == KindByteSize {
+		// Duration and byte size bounds cannot be expressed in JSON Schema
+		return
+	}
+	minKey, maxKey := "minimum", "maximum"
+	if e.isLengthBound() {
+		minKey, maxKey = "minLength", "maxLength"
+	}
+	if e.Min != nil {
+		item[minKey] = e.Min
+	}
+	if e.Max != nil {
+		item[maxKey] = e.Max
+	}
+}
+
+// schemaValue returns the representation of the entry's value in config files.
+func (e *Entry) schemaValue() any {
+	switch v := e.Value.(type) {
+	case time.Duration:
+		return v.String()
+	case []time.Duration:
+		return lo.Map(v, func(d time.Duration, _ int) string { return d.String() })
+	case int64:
+		if e.Type == KindByteSize {
+			return formatByteSize(v)
+		}
+	case []int64:
+		if e.Type == KindByteSize {
+			return lo.Map(v, func(size int64, _ int) string { return formatByteSize(size) })
+		}
+	}
+	return e.Value
+}
+
+func jsonType(kind reflect.Kind) string {
+	switch kind {
+	case reflect.String, KindURL, KindHost:
+		return "string"
+	case reflect.Int:
+		return "integer"
+	case reflect.Float64:
+		return "number"
+	case reflect.Bool:
+		return "boolean"
+	default:
+		return ""
+	}
+}
+
+// Dump returns the values of all the entries of the configuration as nested maps.
+// The value of secret entries is replaced with `Redacted`. Unset entries are included
+// with a `nil` value. Durations and byte sizes are written as strings ("10s", "10MiB")
+// so the dump can be loaded back as a config.
+//
+// This can be used to inspect the effective configuration, for example from a debug route
+// or a CLI command. `Config` also implements `json.Marshaler` using this function.
+func (c *Config) Dump() map[string]any {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return dumpCategory(c.config)
+}
+
+func dumpCategory(category object) map[string]any {
+	dump := make(map[string]any, len(category))
+	for k, v := range category {
+		if sub, ok := v.(object); ok {
+			dump[k] = dumpCategory(sub)
+			continue
+		}
+		entry := v.(*Entry)
+		if entry.Secret && entry.Value != nil {
+			dump[k] = Redacted
+			continue
+		}
+		dump[k] = entry.schemaValue()
+	}
+	return dump
+}
+
+// MarshalJSON returns the JSON representation of the configuration's values.
+// Secret values are redacted. See `Config.Dump()`.
+func (c *Config) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.Dump())
+}
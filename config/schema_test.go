@@ -0,0 +1,265 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONSchema(t *testing.T) {
+	schema := JSONSchema()
+
+	assert.Equal(t, "https://json-schema.org/draft/2020-12/schema", schema["$schema"])
+	assert.Equal(t, "object", schema["type"])
+	properties := schema["properties"].(map[string]any)
+	assert.Contains(t, properties, "include")
+
+	server := properties["server"].(map[string]any)
+	assert.Equal(t, "object", server["type"])
+	serverProperties := server["properties"].(map[string]any)
+
+	assert.Equal(t, map[string]any{
+		"anyOf": []any{
+			map[string]any{"type": "integer", "minimum": 0, "maximum": 65535},
+			map[string]any{"type": "string", "pattern": placeholderPattern},
+		},
+		"default":     8080,
+		"description": "Changing this entry requires a restart.",
+	}, serverProperties["port"])
+
+	proxy := serverProperties["proxy"].(map[string]any)["properties"].(map[string]any)
+	assert.Equal(t, map[string]any{
+		"type":        "string",
+		"enum":        []any{"http", "https"},
+		"default":     "http",
+		"description": "Changing this entry requires a restart.",
+	}, proxy["protocol"])
+
+	database := properties["database"].(map[string]any)["properties"].(map[string]any)
+	assert.Equal(t, map[string]any{
+		"type":        "string",
+		"writeOnly":   true,
+		"description": "Changing this entry requires a restart.",
+	}, database["password"])
+
+	// The schema can be marshaled
+	_, err := json.Marshal(schema)
+	require.NoError(t, err)
+}
+
+func TestCategorySchema(t *testing.T) {
+	category := object{
+		"required": &Entry{
+			Value:            nil,
+			AuthorizedValues: []any{},
+			Type:             reflect.String,
+			IsSlice:          false,
+			Required:         true,
+		},
+		"withDefault": &Entry{
+			Value:            "default",
+			AuthorizedValues: []any{},
+			Type:             reflect.String,
+			IsSlice:          false,
+			Required:         true,
+		},
+		"slice": &Entry{
+			Value:            []string{"a"},
+			AuthorizedValues: []any{"a", "b"},
+			Type:             reflect.String,
+			IsSlice:          true,
+		},
+		"any": &Entry{
+			Value:            nil,
+			AuthorizedValues: []any{},
+			Type:             reflect.Interface,
+			IsSlice:          false,
+		},
+		"sub": object{
+			"bool": &Entry{
+				Value:            true,
+				AuthorizedValues: []any{},
+				Type:             reflect.Bool,
+				IsSlice:          false,
+				Required:         true,
+			},
+			"float": &Entry{
+				Value:            nil,
+				AuthorizedValues: []any{},
+				Type:             reflect.Float64,
+				IsSlice:          false,
+				Required:         true,
+			},
+		},
+	}
+
+	expected := map[string]any{
+		"type":     "object",
+		"required": []string{"required"},
+		"properties": map[string]any{
+			"required":    map[string]any{"type": "string"},
+			"withDefault": map[string]any{"type": "string", "default": "default"},
+			"slice": map[string]any{
+				"anyOf": []any{
+					map[string]any{"type": "array", "items": map[string]any{"type": "string", "enum": []any{"a", "b"}}},
+					map[string]any{"type": "string", "pattern": placeholderPattern},
+				},
+				"default": []string{"a"},
+			},
+			"any": map[string]any{
+				"anyOf": []any{
+					map[string]any{},
+					map[string]any{"type": "string", "pattern": placeholderPattern},
+				},
+			},
+			"sub": map[string]any{
+				"type":     "object",
+				"required": []string{"float"},
+				"properties": map[string]any{
+					"bool": map[string]any{
+						"anyOf": []any{
+							map[string]any{"type": "boolean"},
+							map[string]any{"type": "string", "pattern": placeholderPattern},
+						},
+						"default": true,
+					},
+					"float": map[string]any{
+						"anyOf": []any{
+							map[string]any{"type": "number"},
+							map[string]any{"type": "string", "pattern": placeholderPattern},
+						},
+					},
+				},
+			},
+		},
+	}
+	assert.Equal(t, expected, categorySchema(category))
+}
+
+func TestDump(t *testing.T) {
+	t.Setenv("TEST_DUMP_SECRET", "env-secret")
+	cfg, err := newTestLoader().loadJSON(`{
+		"database": {"password": "p4ssw0rd"},
+		"custom": {"secret": "secret://env/TEST_DUMP_SECRET", "entry": "value"}
+	}`)
+	require.NoError(t, err)
+
+	dump := cfg.Dump()
+	assert.Equal(t, Redacted, dump["database"].(map[string]any)["password"])
+	assert.Equal(t, Redacted, dump["custom"].(map[string]any)["secret"])
+	assert.Equal(t, "value", dump["custom"].(map[string]any)["entry"])
+	assert.Equal(t, 8080, dump["server"].(map[string]any)["port"])
+	assert.Nil(t, dump["server"].(map[string]any)["proxy"].(map[string]any)["host"])
+
+	data, err := json.Marshal(cfg)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "p4ssw0rd")
+	assert.NotContains(t, string(data), "env-secret")
+	assert.Contains(t, string(data), `"password":"[REDACTED]"`)
+	assert.Equal(t, "10s", dump["server"].(map[string]any)["writeTimeout"])
+	assert.Equal(t, "10MiB", dump["server"].(map[string]any)["maxUploadSize"])
+
+	t.Run("round_trip", func(t *testing.T) {
+		loaded, err := newTestLoader().loadJSON(string(data))
+		require.NoError(t, err)
+		assert.Equal(t, cfg.GetDuration("server.writeTimeout"), loaded.GetDuration("server.writeTimeout"))
+		assert.Equal(t, cfg.GetByteSize("server.maxUploadSize"), loaded.GetByteSize("server.maxUploadSize"))
+		assert.Equal(t, dump, loaded.Dump())
+	})
+}
+
+func TestEntrySchemaConstraints(t *testing.T) {
+	placeholder := map[string]any{"type": "string", "pattern": placeholderPattern}
+	cases := []struct {
+		entry *Entry
+		want  map[string]any
+		desc  string
+	}{
+		{
+			desc: "bounds",
+			entry: &Entry{
+				Value:            nil,
+				Min:              0,
+				Max:              10.5,
+				AuthorizedValues: []any{},
+				Type:             reflect.Float64,
+				IsSlice:          false,
+			},
+			want: map[string]any{"anyOf": []any{map[string]any{"type": "number", "minimum": 0, "maximum": 10.5}, placeholder}},
+		},
+		{
+			desc: "string",
+			entry: &Entry{
+				Value:            "abc",
+				Min:              1,
+				Max:              10,
+				Pattern:          "^[a-z]+$",
+				AuthorizedValues: []any{},
+				Type:             reflect.String,
+				IsSlice:          false,
+			},
+			want: map[string]any{
+				"anyOf":   []any{map[string]any{"type": "string", "minLength": 1, "maxLength": 10, "pattern": "^[a-z]+$"}, placeholder},
+				"default": "abc",
+			},
+		},
+		{
+			desc: "duration",
+			entry: &Entry{
+				Value:            []time.Duration{time.Second},
+				Min:              "1s",
+				AuthorizedValues: []any{},
+				Type:             KindDuration,
+				IsSlice:          true,
+			},
+			want: map[string]any{
+				"anyOf":   []any{map[string]any{"type": "array", "items": map[string]any{"type": []string{"string", "number"}}}, placeholder},
+				"default": []string{"1s"},
+			},
+		},
+		{
+			desc: "byte_size",
+			entry: &Entry{
+				Value:            int64(10 << 20),
+				Min:              0,
+				AuthorizedValues: []any{},
+				Type:             KindByteSize,
+				Unit:             1 << 20,
+				IsSlice:          false,
+			},
+			want: map[string]any{
+				"anyOf":   []any{map[string]any{"type": []string{"string", "number"}}, placeholder},
+				"default": "10MiB",
+			},
+		},
+		{
+			desc: "url",
+			entry: &Entry{
+				Value:            nil,
+				AuthorizedValues: []any{},
+				Type:             KindURL,
+				IsSlice:          false,
+			},
+			want: map[string]any{"anyOf": []any{map[string]any{"type": "string", "format": "uri"}, placeholder}},
+		},
+		{
+			desc: "host",
+			entry: &Entry{
+				Value:            "localhost",
+				AuthorizedValues: []any{},
+				Type:             KindHost,
+				IsSlice:          false,
+			},
+			want: map[string]any{"anyOf": []any{map[string]any{"type": "string", "format": "hostname"}, placeholder}, "default": "localhost"},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			assert.Equal(t, c.want, c.entry.schema())
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package config
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"goyave.dev/goyave/v5/util/errors"
+	"goyave.dev/goyave/v5/util/fsutil/osfs"
+)
+
+const (
+	// SecretScheme the prefix of the config values referencing a secret.
+	// A secret reference has the following format: "secret://<provider>/<path>".
+	SecretScheme = "secret://"
+
+	// Redacted the placeholder replacing the value of secret entries in
+	// error messages and config dumps.
+	Redacted = "[REDACTED]"
+)
+
+// SecretProvider resolves secret references found in config values. Secret references have
+// the format "secret://<provider>/<path>". The path is passed to the provider registered with the
+// matching name. See `RegisterSecretProvider()`.
+type SecretProvider interface {
+	// Resolve returns the value of the secret identified by the given path.
+	Resolve(path string) (string, error)
+}
+
+// SecretProviderFunc an adapter allowing the use of a function as a `SecretProvider`.
+type SecretProviderFunc func(path string) (string, error)
+
+// Resolve calls the function.
+func (f SecretProviderFunc) Resolve(path string) (string, error) {
+	return f(path)
+}
+
+// FileSecretProvider resolves secrets by reading files. The path of the secret reference
+// is the path of the file inside `FS`. Leading and trailing white spaces are removed from
+// the content of the file.
+//
+// This provider is registered by default with the name "file" and the root of the OS file system,
+// so mounted secrets (Docker, Kubernetes) can be referenced directly: "secret://file/run/secrets/db_password".
+// To reference secrets relative to a specific directory, register a new provider:
+//
+//	config.RegisterSecretProvider("docker", &config.FileSecretProvider{FS: osfs.New("/run/secrets")})
+//	// "secret://docker/db_password"
+type FileSecretProvider struct {
+	FS fs.FS
+}
+
+// Resolve returns the trimmed content of the file at the given path.
+func (p *FileSecretProvider) Resolve(path string) (string, error) {
+	content, err := fs.ReadFile(p.FS, path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(content)), nil
+}
+
+// EnvSecretProvider resolves secrets from environment variables. The path of the secret
+// reference is the name of the variable: "secret://env/DB_PASSWORD".
+//
+// This provider is registered by default with the name "env".
+type EnvSecretProvider struct{}
+
+// Resolve returns the value of the environment variable identified by the given name.
+func (EnvSecretProvider) Resolve(name string) (string, error) {
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return "", fmt.Errorf("environment variable %q is not set", name)
+	}
+	return value, nil
+}
+
+func defaultSecretProviders() map[string]SecretProvider {
+	return map[string]SecretProvider{
+		"file": &FileSecretProvider{FS: osfs.New("/")},
+		"env":  EnvSecretProvider{},
+	}
+}
+
+// RegisterSecretProvider registers a provider resolving the secret references having the
+// given provider name ("secret://<name>/<path>"). Replaces the existing provider having
+// the same name, if any.
+//
+// Secret references are resolved when the config is loaded or reloaded, after the
+// environment variable overrides are applied. The resolved value is converted to the
+// type of the entry. Entries whose value is resolved from a secret are marked as `Entry.Secret`.
+func RegisterSecretProvider(name string, provider SecretProvider) {
+	defaultLoader.mu.Lock()
+	defer defaultLoader.mu.Unlock()
+	defaultLoader.secretProviders[name] = provider
+}
+
+// resolveSecrets replaces the secret references of the entries of the given category
+// (identified by the given path) with the secret value returned by the matching provider.
+func (l *loader) resolveSecrets(category object, key string) error {
+	message := ""
+	for k, entry := range category {
+		subKey := joinKey(key, k)
+		if subCategory, ok := entry.(object); ok {
+			if err := l.resolveSecrets(subCategory, subKey); err != nil {
+				message += err.Error()
+			}
+			continue
+		}
+
+		e := entry.(*Entry)
+		ref, ok := e.Value.(string)
+		if !ok || !strings.HasPrefix(ref, SecretScheme) {
+			continue
+		}
+		e.Secret = true
+		if err := l.resolveSecret(e, ref); err != nil {
+			message += fmt.Sprintf("\n\t- %q: could not resolve secret %q: %s", subKey, ref, err.Error())
+		}
+	}
+	if message != "" {
+		return fmt.Errorf("%s", message)
+	}
+	return nil
+}
+
+func (l *loader) resolveSecret(e *Entry, ref string) error {
+	name, path, _ := strings.Cut(strings.TrimPrefix(ref, SecretScheme), "/")
+	provider, ok := l.secretProviders[name]
+	if !ok {
+		return errors.Errorf("unknown secret provider %q", name)
+	}
+	secret, err := provider.Resolve(path)
+	if err != nil {
+		return err
+	}
+	value, err := e.convertString(secret)
+	if err != nil {
+		// Don't include the original error as it may contain the secret value
+		return errors.Errorf("secret could not be converted to %s", e.typeName())
+	}
+	e.Value = value
+	return nil
+}
@@ -0,0 +1,150 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecretProviders(t *testing.T) {
+	t.Run("file", func(t *testing.T) {
+		p := &FileSecretProvider{FS: fstest.MapFS{
+			"run/secrets/db_password": &fstest.MapFile{Data: []byte("p4ssw0rd\n")},
+		}}
+		secret, err := p.Resolve("run/secrets/db_password")
+		require.NoError(t, err)
+		assert.Equal(t, "p4ssw0rd", secret)
+
+		_, err = p.Resolve("run/secrets/notafile")
+		require.Error(t, err)
+	})
+
+	t.Run("env", func(t *testing.T) {
+		t.Setenv("TEST_SECRET", "s3cr3t")
+		secret, err := EnvSecretProvider{}.Resolve("TEST_SECRET")
+		require.NoError(t, err)
+		assert.Equal(t, "s3cr3t", secret)
+
+		_, err = EnvSecretProvider{}.Resolve("TEST_SECRET_UNSET")
+		require.Error(t, err)
+		assert.Equal(t, "environment variable \"TEST_SECRET_UNSET\" is not set", err.Error())
+	})
+
+	t.Run("func", func(t *testing.T) {
+		p := SecretProviderFunc(func(path string) (string, error) {
+			return "secret-" + path, nil
+		})
+		secret, err := p.Resolve("path")
+		require.NoError(t, err)
+		assert.Equal(t, "secret-path", secret)
+	})
+
+	t.Run("RegisterSecretProvider", func(t *testing.T) {
+		p := SecretProviderFunc(func(_ string) (string, error) { return "", nil })
+		RegisterSecretProvider("test", p)
+		t.Cleanup(func() {
+			defaultLoader.mu.Lock()
+			delete(defaultLoader.secretProviders, "test")
+			defaultLoader.mu.Unlock()
+		})
+		assert.NotNil(t, defaultLoader.secretProviders["test"])
+		assert.Contains(t, defaultLoader.secretProviders, "file")
+		assert.Contains(t, defaultLoader.secretProviders, "env")
+	})
+}
+
+func TestResolveSecrets(t *testing.T) {
+	newLoader := func() *loader {
+		l := newTestLoader()
+		l.secretProviders["vault"] = SecretProviderFunc(func(path string) (string, error) {
+			switch path {
+			case "db/port":
+				return "5432", nil
+			case "db/password":
+				return "vault-password", nil
+			case "cors/origins":
+				return "a.example.org,b.example.org", nil
+			}
+			return "", fmt.Errorf("secret %q not found", path)
+		})
+		l.register("cors.origins", Entry{Value: []string{}, AuthorizedValues: []any{}, Type: reflect.String, IsSlice: true})
+		return l
+	}
+
+	t.Run("resolve", func(t *testing.T) {
+		t.Setenv("TEST_JWT_SECRET", "jwt-secret")
+		t.Setenv("GOYAVE_DATABASE_PORT", "secret://vault/db/port")
+		cfg, err := newLoader().loadJSON(`{
+			"database": {"password": "secret://vault/db/password"},
+			"cors": {"origins": "secret://vault/cors/origins"},
+			"custom": {"secret": "secret://env/TEST_JWT_SECRET", "notASecret": "secret"}
+		}`)
+		require.NoError(t, err)
+
+		assert.Equal(t, "vault-password", cfg.GetString("database.password"))
+		assert.Equal(t, 5432, cfg.GetInt("database.port"))
+		assert.Equal(t, []string{"a.example.org", "b.example.org"}, cfg.GetStringSlice("cors.origins"))
+		assert.Equal(t, "jwt-secret", cfg.GetString("custom.secret"))
+		assert.Equal(t, "env:GOYAVE_DATABASE_PORT", cfg.Source("database.port"))
+
+		secret := func(key string) bool {
+			category, entryKey, _ := walk(cfg.config, key)
+			return category[entryKey].(*Entry).Secret
+		}
+		assert.True(t, secret("database.password"))
+		assert.True(t, secret("database.port"))
+		assert.True(t, secret("custom.secret"))
+		assert.False(t, secret("custom.notASecret"))
+	})
+
+	t.Run("file", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(dir+"/db_password", []byte("file-password\n"), 0o600))
+		cfg, err := newLoader().loadJSON(fmt.Sprintf(`{"database": {"password": "secret://file%s/db_password"}}`, dir))
+		require.NoError(t, err)
+		assert.Equal(t, "file-password", cfg.GetString("database.password"))
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		cfg, err := newLoader().loadJSON(`{
+			"database": {"password": "secret://unknown/db/password"},
+			"custom": {"secret": "secret://vault/notfound"}
+		}`)
+		assert.Nil(t, cfg)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "\n\t- \"database.password\": could not resolve secret \"secret://unknown/db/password\": unknown secret provider \"unknown\"")
+		assert.Contains(t, err.Error(), "\n\t- \"custom.secret\": could not resolve secret \"secret://vault/notfound\": secret \"notfound\" not found")
+	})
+
+	t.Run("conversion_error_redacted", func(t *testing.T) {
+		cfg, err := newLoader().loadJSON(`{"server": {"port": "secret://vault/db/password"}}`)
+		assert.Nil(t, cfg)
+		require.Error(t, err)
+		assert.Equal(t, "Config error: \n\t- \"server.port\": could not resolve secret \"secret://vault/db/password\": secret could not be converted to int", err.Error())
+		assert.NotContains(t, err.Error(), "vault-password")
+	})
+
+	t.Run("env_error_redacted", func(t *testing.T) {
+		l := newLoader()
+		l.register("custom.pin", Entry{Value: nil, AuthorizedValues: []any{}, Type: reflect.Int, Secret: true})
+
+		t.Setenv("GOYAVE_CUSTOM_PIN", "my-secret-pin")
+		cfg, err := l.loadJSON(`{}`)
+		assert.Nil(t, cfg)
+		require.Error(t, err)
+		assert.Equal(t, "Config error: \n\t- \"custom.pin\" could not be converted to int from environment variable \"GOYAVE_CUSTOM_PIN\": [REDACTED]", err.Error())
+
+		t.Setenv("GOYAVE_CUSTOM_PIN", "")
+		t.Setenv("TEST_PIN", "my-secret-pin")
+		l.envPrefix = ""
+		cfg, err = l.loadJSON(`{"custom": {"pin": "${TEST_PIN}"}}`)
+		assert.Nil(t, cfg)
+		require.Error(t, err)
+		assert.Equal(t, "Config error: \n\t- \"custom.pin\" could not be converted to int from environment variable \"TEST_PIN\" of value \"[REDACTED]\"", err.Error())
+	})
+}
@@ -0,0 +1,325 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"goyave.dev/goyave/v5/util/errors"
+	"goyave.dev/goyave/v5/util/fsutil/osfs"
+)
+
+const (
+	// SourceRemotePrefix the prefix of the source of the entries coming from a remote source.
+	// The source is followed by the name of the remote source ("remote:consul").
+	SourceRemotePrefix = "remote:"
+
+	// DefaultSourceTimeout the maximum duration of a remote source fetch if
+	// `RemoteSource.Timeout` is not set.
+	DefaultSourceTimeout = 10 * time.Second
+)
+
+// Source provides configuration values from outside the config files, such as
+// an HTTP endpoint, a key-value store or a database table.
+type Source interface {
+	// Name identifies the source in `Config.Source()` and in error messages.
+	Name() string
+
+	// Fetch returns the configuration values as nested maps, using the same structure
+	// as config files. Values are converted and validated like values read from files.
+	// Implementations must return when the given context is canceled: a fetch
+	// ignoring the context keeps running after the timeout of the `RemoteSource`.
+	// No new fetch is started until it returns, so the source is not refreshed
+	// in the meantime.
+	Fetch(ctx context.Context) (map[string]any, error)
+}
+
+// SourceError returned when a remote source could not be fetched.
+type SourceError struct {
+	err error
+
+	// Source the name of the source that could not be fetched.
+	Source string
+
+	// Fallback true if the last known good values of the source were used instead.
+	Fallback bool
+}
+
+func (e *SourceError) Error() string {
+	if e.Fallback {
+		return fmt.Sprintf("config source %q unavailable, using last known good values: %s", e.Source, e.err.Error())
+	}
+	return fmt.Sprintf("config source %q unavailable: %s", e.Source, e.err.Error())
+}
+
+func (e *SourceError) Unwrap() error {
+	return e.err
+}
+
+// RemoteSource a `Source` merged into the configuration after the config files, and
+// before the environment variables. See `LoadWithSources()`.
+//
+// The values of the last successful fetch (the last known good values) are kept and used
+// if the source is unavailable when the config is reloaded. If `CacheFile` is set, they are
+// also persisted so they can be used if the source is unavailable at startup.
+type RemoteSource struct {
+	fetchedAt time.Time
+
+	Source  Source
+	lastErr error
+
+	// pending receives the result of the running fetch. It is kept if the fetch
+	// times out so the next one waits for it instead of starting a new one.
+	pending chan fetchResult
+
+	// CacheFile the path of the file in which the last known good values are stored.
+	// Leave empty to disable the persistence of the last known good values.
+	CacheFile string
+
+	// lastGood the JSON representation of the values of the last successful fetch.
+	lastGood []byte
+
+	// Timeout the maximum duration of a fetch. Defaults to `DefaultSourceTimeout`.
+	Timeout time.Duration
+
+	// RefreshInterval the minimum duration between two fetches when the config is watched
+	// (see `Config.Watch()`). The config is reloaded when this duration has elapsed.
+	// If zero, the source is only fetched when the config is loaded or reloaded.
+	RefreshInterval time.Duration
+
+	mu sync.Mutex
+
+	// fetchMu serializes the fetches and protects `pending`.
+	fetchMu sync.Mutex
+
+	// Optional if true, the source is ignored if it is unavailable and there are no last
+	// known good values. Otherwise, loading the config fails.
+	Optional bool
+}
+
+// Err returns the error of the last fetch, or nil if it was successful.
+func (s *RemoteSource) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastErr
+}
+
+// refreshDue returns true if the refresh interval has elapsed since the last fetch.
+func (s *RemoteSource) refreshDue() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.RefreshInterval > 0 && time.Since(s.fetchedAt) >= s.RefreshInterval
+}
+
+// read fetches the values of the source, falling back to the last known good values
+// if the source is unavailable. The fetch is done without holding the lock of the source
+// so `Err()` and the refresh checks are not blocked by a slow source.
+func (s *RemoteSource) read() (object, error) {
+	fetchedAt := time.Now()
+	data, err := s.fetch()
+	var cacheErr error
+	if err == nil && s.CacheFile != "" {
+		if writeErr := os.WriteFile(s.CacheFile, data, 0o600); writeErr != nil {
+			cacheErr = errors.Errorf("config source %q: could not write cache: %w", s.Source.Name(), writeErr)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fetchedAt = fetchedAt
+	if err == nil {
+		s.lastErr = cacheErr
+		s.lastGood = data
+		return decodeJSON(bytes.NewReader(data))
+	}
+
+	if s.lastGood == nil && s.CacheFile != "" {
+		if cache, cacheErr := os.ReadFile(s.CacheFile); cacheErr == nil {
+			s.lastGood = cache
+		}
+	}
+	if s.lastGood != nil {
+		s.lastErr = &SourceError{err: err, Source: s.Source.Name(), Fallback: true}
+		return decodeJSON(bytes.NewReader(s.lastGood))
+	}
+
+	s.lastErr = &SourceError{err: err, Source: s.Source.Name()}
+	if s.Optional {
+		return nil, nil
+	}
+	return nil, s.lastErr
+}
+
+type fetchResult struct {
+	err    error
+	values map[string]any
+}
+
+// fetch calls `Source.Fetch()` and returns the JSON representation of the values.
+// If the previous fetch timed out and is still running, waits for its result
+// instead of starting a new one.
+func (s *RemoteSource) fetch() ([]byte, error) {
+	s.fetchMu.Lock()
+	defer s.fetchMu.Unlock()
+	timeout := s.Timeout
+	if timeout <= 0 {
+		timeout = DefaultSourceTimeout
+	}
+
+	if s.pending != nil {
+		select {
+		case <-s.pending:
+			// The previous fetch returned after its timeout, its result is outdated.
+			s.pending = nil
+		default:
+		}
+	}
+	if s.pending == nil {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		pending := make(chan fetchResult, 1)
+		s.pending = pending
+		go func() {
+			defer cancel()
+			values, err := s.Source.Fetch(ctx)
+			pending <- fetchResult{values: values, err: err}
+		}()
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil, context.DeadlineExceeded
+	case r := <-s.pending:
+		s.pending = nil
+		if r.err != nil {
+			return nil, r.err
+		}
+		return json.Marshal(r.values)
+	}
+}
+
+// sourceLayer returns a layer reading the given remote source.
+func sourceLayer(source *RemoteSource) *layer {
+	return &layer{
+		name:   SourceRemotePrefix + source.Source.Name(),
+		read:   source.read,
+		remote: source,
+	}
+}
+
+// readRemoteLayers fetches the remote sources of the given layers and returns a copy of
+// the layers in which the remote layers return the fetched values. This lets the loader
+// fetch the remote sources without holding its lock.
+func readRemoteLayers(layers []*layer) ([]*layer, error) {
+	result := make([]*layer, len(layers))
+	for i, ly := range layers {
+		if ly.remote == nil {
+			result[i] = ly
+			continue
+		}
+		conf, err := ly.read()
+		if err != nil {
+			return nil, err
+		}
+		fetched := *ly
+		fetched.read = func() (object, error) { return conf, nil }
+		result[i] = &fetched
+	}
+	return result, nil
+}
+
+// LoadWithSources loads the config files like `Load()`, then merges the values of the
+// given remote sources, in order, each one overriding the values of the previous ones.
+// The environment variables are applied last.
+//
+//	cfg, err := config.LoadWithSources(&config.RemoteSource{
+//		Source:          &config.HTTPSource{URL: "https://config.example.org/my-app"},
+//		CacheFile:       ".config-cache.json",
+//		RefreshInterval: time.Minute,
+//	})
+//
+// The sources are fetched again each time the config is reloaded. Use `Config.Watch()`
+// to refresh them periodically.
+func LoadWithSources(sources ...*RemoteSource) (*Config, error) {
+	layers := profileLayers(&osfs.FS{})
+	for _, s := range sources {
+		layers = append(layers, sourceLayer(s))
+	}
+	return defaultLoader.load(layers...)
+}
+
+// FileSource a `Source` reading a config file. The format of the file is detected from
+// its extension. This source can be used as a local stand-in for remote sources, in tests
+// or in development environments.
+type FileSource struct {
+	FS   fs.FS
+	Path string
+}
+
+// Name returns "file:" followed by the path of the file.
+func (s *FileSource) Name() string {
+	return "file:" + s.Path
+}
+
+// Fetch reads and decodes the file.
+func (s *FileSource) Fetch(_ context.Context) (map[string]any, error) {
+	conf, err := readConfigFile(s.FS, s.Path)
+	if err != nil {
+		return nil, err
+	}
+	return conf, nil
+}
+
+// HTTPSource a `Source` fetching a JSON document from an HTTP endpoint with a GET request.
+type HTTPSource struct {
+	// Client the client used to send the request. Defaults to `http.DefaultClient`.
+	Client *http.Client
+
+	// Header additional headers sent with the request, for authentication for example.
+	Header http.Header
+
+	URL string
+}
+
+// Name returns the URL of the endpoint.
+func (s *HTTPSource) Name() string {
+	return s.URL
+}
+
+// Fetch requests the endpoint and decodes the JSON response body.
+// Returns an error if the response status is not "200 OK".
+func (s *HTTPSource) Fetch(ctx context.Context) (map[string]any, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range s.Header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Accept", "application/json")
+
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil, errors.Errorf("unexpected response status %q", resp.Status)
+	}
+	return decodeJSON(resp.Body)
+}
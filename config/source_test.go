@@ -0,0 +1,285 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testSource a source returning the values or error it is configured with.
+type testSource struct {
+	err    error
+	values map[string]any
+	block  bool
+	mu     sync.Mutex
+}
+
+func (s *testSource) Name() string {
+	return "test"
+}
+
+func (s *testSource) Fetch(ctx context.Context) (map[string]any, error) {
+	s.mu.Lock()
+	values, err, block := s.values, s.err, s.block
+	s.mu.Unlock()
+	if block {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	return values, err
+}
+
+func (s *testSource) set(values map[string]any, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values = values
+	s.err = err
+}
+
+// blockingSource a source ignoring the context and returning only when released.
+type blockingSource struct {
+	release chan struct{}
+	calls   atomic.Int32
+}
+
+func (s *blockingSource) Name() string {
+	return "blocking"
+}
+
+func (s *blockingSource) Fetch(_ context.Context) (map[string]any, error) {
+	s.calls.Add(1)
+	<-s.release
+	return map[string]any{"app": map[string]any{"name": "late"}}, nil
+}
+
+func TestRemoteSource(t *testing.T) {
+	fs := fstest.MapFS{
+		"config.json": &fstest.MapFile{Data: []byte(`{"app": {"name": "file", "debug": false}, "custom": {"fromFile": "file"}}`)},
+		"remote.yml":  &fstest.MapFile{Data: []byte("app:\n  name: remote\nserver:\n  port: 1234\n")},
+	}
+
+	t.Run("merge", func(t *testing.T) {
+		t.Setenv("GOYAVE_SERVER_PORT", "4321")
+		source := &RemoteSource{Source: &FileSource{FS: fs, Path: "remote.yml"}}
+		cfg, err := newTestLoader().load(fileLayer(fs, "config.json", false), sourceLayer(source))
+		require.NoError(t, err)
+
+		assert.Equal(t, "remote", cfg.GetString("app.name"))
+		assert.Equal(t, "remote:file:remote.yml", cfg.Source("app.name"))
+		assert.False(t, cfg.GetBool("app.debug"))
+		assert.Equal(t, "config.json", cfg.Source("app.debug"))
+		assert.Equal(t, 4321, cfg.GetInt("server.port"))
+		assert.Equal(t, "env:GOYAVE_SERVER_PORT", cfg.Source("server.port"))
+		require.NoError(t, source.Err())
+	})
+
+	t.Run("unavailable", func(t *testing.T) {
+		source := &RemoteSource{Source: &FileSource{FS: fs, Path: "notafile.json"}}
+		cfg, err := newTestLoader().load(fileLayer(fs, "config.json", false), sourceLayer(source))
+		assert.Nil(t, cfg)
+		require.Error(t, err)
+		var sourceErr *SourceError
+		require.ErrorAs(t, err, &sourceErr)
+		assert.Equal(t, "file:notafile.json", sourceErr.Source)
+		assert.False(t, sourceErr.Fallback)
+		assert.Contains(t, err.Error(), "config source \"file:notafile.json\" unavailable: ")
+	})
+
+	t.Run("optional", func(t *testing.T) {
+		source := &RemoteSource{Source: &FileSource{FS: fs, Path: "notafile.json"}, Optional: true}
+		cfg, err := newTestLoader().load(fileLayer(fs, "config.json", false), sourceLayer(source))
+		require.NoError(t, err)
+		assert.Equal(t, "file", cfg.GetString("app.name"))
+		require.Error(t, source.Err())
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		source := &RemoteSource{Source: &testSource{block: true}, Timeout: 10 * time.Millisecond}
+		_, err := newTestLoader().load(sourceLayer(source))
+		require.Error(t, err)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("timeout_ignored_context", func(t *testing.T) {
+		src := &blockingSource{release: make(chan struct{})}
+		source := &RemoteSource{Source: src, Timeout: 10 * time.Millisecond}
+		_, err := source.read()
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		_, err = source.read()
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, int32(1), src.calls.Load()) // No new fetch while the previous one is running
+
+		close(src.release)
+		source.Timeout = time.Second
+		values, err := source.read()
+		require.NoError(t, err)
+		assert.Equal(t, object{"app": map[string]any{"name": "late"}}, values)
+		assert.Equal(t, int32(1), src.calls.Load())
+
+		_, err = source.read()
+		require.NoError(t, err)
+		assert.Equal(t, int32(2), src.calls.Load())
+	})
+
+	t.Run("fetch_without_lock", func(t *testing.T) {
+		src := &blockingSource{release: make(chan struct{})}
+		source := &RemoteSource{Source: src, Timeout: time.Minute}
+		loader := newTestLoader()
+
+		done := make(chan error, 1)
+		go func() {
+			_, err := loader.load(sourceLayer(source))
+			done <- err
+		}()
+		require.Eventually(t, func() bool { return src.calls.Load() == 1 }, time.Second, time.Millisecond)
+
+		// Neither the source nor the loader are locked while the source is fetched
+		require.NoError(t, source.Err())
+		assert.False(t, source.refreshDue())
+		cfg, err := loader.loadJSON(`{"app": {"name": "other"}}`)
+		require.NoError(t, err)
+		assert.Equal(t, "other", cfg.GetString("app.name"))
+
+		close(src.release)
+		require.NoError(t, <-done)
+	})
+
+	t.Run("include_not_supported", func(t *testing.T) {
+		source := &RemoteSource{Source: &testSource{values: map[string]any{"include": "other.json"}}}
+		_, err := newTestLoader().load(sourceLayer(source))
+		require.Error(t, err)
+		assert.Equal(t, "Config error: remote:test: \"include\" is not supported in remote sources", err.Error())
+	})
+
+	t.Run("cache", func(t *testing.T) {
+		cacheFile := path.Join(t.TempDir(), "cache.json")
+		src := &testSource{values: map[string]any{"app": map[string]any{"name": "cached"}}}
+		source := &RemoteSource{Source: src, CacheFile: cacheFile}
+		_, err := newTestLoader().load(sourceLayer(source))
+		require.NoError(t, err)
+
+		cache, err := os.ReadFile(cacheFile)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"app": {"name": "cached"}}`, string(cache))
+
+		// Source unavailable at startup: the cached values are used
+		src.set(nil, fmt.Errorf("connection refused"))
+		source = &RemoteSource{Source: src, CacheFile: cacheFile}
+		cfg, err := newTestLoader().load(sourceLayer(source))
+		require.NoError(t, err)
+		assert.Equal(t, "cached", cfg.GetString("app.name"))
+		assert.Equal(t, "remote:test", cfg.Source("app.name"))
+
+		var sourceErr *SourceError
+		require.ErrorAs(t, source.Err(), &sourceErr)
+		assert.True(t, sourceErr.Fallback)
+		assert.Equal(t, "config source \"test\" unavailable, using last known good values: connection refused", sourceErr.Error())
+	})
+
+	t.Run("reload_last_known_good", func(t *testing.T) {
+		src := &testSource{values: map[string]any{"app": map[string]any{"name": "first"}}}
+		source := &RemoteSource{Source: src}
+		cfg, err := newTestLoader().load(sourceLayer(source))
+		require.NoError(t, err)
+
+		src.set(map[string]any{"app": map[string]any{"name": "second"}}, nil)
+		changed, err := cfg.Reload()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"app.name"}, changed)
+		assert.Equal(t, "second", cfg.GetString("app.name"))
+
+		src.set(nil, fmt.Errorf("connection refused"))
+		changed, err = cfg.Reload()
+		require.NoError(t, err)
+		assert.Empty(t, changed)
+		assert.Equal(t, "second", cfg.GetString("app.name"))
+		require.Error(t, cfg.sourcesError())
+	})
+}
+
+func TestWatchRemoteSource(t *testing.T) {
+	src := &testSource{values: map[string]any{"app": map[string]any{"name": "initial"}}}
+	source := &RemoteSource{Source: src, RefreshInterval: 20 * time.Millisecond}
+	cfg, err := newTestLoader().load(sourceLayer(source))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	type result struct {
+		err     error
+		changed []string
+	}
+	results := make(chan result, 10)
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		cfg.Watch(ctx, 5*time.Millisecond, func(changed []string, err error) {
+			results <- result{changed: changed, err: err}
+		})
+	}()
+
+	src.set(map[string]any{"app": map[string]any{"name": "refreshed"}}, nil)
+	select {
+	case r := <-results:
+		require.NoError(t, r.err)
+		assert.Equal(t, []string{"app.name"}, r.changed)
+		assert.Equal(t, "refreshed", cfg.GetString("app.name"))
+	case <-time.After(2 * time.Second):
+		assert.Fail(t, "config not refreshed")
+	}
+
+	src.set(nil, fmt.Errorf("connection refused"))
+	select {
+	case r := <-results:
+		var sourceErr *SourceError
+		require.ErrorAs(t, r.err, &sourceErr)
+		assert.True(t, sourceErr.Fallback)
+		assert.Empty(t, r.changed)
+		assert.Equal(t, "refreshed", cfg.GetString("app.name"))
+	case <-time.After(2 * time.Second):
+		assert.Fail(t, "config not refreshed")
+	}
+
+	cancel()
+	wg.Wait()
+}
+
+func TestHTTPSource(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"app": {"name": "http"}}`))
+	}))
+	defer srv.Close()
+
+	source := &HTTPSource{URL: srv.URL, Header: http.Header{"Authorization": []string{"Bearer token"}}}
+	assert.Equal(t, srv.URL, source.Name())
+	values, err := source.Fetch(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"app": map[string]any{"name": "http"}}, values)
+
+	source = &HTTPSource{URL: srv.URL, Client: srv.Client()}
+	_, err = source.Fetch(context.Background())
+	require.Error(t, err)
+	assert.Equal(t, "unexpected response status \"401 Unauthorized\"", err.Error())
+
+	source = &HTTPSource{URL: "://invalid"}
+	_, err = source.Fetch(context.Background())
+	require.Error(t, err)
+}
@@ -0,0 +1,399 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"unicode"
+
+	"goyave.dev/goyave/v5/util/errors"
+)
+
+// Struct tags used by `Unmarshal()` and `RegisterStruct()`:
+//   - `config:"name"`: the name of the entry or category. Defaults to the name of the field in lower camel case
+//     ("MaxOpenConnections" -> "maxOpenConnections"). Use `config:"-"` to ignore a field. The "required" option
+//     marks the entry as required: `config:"name,required"`. The "restart" option marks the entry as requiring
+//     a restart to be changed (see `Entry.RequiresRestart`). The "secret" option marks the entry as secret
+//     (see `Entry.Secret`).
+//   - `default:"value"`: the default value of the entry. Slices can be written as comma-separated values or
+//     as a JSON array.
+//   - `authorized:"a,b,c"`: the comma-separated authorized values of the entry.
+//   - `min:"value"` and `max:"value"`: the bounds of the entry (see `Entry.Min` and `Entry.Max`).
+//   - `pattern:"regexp"`: the regular expression string values must match (see `Entry.Pattern`).
+const (
+	tagName       = "config"
+	tagDefault    = "default"
+	tagAuthorized = "authorized"
+	tagMin        = "min"
+	tagMax        = "max"
+	tagPattern    = "pattern"
+)
+
+// structField the config entry or category matching a struct field.
+type structField struct {
+	name     string
+	index    int
+	required bool
+	restart  bool
+	secret   bool
+}
+
+// RegisterStruct registers a config entry for each field of the struct `T`, inside
+// the given category. Nested structs define sub-categories. Defaults, authorized values
+// and required flags are defined using struct tags.
+//
+//	type DatabaseConfig struct {
+//		Driver   string `config:"driver,required" default:"postgres" authorized:"postgres,mysql"`
+//		Replicas []string
+//		Pool     struct {
+//			MaxOpen int `default:"20"`
+//		}
+//	}
+//
+//	func init() {
+//		config.RegisterStruct[DatabaseConfig]("myDatabase")
+//	}
+//
+// Supported field types are `string`, `bool`, integers, floats, `time.Duration` (registered
+// as `KindDuration`), slices of those, and structs.
+// The entries can then be retrieved with `Unmarshal()`.
+//
+// Panics if the struct definition is invalid or if an entry conflicts with an already
+// registered one (see `Register()`).
+func RegisterStruct[T any](category string) {
+	defaultLoader.registerStruct(category, reflect.TypeOf((*T)(nil)).Elem())
+}
+
+func (l *loader) registerStruct(category string, t reflect.Type) {
+	entries := map[string]Entry{}
+	if err := entriesFromStruct(category, t, entries); err != nil {
+		panic(err)
+	}
+	for key, entry := range entries {
+		l.register(key, entry)
+	}
+}
+
+func entriesFromStruct(category string, t reflect.Type, entries map[string]Entry) error {
+	if t.Kind() != reflect.Struct {
+		return errors.Errorf("cannot register config entries from non-struct type %s", t)
+	}
+	for _, f := range structFields(t) {
+		field := t.Field(f.index)
+		key := f.name
+		if category != "" {
+			key = category + "." + f.name
+		}
+		if field.Type.Kind() == reflect.Struct {
+			if err := entriesFromStruct(key, field.Type, entries); err != nil {
+				return err
+			}
+			continue
+		}
+
+		entry, err := entryFromField(key, field, f)
+		if err != nil {
+			return err
+		}
+		entries[key] = entry
+	}
+	return nil
+}
+
+func entryFromField(key string, field reflect.StructField, f structField) (Entry, error) {
+	entry := Entry{
+		AuthorizedValues: []any{},
+		Required:         f.required,
+		RequiresRestart:  f.restart,
+		Secret:           f.secret,
+	}
+	t := field.Type
+	if t.Kind() == reflect.Slice {
+		entry.IsSlice = true
+		t = t.Elem()
+	}
+	kind, ok := entryKind(t)
+	if !ok {
+		return entry, errors.Errorf("config entry %q: unsupported field type %s", key, field.Type)
+	}
+	entry.Type = kind
+	entry.Pattern = field.Tag.Get(tagPattern)
+	if entry.Pattern != "" {
+		if _, err := compilePattern(entry.Pattern); err != nil {
+			return entry, errors.Errorf("config entry %q: invalid pattern: %w", key, err)
+		}
+	}
+
+	var err error
+	if entry.Min, err = entry.boundFromTag(field, tagMin); err != nil {
+		return entry, errors.Errorf("config entry %q: %w", key, err)
+	}
+	if entry.Max, err = entry.boundFromTag(field, tagMax); err != nil {
+		return entry, errors.Errorf("config entry %q: %w", key, err)
+	}
+
+	if authorized, ok := field.Tag.Lookup(tagAuthorized); ok {
+		for _, str := range strings.Split(authorized, ",") {
+			v, err := entry.convertElement(strings.TrimSpace(str))
+			if err != nil {
+				return entry, errors.Errorf("config entry %q: invalid authorized value %q: %w", key, str, err)
+			}
+			entry.AuthorizedValues = append(entry.AuthorizedValues, v)
+		}
+	}
+
+	if def, ok := field.Tag.Lookup(tagDefault); ok {
+		v, err := entry.convertString(def)
+		if err != nil {
+			return entry, errors.Errorf("config entry %q: invalid default value %q: %w", key, def, err)
+		}
+		entry.Value = v
+		if (entry.IsSlice || isCustomKind(kind)) && !entry.tryConversion(reflect.Interface) {
+			if entry.IsSlice {
+				return entry, errors.Errorf("config entry %q: default value must be a slice of %s", key, kindName(kind))
+			}
+			return entry, errors.Errorf("config entry %q: default value must be a %s", key, kindName(kind))
+		}
+	}
+	return entry, nil
+}
+
+// boundFromTag returns the `Min` or `Max` bound defined by the given struct tag, or nil
+// if the tag is not set.
+func (e *Entry) boundFromTag(field reflect.StructField, tag string) (any, error) {
+	str, ok := field.Tag.Lookup(tag)
+	if !ok {
+		return nil, nil
+	}
+	var bound any
+	var err error
+	switch e.Type {
+	case reflect.Float64:
+		bound, err = convertScalar(str, reflect.Float64)
+	case KindDuration:
+		bound, err = e.convertElement(str)
+	default:
+		bound, err = convertScalar(str, reflect.Int)
+	}
+	if err != nil {
+		return nil, errors.Errorf("invalid %s value %q: %w", tag, str, err)
+	}
+	return bound, nil
+}
+
+// entryKind returns the kind of entry matching the given field type.
+func entryKind(t reflect.Type) (reflect.Kind, bool) {
+	if t == durationType {
+		return KindDuration, true
+	}
+	kind := t.Kind()
+	switch kind {
+	case reflect.String, reflect.Bool:
+		return kind, true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return reflect.Int, true
+	case reflect.Float32, reflect.Float64:
+		return reflect.Float64, true
+	default:
+		return reflect.Invalid, false
+	}
+}
+
+// structFields returns the exported fields of the given struct type that are not ignored
+// with the `config:"-"` tag, with their entry name.
+func structFields(t reflect.Type) []structField {
+	fields := make([]structField, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		f := structField{index: i, name: lowerCamelCase(field.Name)}
+		if tag, ok := field.Tag.Lookup(tagName); ok {
+			name, options, _ := strings.Cut(tag, ",")
+			if name == "-" {
+				continue
+			}
+			if name != "" {
+				f.name = name
+			}
+			for _, option := range strings.Split(options, ",") {
+				switch option {
+				case "required":
+					f.required = true
+				case "restart":
+					f.restart = true
+				case "secret":
+					f.secret = true
+				}
+			}
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+// lowerCamelCase converts a Go field name to the lower camel case convention
+// used by config entries ("MaxOpenConnections" -> "maxOpenConnections", "URLPath" -> "urlPath").
+func lowerCamelCase(name string) string {
+	runes := []rune(name)
+	for i := range runes {
+		if !unicode.IsUpper(runes[i]) {
+			break
+		}
+		if i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1]) {
+			break
+		}
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	return string(runes)
+}
+
+// Unmarshal decodes the config category identified by the given dot-separated path into
+// a new struct of type `T`. An empty key decodes the whole configuration. Fields are matched
+// with the config entries using the same rules as `RegisterStruct()`. Fields matching an
+// unset entry are left to their zero value.
+//
+//	dbConfig, err := config.Unmarshal[DatabaseConfig](cfg, "myDatabase")
+//
+// Returns an error if the category doesn't exist or if a value cannot be assigned to its field.
+func Unmarshal[T any](c *Config, key string) (T, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	var result T
+	category := c.config
+	if key != "" {
+		cat, ok := c.category(key)
+		if !ok {
+			return result, errors.Errorf("config category %q doesn't exist", key)
+		}
+		category = cat
+	}
+
+	v := reflect.ValueOf(&result).Elem()
+	if v.Kind() != reflect.Struct {
+		return result, errors.Errorf("cannot unmarshal config into non-struct type %s", v.Type())
+	}
+	if err := unmarshalCategory(category, key, v); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// category returns the category identified by the given dot-separated path.
+// The caller must hold the config lock.
+func (c *Config) category(key string) (object, bool) {
+	current := c.config
+	for _, segment := range strings.Split(key, ".") {
+		category, ok := current[segment].(object)
+		if !ok {
+			return nil, false
+		}
+		current = category
+	}
+	return current, true
+}
+
+func unmarshalCategory(category object, key string, v reflect.Value) error {
+	for _, f := range structFields(v.Type()) {
+		subKey := f.name
+		if key != "" {
+			subKey = key + "." + f.name
+		}
+		field := v.Field(f.index)
+		value, ok := category[f.name]
+		if !ok {
+			continue
+		}
+
+		if field.Kind() == reflect.Struct {
+			sub, ok := value.(object)
+			if !ok {
+				return errors.Errorf("config entry %q is not a category", subKey)
+			}
+			if err := unmarshalCategory(sub, subKey, field); err != nil {
+				return err
+			}
+			continue
+		}
+
+		entry, ok := value.(*Entry)
+		if !ok {
+			return errors.Errorf("config entry %q is a category", subKey)
+		}
+		if entry.Value == nil {
+			continue
+		}
+		if !assignValue(field, reflect.ValueOf(entry.Value)) {
+			return errors.Errorf("config entry %q of type %T cannot be assigned to field of type %s", subKey, entry.Value, field.Type())
+		}
+	}
+	return nil
+}
+
+// assignValue sets the given field to the given config value, converting numbers
+// and slice elements if needed. Returns false if the value cannot be assigned.
+func assignValue(field reflect.Value, value reflect.Value) bool {
+	if value.Kind() == reflect.Interface {
+		value = value.Elem()
+	}
+	if !value.IsValid() {
+		return true
+	}
+	t := field.Type()
+	if value.Type() == t {
+		field.Set(value)
+		return true
+	}
+
+	switch t.Kind() {
+	case reflect.Interface:
+		if !value.Type().AssignableTo(t) {
+			return false
+		}
+		field.Set(value)
+		return true
+	case reflect.Slice:
+		if value.Kind() != reflect.Slice {
+			return false
+		}
+		slice := reflect.MakeSlice(t, value.Len(), value.Len())
+		for i := 0; i < value.Len(); i++ {
+			if !assignValue(slice.Index(i), value.Index(i)) {
+				return false
+			}
+		}
+		field.Set(slice)
+		return true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, ok := convertInt(value.Interface())
+		if !ok || field.OverflowInt(int64(i)) {
+			return false
+		}
+		field.SetInt(int64(i))
+		return true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		i, ok := convertInt(value.Interface())
+		if !ok || i < 0 || field.OverflowUint(uint64(i)) {
+			return false
+		}
+		field.SetUint(uint64(i))
+		return true
+	case reflect.Float32, reflect.Float64:
+		switch n := value.Interface().(type) {
+		case float64:
+			field.SetFloat(n)
+		case int:
+			field.SetFloat(float64(n))
+		default:
+			return false
+		}
+		return true
+	default:
+		if value.Kind() != t.Kind() || !value.Type().ConvertibleTo(t) {
+			return false
+		}
+		field.Set(value.Convert(t))
+		return true
+	}
+}
@@ -0,0 +1,286 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testStructConfig struct {
+	Pool struct {
+		Timeout  float32       `default:"1.5" min:"0.5"`
+		MaxOpen  uint16        `default:"20" max:"100"`
+		Lifetime time.Duration `default:"5m" min:"1s"`
+	}
+	Any      any    `config:"-"`
+	Driver   string `config:"driver,required,restart" default:"postgres" authorized:"postgres,mysql"`
+	Host     string `config:",restart,secret"`
+	URLPath  string `default:"/" pattern:"^/" min:"1"`
+	Replicas []string
+	Ports    []int `default:"[1, 2]"`
+	_        int
+	Debug    bool `config:"verbose"`
+}
+
+func TestLowerCamelCase(t *testing.T) {
+	cases := map[string]string{
+		"Name":               "name",
+		"MaxOpenConnections": "maxOpenConnections",
+		"URL":                "url",
+		"URLPath":            "urlPath",
+		"ID":                 "id",
+		"A":                  "a",
+		"already":            "already",
+	}
+	for name, want := range cases {
+		assert.Equal(t, want, lowerCamelCase(name), name)
+	}
+}
+
+func TestRegisterStruct(t *testing.T) {
+	t.Run("register", func(t *testing.T) {
+		l := newTestLoader()
+		l.registerStruct("custom", reflect.TypeOf(testStructConfig{}))
+
+		expected := object{
+			"pool": object{
+				"timeout": &Entry{
+					Value:            1.5,
+					Min:              0.5,
+					AuthorizedValues: []any{},
+					Type:             reflect.Float64,
+					IsSlice:          false,
+				},
+				"maxOpen": &Entry{
+					Value:            20,
+					Max:              100,
+					AuthorizedValues: []any{},
+					Type:             reflect.Int,
+					IsSlice:          false,
+				},
+				"lifetime": &Entry{
+					Value:            5 * time.Minute,
+					Min:              time.Second,
+					AuthorizedValues: []any{},
+					Type:             KindDuration,
+					IsSlice:          false,
+				},
+			},
+			"driver": &Entry{
+				Value:            "postgres",
+				AuthorizedValues: []any{"postgres", "mysql"},
+				Type:             reflect.String,
+				IsSlice:          false,
+				Required:         true,
+				RequiresRestart:  true,
+			},
+			"host": &Entry{
+				Value:            nil,
+				AuthorizedValues: []any{},
+				Type:             reflect.String,
+				IsSlice:          false,
+				RequiresRestart:  true,
+				Secret:           true,
+			},
+			"urlPath": &Entry{
+				Value:            "/",
+				Min:              1,
+				Pattern:          "^/",
+				AuthorizedValues: []any{},
+				Type:             reflect.String,
+				IsSlice:          false,
+			},
+			"replicas": &Entry{
+				Value:            nil,
+				AuthorizedValues: []any{},
+				Type:             reflect.String,
+				IsSlice:          true,
+			},
+			"ports": &Entry{
+				Value:            []int{1, 2},
+				AuthorizedValues: []any{},
+				Type:             reflect.Int,
+				IsSlice:          true,
+			},
+			"verbose": &Entry{
+				Value:            nil,
+				AuthorizedValues: []any{},
+				Type:             reflect.Bool,
+				IsSlice:          false,
+			},
+		}
+		assert.Equal(t, expected, l.defaults["custom"])
+
+		// Registering twice the same struct doesn't conflict
+		assert.NotPanics(t, func() {
+			l.registerStruct("custom", reflect.TypeOf(testStructConfig{}))
+		})
+	})
+
+	t.Run("conflict", func(t *testing.T) {
+		l := newTestLoader()
+		l.register("custom.host", Entry{
+			Value:            "localhost",
+			AuthorizedValues: []any{},
+			Type:             reflect.String,
+			IsSlice:          false,
+			Required:         true,
+		})
+		assert.Panics(t, func() {
+			l.registerStruct("custom", reflect.TypeOf(testStructConfig{}))
+		})
+	})
+
+	cases := []struct {
+		value any
+		desc  string
+	}{
+		{desc: "not_a_struct", value: 1},
+		{desc: "unsupported_type", value: struct{ M map[string]string }{}},
+		{desc: "invalid_default", value: struct {
+			I int `default:"abc"`
+		}{}},
+		{desc: "invalid_default_slice", value: struct {
+			I []int `default:"[\"a\"]"`
+		}{}},
+		{desc: "invalid_authorized", value: struct {
+			B bool `authorized:"true,abc"`
+		}{}},
+		{desc: "invalid_default_duration", value: struct {
+			D time.Duration `default:"abc"`
+		}{}},
+		{desc: "invalid_min", value: struct {
+			I int `min:"abc"`
+		}{}},
+		{desc: "invalid_max_duration", value: struct {
+			D time.Duration `max:"abc"`
+		}{}},
+		{desc: "invalid_pattern", value: struct {
+			S string `pattern:"["`
+		}{}},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			l := newTestLoader()
+			assert.Panics(t, func() {
+				l.registerStruct("custom", reflect.TypeOf(c.value))
+			})
+		})
+	}
+}
+
+func TestUnmarshal(t *testing.T) {
+	l := newTestLoader()
+	l.registerStruct("custom", reflect.TypeOf(testStructConfig{}))
+
+	t.Run("unmarshal", func(t *testing.T) {
+		cfg, err := l.loadJSON(`{"custom": {"driver": "mysql", "replicas": ["a", "b"], "verbose": true, "pool": {"maxOpen": 5}}}`)
+		require.NoError(t, err)
+
+		c, err := Unmarshal[testStructConfig](cfg, "custom")
+		require.NoError(t, err)
+
+		assert.Equal(t, "mysql", c.Driver)
+		assert.Empty(t, c.Host)
+		assert.Equal(t, "/", c.URLPath)
+		assert.Equal(t, []string{"a", "b"}, c.Replicas)
+		assert.Equal(t, []int{1, 2}, c.Ports)
+		assert.True(t, c.Debug)
+		assert.Equal(t, uint16(5), c.Pool.MaxOpen)
+		assert.InEpsilon(t, float32(1.5), c.Pool.Timeout, 0)
+		assert.Equal(t, 5*time.Minute, c.Pool.Lifetime)
+		assert.Nil(t, c.Any)
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		cfg, err := l.loadJSON(`{"custom": {"driver": "sqlite"}}`)
+		assert.Nil(t, cfg)
+		require.Error(t, err)
+		assert.Equal(t, "Config error: \n\t- \"custom.driver\" must have one of the following values: [postgres mysql]", err.Error())
+
+		cfg, err = l.loadJSON(`{"custom": {"urlPath": "path", "pool": {"lifetime": "10ms"}}}`)
+		assert.Nil(t, cfg)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "\n\t- \"custom.urlPath\" must match the pattern \"^/\"")
+		assert.Contains(t, err.Error(), "\n\t- \"custom.pool.lifetime\" must be greater than or equal to 1s")
+	})
+
+	t.Run("unregistered", func(t *testing.T) {
+		type server struct {
+			Extra  map[string]any `config:"-"`
+			Any    any            `config:"port"`
+			Host   string
+			Unset  string
+			Port   int64 `config:"port"`
+			Offset int8
+			Values []float32
+		}
+		type root struct {
+			Server server
+			App    struct {
+				Name string
+			}
+		}
+		cfg, err := l.loadJSON(`{"server": {"offset": 3, "values": [1, 2.5]}}`)
+		require.NoError(t, err)
+
+		c, err := Unmarshal[root](cfg, "")
+		require.NoError(t, err)
+		assert.Equal(t, "goyave", c.App.Name)
+		assert.Equal(t, "127.0.0.1", c.Server.Host)
+		assert.Equal(t, int64(8080), c.Server.Port)
+		assert.Equal(t, 8080, c.Server.Any)
+		assert.Equal(t, int8(3), c.Server.Offset)
+		assert.Equal(t, []float32{1, 2.5}, c.Server.Values)
+		assert.Empty(t, c.Server.Unset)
+	})
+
+	cfg, err := l.loadJSON(`{"custom": {"driver": "mysql"}, "negative": -1, "big": 300, "float": 1.5}`)
+	require.NoError(t, err)
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := Unmarshal[testStructConfig](cfg, "notacategory")
+		require.Error(t, err)
+		assert.Equal(t, "config category \"notacategory\" doesn't exist", err.Error())
+
+		_, err = Unmarshal[testStructConfig](cfg, "custom.driver")
+		require.Error(t, err)
+
+		_, err = Unmarshal[int](cfg, "custom")
+		require.Error(t, err)
+		assert.Equal(t, "cannot unmarshal config into non-struct type int", err.Error())
+
+		_, err = Unmarshal[struct{ Custom string }](cfg, "")
+		require.Error(t, err)
+		assert.Equal(t, "config entry \"custom\" is a category", err.Error())
+
+		_, err = Unmarshal[struct{ Custom struct{ Driver struct{} } }](cfg, "")
+		require.Error(t, err)
+		assert.Equal(t, "config entry \"custom.driver\" is not a category", err.Error())
+
+		_, err = Unmarshal[struct{ Driver int }](cfg, "custom")
+		require.Error(t, err)
+		assert.Equal(t, "config entry \"custom.driver\" of type string cannot be assigned to field of type int", err.Error())
+
+		_, err = Unmarshal[struct{ Negative uint }](cfg, "")
+		require.Error(t, err)
+
+		_, err = Unmarshal[struct{ Big int8 }](cfg, "")
+		require.Error(t, err)
+
+		_, err = Unmarshal[struct{ Float int }](cfg, "")
+		require.Error(t, err)
+
+		_, err = Unmarshal[struct{ Big string }](cfg, "")
+		require.Error(t, err)
+
+		_, err = Unmarshal[struct{ Big []int }](cfg, "")
+		require.Error(t, err)
+
+		_, err = Unmarshal[struct{ Big bool }](cfg, "")
+		require.Error(t, err)
+	})
+}
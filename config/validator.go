@@ -0,0 +1,77 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Validator a function validating the configuration as a whole. Validators are meant to
+// check constraints involving several entries, which cannot be expressed with the `Entry` fields.
+//
+// The returned error message is added to the `config.Error`. Several violations can be
+// reported at once by returning an error created with `errors.Join()`.
+type Validator func(cfg *Config) error
+
+// RegisterValidator registers a validator executed every time a config is loaded or reloaded.
+// Validators are only executed if all the entries are valid, so they can safely use the
+// typed getters (`Config.GetInt()`, etc).
+//
+// Like entries, validators should be registered in an "init()" function.
+func RegisterValidator(validator Validator) {
+	defaultLoader.mu.Lock()
+	defer defaultLoader.mu.Unlock()
+	defaultLoader.validators = append(defaultLoader.validators, validator)
+}
+
+func defaultValidators() []Validator {
+	return []Validator{validateDatabaseConnections}
+}
+
+func validateDatabaseConnections(cfg *Config) error {
+	maxOpen := cfg.GetInt("database.maxOpenConnections")
+	maxIdle := cfg.GetInt("database.maxIdleConnections")
+	if maxOpen > 0 && maxIdle > maxOpen {
+		return fmt.Errorf("%q must be less than or equal to %q", "database.maxIdleConnections", "database.maxOpenConnections")
+	}
+	return nil
+}
+
+// runValidators executes the registered validators on the given config and
+// aggregates their violations.
+func (l *loader) runValidators(cfg *Config) error {
+	message := ""
+	for _, validator := range l.validators {
+		for _, violation := range runValidator(validator, cfg) {
+			message += "\n\t- " + violation
+		}
+	}
+	if message != "" {
+		return fmt.Errorf("%s", message)
+	}
+	return nil
+}
+
+func runValidator(validator Validator, cfg *Config) (violations []string) {
+	defer func() {
+		if r := recover(); r != nil {
+			violations = []string{fmt.Sprintf("validator panicked: %v", r)}
+		}
+	}()
+	err := validator(cfg)
+	if err == nil {
+		return nil
+	}
+	return flattenErrors(err)
+}
+
+// flattenErrors returns the messages of the errors joined with `errors.Join()`.
+func flattenErrors(err error) []string {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		messages := []string{}
+		for _, e := range joined.Unwrap() {
+			messages = append(messages, flattenErrors(e)...)
+		}
+		return messages
+	}
+	return strings.Split(err.Error(), "\n")
+}
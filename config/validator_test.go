@@ -0,0 +1,82 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidators(t *testing.T) {
+	t.Run("RegisterValidator", func(t *testing.T) {
+		defaultLoader.mu.RLock()
+		previous := defaultLoader.validators
+		defaultLoader.mu.RUnlock()
+		t.Cleanup(func() {
+			defaultLoader.mu.Lock()
+			defaultLoader.validators = previous
+			defaultLoader.mu.Unlock()
+		})
+
+		RegisterValidator(func(_ *Config) error { return nil })
+		assert.Len(t, defaultLoader.validators, len(previous)+1)
+	})
+
+	t.Run("database_connections", func(t *testing.T) {
+		cfg, err := newTestLoader().loadJSON(`{"database": {"maxOpenConnections": 0, "maxIdleConnections": 30}}`)
+		require.NoError(t, err)
+		assert.NotNil(t, cfg)
+
+		cfg, err = newTestLoader().loadJSON(`{"database": {"maxOpenConnections": 10, "maxIdleConnections": 30}}`)
+		assert.Nil(t, cfg)
+		require.Error(t, err)
+		assert.Equal(t, "Config error: \n\t- \"database.maxIdleConnections\" must be less than or equal to \"database.maxOpenConnections\"", err.Error())
+	})
+
+	t.Run("aggregated", func(t *testing.T) {
+		l := newTestLoader()
+		l.register("custom.min", Entry{Value: 0, AuthorizedValues: []any{}, Type: reflect.Int, Required: true})
+		l.register("custom.max", Entry{Value: 10, AuthorizedValues: []any{}, Type: reflect.Int, Required: true})
+		l.validators = append(l.validators,
+			func(cfg *Config) error {
+				if cfg.GetInt("custom.min") > cfg.GetInt("custom.max") {
+					return fmt.Errorf("%q must be less than %q", "custom.min", "custom.max")
+				}
+				return nil
+			},
+			func(_ *Config) error {
+				return errors.Join(errors.New("first violation"), errors.New("second violation"))
+			},
+			func(_ *Config) error {
+				panic("validator panic")
+			},
+		)
+
+		cfg, err := l.loadJSON(`{"custom": {"min": 20}, "database": {"maxOpenConnections": 1}}`)
+		assert.Nil(t, cfg)
+		require.Error(t, err)
+		assert.Equal(t, "Config error: "+
+			"\n\t- \"database.maxIdleConnections\" must be less than or equal to \"database.maxOpenConnections\""+
+			"\n\t- \"custom.min\" must be less than \"custom.max\""+
+			"\n\t- first violation"+
+			"\n\t- second violation"+
+			"\n\t- validator panicked: validator panic", err.Error())
+	})
+
+	t.Run("not_executed_if_invalid_entries", func(t *testing.T) {
+		l := newTestLoader()
+		called := false
+		l.validators = append(l.validators, func(_ *Config) error {
+			called = true
+			return nil
+		})
+
+		_, err := l.loadJSON(`{"database": {"maxOpenConnections": "abc"}}`)
+		require.Error(t, err)
+		assert.Equal(t, "Config error: \n\t- \"database.maxOpenConnections\" type must be int", err.Error())
+		assert.False(t, called)
+	})
+}
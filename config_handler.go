@@ -0,0 +1,36 @@
+package goyave
+
+import (
+	"net/http"
+
+	"goyave.dev/goyave/v5/config"
+)
+
+// ConfigDumpHandler a handler writing the effective configuration of the server as JSON.
+// The value of secret entries is redacted (see `config.Config.Dump()`).
+//
+// Because the configuration can contain sensitive information, this handler only works
+// if "app.debug" is enabled. Otherwise, it responds with "404 Not Found".
+//
+//	router.Get("/debug/config", goyave.ConfigDumpHandler)
+func ConfigDumpHandler(response *Response, _ *Request) {
+	cfg := response.server.Config()
+	if !cfg.GetBool("app.debug") {
+		response.Status(http.StatusNotFound)
+		return
+	}
+	response.JSON(http.StatusOK, cfg.Dump())
+}
+
+// ConfigSchemaHandler a handler writing the JSON Schema of the config files, generated from
+// the registered config entries (see `config.JSONSchema()`).
+//
+// Like `ConfigDumpHandler`, this handler only works if "app.debug" is enabled. Otherwise, it
+// responds with "404 Not Found".
+func ConfigSchemaHandler(response *Response, _ *Request) {
+	if !response.server.Config().GetBool("app.debug") {
+		response.Status(http.StatusNotFound)
+		return
+	}
+	response.JSON(http.StatusOK, config.JSONSchema())
+}
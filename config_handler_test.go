@@ -0,0 +1,51 @@
+package goyave
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigHandlers(t *testing.T) {
+	cases := []struct {
+		handler Handler
+		desc    string
+	}{
+		{desc: "ConfigDumpHandler", handler: ConfigDumpHandler},
+		{desc: "ConfigSchemaHandler", handler: ConfigSchemaHandler},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			req, resp, recorder := prepareStatusHandlerTest()
+			resp.server.config.Set("app.debug", true)
+			resp.server.config.Set("database.password", "secret")
+			c.handler(resp, req)
+
+			res := recorder.Result()
+			body, err := io.ReadAll(res.Body)
+			assert.NoError(t, res.Body.Close())
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusOK, res.StatusCode)
+			assert.NotContains(t, string(body), `"secret"`)
+
+			result := map[string]any{}
+			require.NoError(t, json.Unmarshal(body, &result))
+			assert.Contains(t, string(body), `"database"`)
+		})
+
+		t.Run(c.desc+"_no_debug", func(t *testing.T) {
+			req, resp, recorder := prepareStatusHandlerTest()
+			resp.server.config.Set("app.debug", false)
+			c.handler(resp, req)
+
+			res := recorder.Result()
+			assert.NoError(t, res.Body.Close())
+			assert.Equal(t, http.StatusNotFound, resp.GetStatus())
+		})
+	}
+}
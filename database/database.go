@@ -2,7 +2,6 @@ package database
 
 import (
 	"errors"
-	"time"
 
 	"gorm.io/gorm"
 	"goyave.dev/goyave/v5/config"
@@ -43,6 +42,10 @@ func New(cfg *config.Config, logger func() *slog.Logger) (*gorm.DB, error) {
 		return db, errorutil.New(err)
 	}
 
+	if err := db.Use(&OptimisticLockPlugin{}); err != nil {
+		return db, errorutil.New(err)
+	}
+
 	return db, initSQLDB(cfg, db)
 }
 
@@ -60,6 +63,10 @@ func NewFromDialector(cfg *config.Config, logger func() *slog.Logger, dialector
 		return db, errorutil.New(err)
 	}
 
+	if err := db.Use(&OptimisticLockPlugin{}); err != nil {
+		return db, errorutil.New(err)
+	}
+
 	return db, initSQLDB(cfg, db)
 }
 
@@ -68,8 +75,11 @@ func newConfig(cfg *config.Config, logger func() *slog.Logger) *gorm.Config {
 		// Stay silent about DB operations when not in debug mode
 		logger = nil
 	}
+	l := NewLogger(logger)
+	l.SlowThreshold = cfg.GetDuration("database.slowQueryThreshold")
+	l.RepeatedQueryThreshold = cfg.GetInt("database.repeatedQueryThreshold")
 	return &gorm.Config{
-		Logger:                                   NewLogger(logger),
+		Logger:                                   l,
 		SkipDefaultTransaction:                   cfg.GetBool("database.config.skipDefaultTransaction"),
 		DryRun:                                   cfg.GetBool("database.config.dryRun"),
 		PrepareStmt:                              cfg.GetBool("database.config.prepareStmt"),
@@ -82,8 +92,8 @@ func newConfig(cfg *config.Config, logger func() *slog.Logger) *gorm.Config {
 
 func initTimeoutPlugin(cfg *config.Config, db *gorm.DB) error {
 	timeoutPlugin := &TimeoutPlugin{
-		ReadTimeout:  time.Duration(cfg.GetInt("database.defaultReadQueryTimeout")) * time.Millisecond,
-		WriteTimeout: time.Duration(cfg.GetInt("database.defaultWriteQueryTimeout")) * time.Millisecond,
+		ReadTimeout:  cfg.GetDuration("database.defaultReadQueryTimeout"),
+		WriteTimeout: cfg.GetDuration("database.defaultWriteQueryTimeout"),
 	}
 	return errorutil.New(db.Use(timeoutPlugin))
 }
@@ -98,6 +108,6 @@ func initSQLDB(cfg *config.Config, db *gorm.DB) error {
 	}
 	sqlDB.SetMaxOpenConns(cfg.GetInt("database.maxOpenConnections"))
 	sqlDB.SetMaxIdleConns(cfg.GetInt("database.maxIdleConnections"))
-	sqlDB.SetConnMaxLifetime(time.Duration(cfg.GetInt("database.maxLifetime")) * time.Second)
+	sqlDB.SetConnMaxLifetime(cfg.GetDuration("database.maxLifetime"))
 	return nil
 }
@@ -56,6 +56,8 @@ func TestNewDatabase(t *testing.T) {
 		cfg.Set("database.maxLifetime", 123)
 		cfg.Set("database.defaultReadQueryTimeout", 123)
 		cfg.Set("database.defaultWriteQueryTimeout", 123)
+		cfg.Set("database.slowQueryThreshold", 123)
+		cfg.Set("database.repeatedQueryThreshold", 3)
 		cfg.Set("database.config.skipDefaultTransaction", true)
 		cfg.Set("database.config.dryRun", true)
 		cfg.Set("database.config.prepareStmt", false)
@@ -74,6 +76,8 @@ func TestNewDatabase(t *testing.T) {
 			l, ok := db.Config.Logger.(*Logger)
 			if assert.True(t, ok) {
 				assert.NotNil(t, l.slogger)
+				assert.Equal(t, 123*time.Millisecond, l.SlowThreshold)
+				assert.Equal(t, 3, l.RepeatedQueryThreshold)
 			}
 		}
 
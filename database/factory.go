@@ -1,6 +1,9 @@
 package database
 
 import (
+	"math/rand/v2"
+	"time"
+
 	"gorm.io/gorm"
 	"goyave.dev/copier"
 	"goyave.dev/goyave/v5/util/errors"
@@ -8,17 +11,31 @@ import (
 
 // Factory an object used to generate records or seed the database.
 type Factory[T any] struct {
-	generator func() *T
+	generator func(r *rand.Rand) *T
 	override  *T
+	states    map[string]*T
+	rand      *rand.Rand
+	applied   []string
 	BatchSize int
 }
 
 // NewFactory create a new Factory.
 // The given generator function will be used to generate records.
 func NewFactory[T any](generator func() *T) *Factory[T] {
+	return NewRandFactory(func(_ *rand.Rand) *T { return generator() })
+}
+
+// NewRandFactory create a new Factory.
+// The given generator function will be used to generate records. It receives
+// the factory's random source so the generated data can be made reproducible
+// using `Factory.Seed()`.
+func NewRandFactory[T any](generator func(r *rand.Rand) *T) *Factory[T] {
+	seed := uint64(time.Now().UnixNano())
 	return &Factory[T]{
 		generator: generator,
 		override:  nil,
+		states:    map[string]*T{},
+		rand:      rand.New(rand.NewPCG(seed, seed)),
 		BatchSize: 100,
 	}
 }
@@ -33,6 +50,56 @@ func (f *Factory[T]) Override(override *T) *Factory[T] {
 	return f
 }
 
+// Seed replace the random source given to the generator with a new one
+// initialized with the given seed. Two factories using the same generator
+// and the same seed generate identical records.
+// Returns the same instance of `Factory` so this method can be chained.
+func (f *Factory[T]) Seed(seed uint64) *Factory[T] {
+	f.rand = rand.New(rand.NewPCG(seed, seed))
+	return f
+}
+
+// WithRand replace the random source given to the generator.
+// This is useful to share a single random source between multiple factories
+// so a whole seeding process is reproducible.
+// Returns the same instance of `Factory` so this method can be chained.
+func (f *Factory[T]) WithRand(r *rand.Rand) *Factory[T] {
+	f.rand = r
+	return f
+}
+
+// Rand returns the random source given to the generator.
+func (f *Factory[T]) Rand() *rand.Rand {
+	return f.rand
+}
+
+// State define a named override preset. States are applied using `Factory.WithState()`.
+// Values present in the state model will replace the ones in the generated records.
+// This function expects a struct pointer as parameter.
+// Returns the same instance of `Factory` so this method can be chained.
+func (f *Factory[T]) State(name string, state *T) *Factory[T] {
+	f.states[name] = state
+	return f
+}
+
+// WithState returns a copy of this factory that applies the given states, in order,
+// to the generated records. States are applied before the override model.
+// The states applied by the original factory are kept.
+//
+// Panics if one of the given states doesn't exist.
+func (f *Factory[T]) WithState(names ...string) *Factory[T] {
+	for _, name := range names {
+		if _, ok := f.states[name]; !ok {
+			panic(errors.Errorf("factory state %q doesn't exist", name))
+		}
+	}
+	factory := *f
+	factory.applied = make([]string, 0, len(f.applied)+len(names))
+	factory.applied = append(factory.applied, f.applied...)
+	factory.applied = append(factory.applied, names...)
+	return &factory
+}
+
 // Generate a number of records using the given factory.
 func (f *Factory[T]) Generate(count int) []*T {
 	if count <= 0 {
@@ -42,7 +109,12 @@ func (f *Factory[T]) Generate(count int) []*T {
 	slice := make([]*T, 0, count)
 
 	for i := 0; i < count; i++ {
-		record := f.generator()
+		record := f.generator(f.rand)
+		for _, state := range f.applied {
+			if err := copier.CopyWithOption(record, f.states[state], copier.Option{IgnoreEmpty: true, DeepCopy: true, CaseSensitive: true}); err != nil {
+				panic(errors.NewSkip(err, 3))
+			}
+		}
 		if f.override != nil {
 			if err := copier.CopyWithOption(record, f.override, copier.Option{IgnoreEmpty: true, DeepCopy: true, CaseSensitive: true}); err != nil {
 				panic(errors.NewSkip(err, 3))
@@ -63,3 +135,70 @@ func (f *Factory[T]) Save(db *gorm.DB, count int) []*T {
 	}
 	return records
 }
+
+// GenerateFor generate `count` records for each of the given parents using the given
+// factory. The `link` function is called for each generated record and should be used
+// to associate the child with its parent (e.g. by setting a foreign key).
+//
+//	posts := database.GenerateFor(users, postFactory, 3, func(u *User, p *Post) {
+//		p.AuthorID = u.ID
+//	})
+func GenerateFor[P, T any](parents []*P, factory *Factory[T], count int, link func(parent *P, child *T)) []*T {
+	records := make([]*T, 0, len(parents)*max(count, 0))
+	for _, parent := range parents {
+		children := factory.Generate(count)
+		for _, child := range children {
+			link(parent, child)
+		}
+		records = append(records, children...)
+	}
+	return records
+}
+
+// SaveFor generate `count` records for each of the given parents using the given
+// factory, insert them in the database and return the inserted records.
+// See `GenerateFor()` for more details.
+func SaveFor[P, T any](db *gorm.DB, parents []*P, factory *Factory[T], count int, link func(parent *P, child *T)) []*T {
+	records := GenerateFor(parents, factory, count, link)
+	if len(records) == 0 {
+		return records
+	}
+
+	if err := db.CreateInBatches(records, factory.BatchSize).Error; err != nil {
+		panic(errors.New(err))
+	}
+	return records
+}
+
+// GenerateWithRandomParent generate a number of records using the given factory and
+// associate each of them with a parent picked randomly from the given slice, using
+// the factory's random source. This is useful to reuse existing parents instead of
+// creating new ones for each record.
+//
+// Panics if `parents` is empty.
+func GenerateWithRandomParent[P, T any](parents []*P, factory *Factory[T], count int, link func(parent *P, child *T)) []*T {
+	if len(parents) == 0 {
+		panic(errors.New("cannot pick a random parent from an empty slice"))
+	}
+	records := factory.Generate(count)
+	for _, record := range records {
+		link(parents[factory.rand.IntN(len(parents))], record)
+	}
+	return records
+}
+
+// SaveWithRandomParent generate a number of records using the given factory, associate
+// each of them with a parent picked randomly from the given slice, insert them in the
+// database and return the inserted records.
+// See `GenerateWithRandomParent()` for more details.
+func SaveWithRandomParent[P, T any](db *gorm.DB, parents []*P, factory *Factory[T], count int, link func(parent *P, child *T)) []*T {
+	records := GenerateWithRandomParent(parents, factory, count, link)
+	if len(records) == 0 {
+		return records
+	}
+
+	if err := db.CreateInBatches(records, factory.BatchSize).Error; err != nil {
+		panic(errors.New(err))
+	}
+	return records
+}
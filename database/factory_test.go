@@ -1,6 +1,8 @@
 package database
 
 import (
+	"fmt"
+	"math/rand/v2"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -60,6 +62,71 @@ func TestFactory(t *testing.T) {
 		assert.Equal(t, expected, records)
 	})
 
+	t.Run("State", func(t *testing.T) {
+		factory := NewFactory(userGenerator)
+		factory.State("admin", &TestUser{Name: "admin"})
+		factory.State("example", &TestUser{Email: "admin@example.com", Name: "example"})
+
+		records := factory.WithState("admin").Generate(1)
+		assert.Equal(t, []*TestUser{{Name: "admin", Email: "johndoe@example.org"}}, records)
+
+		// States are applied in order
+		records = factory.WithState("admin", "example").Generate(1)
+		assert.Equal(t, []*TestUser{{Name: "example", Email: "admin@example.com"}}, records)
+
+		// Override has priority over states
+		records = factory.WithState("example").Override(&TestUser{Name: "override"}).Generate(1)
+		assert.Equal(t, []*TestUser{{Name: "override", Email: "admin@example.com"}}, records)
+
+		// The original factory is not modified
+		assert.Empty(t, factory.applied)
+		assert.Nil(t, factory.override)
+
+		assert.Panics(t, func() {
+			factory.WithState("unknown")
+		})
+	})
+
+	t.Run("Seed", func(t *testing.T) {
+		generator := func(r *rand.Rand) *TestUser {
+			return &TestUser{Name: fmt.Sprintf("user-%d", r.IntN(1000000))}
+		}
+		a := NewRandFactory(generator).Seed(1234).Generate(5)
+		b := NewRandFactory(generator).Seed(1234).Generate(5)
+		assert.Equal(t, a, b)
+
+		r := rand.New(rand.NewPCG(1, 2))
+		factory := NewRandFactory(generator).WithRand(r)
+		assert.Same(t, r, factory.Rand())
+	})
+
+	t.Run("GenerateFor", func(t *testing.T) {
+		users := []*TestUser{{ID: 1}, {ID: 2}}
+		articles := GenerateFor(users, NewFactory(articleGenerator), 2, func(u *TestUser, a *TestArticle) {
+			a.AuthorID = u.ID
+		})
+		if assert.Len(t, articles, 4) {
+			assert.Equal(t, []uint{1, 1, 2, 2}, []uint{articles[0].AuthorID, articles[1].AuthorID, articles[2].AuthorID, articles[3].AuthorID})
+		}
+
+		assert.Empty(t, GenerateFor(users, NewFactory(articleGenerator), 0, func(_ *TestUser, _ *TestArticle) {}))
+	})
+
+	t.Run("GenerateWithRandomParent", func(t *testing.T) {
+		users := []*TestUser{{ID: 1}, {ID: 2}, {ID: 3}}
+		articles := GenerateWithRandomParent(users, NewFactory(articleGenerator).Seed(1), 10, func(u *TestUser, a *TestArticle) {
+			a.AuthorID = u.ID
+		})
+		assert.Len(t, articles, 10)
+		for _, a := range articles {
+			assert.Contains(t, []uint{1, 2, 3}, a.AuthorID)
+		}
+
+		assert.Panics(t, func() {
+			GenerateWithRandomParent([]*TestUser{}, NewFactory(articleGenerator), 1, func(_ *TestUser, _ *TestArticle) {})
+		})
+	})
+
 	t.Run("Save", func(t *testing.T) {
 		RegisterDialect("sqlite3_factory_test", "file:{name}?{options}", sqlite.Open)
 		t.Cleanup(func() {
@@ -0,0 +1,221 @@
+package database
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/callbacks"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+	"goyave.dev/goyave/v5/util/errors"
+)
+
+const (
+	optimisticLockCallbackCreateName = "goyave:optimistic_lock_create"
+	optimisticLockCallbackBeforeName = "goyave:optimistic_lock_before"
+	optimisticLockCallbackAfterName  = "goyave:optimistic_lock_after"
+
+	optimisticLockSettingKey = "goyave:optimistic_lock"
+)
+
+var versionType = reflect.TypeOf(Version(0))
+
+// Version the type of the column used by the `OptimisticLockPlugin` to detect concurrent
+// modifications of a record. A model can have at most one field of this type.
+//
+//	type Product struct {
+//		Name    string
+//		ID      int64 `gorm:"primaryKey"`
+//		Version database.Version
+//	}
+//
+// The version of a new record is `1`. A zero version means the version of the record is unknown
+// (for example if the record wasn't fetched from the database): updates are then applied without
+// concurrency check but the version is still incremented.
+type Version int64
+
+// ETag returns the strong entity-tag representation of this version, to be used
+// in the `ETag` HTTP header.
+func (v Version) ETag() string {
+	return `"` + strconv.FormatInt(int64(v), 10) + `"`
+}
+
+// ParseVersionETag parses the given entity-tag, as returned by `Version.ETag()`.
+// Returns false if the entity-tag is weak or isn't a valid version.
+func ParseVersionETag(etag string) (Version, bool) {
+	etag = strings.TrimSpace(etag)
+	if len(etag) < 3 || etag[0] != '"' || etag[len(etag)-1] != '"' {
+		return 0, false
+	}
+	v, err := strconv.ParseInt(etag[1:len(etag)-1], 10, 64)
+	if err != nil || v <= 0 {
+		return 0, false
+	}
+	return Version(v), true
+}
+
+// ConflictError returned by update operations on a model having a `Version` field when the
+// record has been modified (or deleted) since it was fetched. The operation didn't update anything.
+type ConflictError struct {
+	// Table the name of the table of the model.
+	Table string
+
+	// Version the version of the record expected by the update operation.
+	Version Version
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("optimistic lock conflict: record of table %q at version %d has been modified concurrently", e.Table, e.Version)
+}
+
+// OptimisticLockPlugin GORM plugin implementing optimistic locking for models having a
+// field of type `Version`.
+//
+// On update, the version column is incremented and, if the version of the updated record is known,
+// the statement is conditioned to the version stored in the database being the same. If no row
+// is affected, the record has been modified concurrently and a `*ConflictError` is added to the statement.
+// On success, the version field of the model is updated.
+//
+// On create, the version of the records is initialized to `1` if it is zero.
+//
+// Batch updates (on a slice of models, or without model instance) always increment the version
+// but cannot detect conflicts.
+type OptimisticLockPlugin struct{}
+
+// Name returns the name of the plugin
+func (p *OptimisticLockPlugin) Name() string {
+	return "goyave:optimistic_lock"
+}
+
+// Initialize registers the callbacks for the create and update operations.
+func (p *OptimisticLockPlugin) Initialize(db *gorm.DB) error {
+	if err := db.Callback().Create().Before("gorm:create").Register(optimisticLockCallbackCreateName, p.create); err != nil {
+		return errors.New(err)
+	}
+
+	updateCallback := db.Callback().Update()
+	if err := updateCallback.After("gorm:save_before_associations").Before("gorm:update").Register(optimisticLockCallbackBeforeName, p.before); err != nil {
+		return errors.New(err)
+	}
+	if err := updateCallback.After("gorm:update").Register(optimisticLockCallbackAfterName, p.after); err != nil {
+		return errors.New(err)
+	}
+	return nil
+}
+
+// optimisticLock the state of the lock of a single update statement.
+type optimisticLock struct {
+	field   *schema.Field
+	version Version
+}
+
+func (p *OptimisticLockPlugin) create(db *gorm.DB) {
+	if db.Error != nil || db.Statement.Schema == nil {
+		return
+	}
+	field := lookUpVersionField(db.Statement.Schema)
+	if field == nil {
+		return
+	}
+
+	initVersion := func(value reflect.Value) {
+		if _, isZero := field.ValueOf(db.Statement.Context, value); isZero {
+			db.AddError(field.Set(db.Statement.Context, value, Version(1)))
+		}
+	}
+
+	switch db.Statement.ReflectValue.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < db.Statement.ReflectValue.Len(); i++ {
+			value := reflect.Indirect(db.Statement.ReflectValue.Index(i))
+			if value.Kind() == reflect.Struct {
+				initVersion(value)
+			}
+		}
+	case reflect.Struct:
+		initVersion(db.Statement.ReflectValue)
+	}
+}
+
+func (p *OptimisticLockPlugin) before(db *gorm.DB) {
+	stmt := db.Statement
+	if db.Error != nil || stmt.Schema == nil || stmt.SQL.Len() != 0 {
+		return
+	}
+	field := lookUpVersionField(stmt.Schema)
+	if field == nil {
+		return
+	}
+
+	// The version must be read before the assignments are converted because
+	// the updated values are copied to the model.
+	lock := &optimisticLock{field: field}
+	if stmt.ReflectValue.Kind() == reflect.Struct {
+		if v, isZero := field.ValueOf(stmt.Context, stmt.ReflectValue); !isZero {
+			lock.version = reflect.ValueOf(v).Convert(versionType).Interface().(Version)
+		}
+	}
+
+	var set clause.Set
+	if c, ok := stmt.Clauses["SET"]; ok {
+		set, _ = c.Expression.(clause.Set)
+	} else {
+		set = callbacks.ConvertToAssignments(stmt)
+	}
+	if len(set) == 0 {
+		// Nothing to update, the update callback won't execute anything.
+		return
+	}
+
+	assignments := make(clause.Set, 0, len(set)+1)
+	for _, assignment := range set {
+		if assignment.Column.Name != field.DBName {
+			assignments = append(assignments, assignment)
+		}
+	}
+	assignments = append(assignments, clause.Assignment{
+		Column: clause.Column{Name: field.DBName},
+		Value:  clause.Expr{SQL: "? + 1", Vars: []any{clause.Column{Table: clause.CurrentTable, Name: field.DBName}}},
+	})
+	stmt.AddClause(assignments)
+
+	if lock.version != 0 {
+		stmt.AddClause(clause.Where{Exprs: []clause.Expression{
+			clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, Value: lock.version},
+		}})
+	}
+	stmt.Settings.Store(optimisticLockSettingKey, lock)
+}
+
+func (p *OptimisticLockPlugin) after(db *gorm.DB) {
+	value, ok := db.Statement.Settings.LoadAndDelete(optimisticLockSettingKey)
+	if !ok {
+		return
+	}
+	delete(db.Statement.Clauses, "SET")
+	lock := value.(*optimisticLock)
+
+	if db.Error != nil || db.DryRun || lock.version == 0 {
+		return
+	}
+
+	if db.RowsAffected == 0 {
+		db.AddError(&ConflictError{Table: db.Statement.Table, Version: lock.version})
+		return
+	}
+	db.AddError(lock.field.Set(db.Statement.Context, db.Statement.ReflectValue, lock.version+1))
+}
+
+// lookUpVersionField returns the first field of type `Version` of the given schema,
+// or `nil` if there is none.
+func lookUpVersionField(s *schema.Schema) *schema.Field {
+	for _, field := range s.Fields {
+		if field.FieldType == versionType && field.DBName != "" {
+			return field
+		}
+	}
+	return nil
+}
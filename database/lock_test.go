@@ -0,0 +1,189 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"goyave.dev/goyave/v5/config"
+)
+
+type TestVersionedProduct struct {
+	Name    string
+	ID      uint `gorm:"primaryKey"`
+	Version Version
+}
+
+func prepareOptimisticLockTest(t *testing.T) *gorm.DB {
+	cfg := config.LoadDefault()
+	cfg.Set("app.debug", false)
+	cfg.Set("database.connection", "sqlite3_lock_test")
+	cfg.Set("database.name", fmt.Sprintf("lock_test_%s.db", t.Name()))
+	cfg.Set("database.options", "mode=memory&cache=shared")
+	db, err := New(cfg, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		sqlDB, err := db.DB()
+		require.NoError(t, err)
+		require.NoError(t, sqlDB.Close())
+	})
+	require.NoError(t, db.AutoMigrate(&TestVersionedProduct{}, &TestUser{}))
+	return db
+}
+
+func TestOptimisticLockPlugin(t *testing.T) {
+	RegisterDialect("sqlite3_lock_test", "file:{name}?{options}", sqlite.Open)
+	t.Cleanup(func() {
+		mu.Lock()
+		delete(dialects, "sqlite3_lock_test")
+		mu.Unlock()
+	})
+
+	t.Run("Callbacks", func(t *testing.T) {
+		db := prepareOptimisticLockTest(t)
+
+		assert.Contains(t, db.Plugins, (&OptimisticLockPlugin{}).Name())
+		callbacks := db.Callback()
+		assert.NotNil(t, callbacks.Create().Get(optimisticLockCallbackCreateName))
+		assert.NotNil(t, callbacks.Update().Get(optimisticLockCallbackBeforeName))
+		assert.NotNil(t, callbacks.Update().Get(optimisticLockCallbackAfterName))
+	})
+
+	t.Run("Create", func(t *testing.T) {
+		db := prepareOptimisticLockTest(t)
+
+		product := &TestVersionedProduct{Name: "product"}
+		require.NoError(t, db.Create(product).Error)
+		assert.Equal(t, Version(1), product.Version)
+
+		products := []*TestVersionedProduct{{Name: "a"}, {Name: "b", Version: 4}}
+		require.NoError(t, db.Create(products).Error)
+		assert.Equal(t, Version(1), products[0].Version)
+		assert.Equal(t, Version(4), products[1].Version)
+
+		// Models without version are not affected
+		require.NoError(t, db.Create(&TestUser{Name: "user"}).Error)
+	})
+
+	t.Run("Save", func(t *testing.T) {
+		db := prepareOptimisticLockTest(t)
+
+		product := &TestVersionedProduct{Name: "product"}
+		require.NoError(t, db.Create(product).Error)
+
+		product.Name = "updated"
+		require.NoError(t, db.Save(product).Error)
+		assert.Equal(t, Version(2), product.Version)
+
+		stored := &TestVersionedProduct{}
+		require.NoError(t, db.First(stored, product.ID).Error)
+		assert.Equal(t, "updated", stored.Name)
+		assert.Equal(t, Version(2), stored.Version)
+	})
+
+	t.Run("Updates", func(t *testing.T) {
+		db := prepareOptimisticLockTest(t)
+
+		product := &TestVersionedProduct{Name: "product"}
+		require.NoError(t, db.Create(product).Error)
+
+		require.NoError(t, db.Model(product).Updates(map[string]any{"name": "map"}).Error)
+		assert.Equal(t, Version(2), product.Version)
+
+		require.NoError(t, db.Model(product).Updates(&TestVersionedProduct{Name: "struct"}).Error)
+		assert.Equal(t, Version(3), product.Version)
+
+		require.NoError(t, db.Model(product).Update("name", "column").Error)
+		assert.Equal(t, Version(4), product.Version)
+
+		stored := &TestVersionedProduct{}
+		require.NoError(t, db.First(stored, product.ID).Error)
+		assert.Equal(t, "column", stored.Name)
+		assert.Equal(t, Version(4), stored.Version)
+	})
+
+	t.Run("Conflict", func(t *testing.T) {
+		db := prepareOptimisticLockTest(t)
+
+		product := &TestVersionedProduct{Name: "product"}
+		require.NoError(t, db.Create(product).Error)
+
+		concurrent := &TestVersionedProduct{}
+		require.NoError(t, db.First(concurrent, product.ID).Error)
+		concurrent.Name = "concurrent"
+		require.NoError(t, db.Save(concurrent).Error)
+
+		product.Name = "stale"
+		err := db.Save(product).Error
+		var conflictErr *ConflictError
+		require.ErrorAs(t, err, &conflictErr)
+		assert.Equal(t, "test_versioned_products", conflictErr.Table)
+		assert.Equal(t, Version(1), conflictErr.Version)
+		assert.Equal(t, Version(1), product.Version)
+
+		err = db.Model(product).Update("name", "stale").Error
+		require.ErrorAs(t, err, &conflictErr)
+
+		stored := &TestVersionedProduct{}
+		require.NoError(t, db.First(stored, product.ID).Error)
+		assert.Equal(t, "concurrent", stored.Name)
+		assert.Equal(t, Version(2), stored.Version)
+
+		var count int64
+		require.NoError(t, db.Model(&TestVersionedProduct{}).Count(&count).Error)
+		assert.Equal(t, int64(1), count) // Save didn't fall back to insert
+	})
+
+	t.Run("unknown_version", func(t *testing.T) {
+		db := prepareOptimisticLockTest(t)
+
+		products := []*TestVersionedProduct{{Name: "a"}, {Name: "b"}}
+		require.NoError(t, db.Create(products).Error)
+
+		// No condition on the version but it is still incremented
+		require.NoError(t, db.Model(&TestVersionedProduct{}).Where("1 = 1").Update("name", "batch").Error)
+
+		stored := []*TestVersionedProduct{}
+		require.NoError(t, db.Order("id").Find(&stored).Error)
+		require.Len(t, stored, 2)
+		for _, p := range stored {
+			assert.Equal(t, "batch", p.Name)
+			assert.Equal(t, Version(2), p.Version)
+		}
+	})
+
+	t.Run("DryRun", func(t *testing.T) {
+		db := prepareOptimisticLockTest(t)
+
+		product := &TestVersionedProduct{Name: "product"}
+		require.NoError(t, db.Create(product).Error)
+
+		product.Name = "dry"
+		stmt := db.Session(&gorm.Session{DryRun: true}).Save(product).Statement
+		require.NoError(t, stmt.Error)
+		assert.Equal(t, "UPDATE `test_versioned_products` SET `name`=?,`version`=`test_versioned_products`.`version` + 1 WHERE `id` = ? AND `test_versioned_products`.`version` = ?", stmt.SQL.String())
+		assert.Equal(t, Version(1), product.Version)
+	})
+}
+
+func TestVersionETag(t *testing.T) {
+	assert.Equal(t, `"12"`, Version(12).ETag())
+
+	v, ok := ParseVersionETag(Version(12).ETag())
+	assert.True(t, ok)
+	assert.Equal(t, Version(12), v)
+
+	for _, etag := range []string{"", `"`, `W/"1"`, `"-1"`, `"a"`, `1`} {
+		v, ok := ParseVersionETag(etag)
+		assert.False(t, ok, etag)
+		assert.Equal(t, Version(0), v)
+	}
+
+	err := &ConflictError{Table: "products", Version: 3}
+	assert.Equal(t, `optimistic lock conflict: record of table "products" at version 3 has been modified concurrently`, err.Error())
+	assert.True(t, errors.As(fmt.Errorf("wrap: %w", err), new(*ConflictError)))
+}
@@ -20,22 +20,60 @@ import (
 
 var regexGormPath = regexp.MustCompile(`gorm.io/(.*?)@`)
 
+// QueryEvent information about an executed query, given to `Logger.MetricsHook`.
+type QueryEvent struct {
+	// Err the error returned by the query, if any.
+	Err error
+
+	// SQL the executed statement.
+	SQL string
+
+	// Route the name of the route that issued the query. Empty if the
+	// query context doesn't contain `QueryStats` or if the route is not named.
+	Route string
+
+	// Duration the execution time of the query.
+	Duration time.Duration
+
+	// Rows the number of rows affected by the query, -1 if unknown.
+	Rows int64
+}
+
 // Logger adapter between `*slog.Logger` and GORM's logger.
+//
+// If the context of a query contains `QueryStats` (see `WithQueryStats()`), the
+// query is recorded in it, even if the logger is silent.
 type Logger struct {
 	slogger func() *slog.Logger
 
+	// MetricsHook if not nil, is called after every query with its execution information,
+	// even if the logger is silent. This can be used to expose query durations to a
+	// metrics sink. This function must be safe for concurrent use.
+	//
+	//	db.Logger.(*database.Logger).MetricsHook = func(ctx context.Context, e *database.QueryEvent) {
+	//		queryDuration.Observe(e.Duration.Seconds())
+	//	}
+	MetricsHook func(ctx context.Context, event *QueryEvent)
+
 	// SlowThreshold defines the minimum query execution time to be considered "slow".
 	// If a query takes more time than `SlowThreshold`, the query will be logged at the WARN level.
 	// If 0, disables query execution time checking.
 	SlowThreshold time.Duration
+
+	// RepeatedQueryThreshold defines the number of times an identical statement (only differing
+	// by its parameters) can be executed with the same `QueryStats` before being logged at the WARN
+	// level as a potential N+1 pattern. Each statement is only reported once per `QueryStats`.
+	// If 0, disables repeated statements detection.
+	RepeatedQueryThreshold int
 }
 
 // NewLogger create a new `Logger` adapter between GORM and `*slog.Logger`.
-// Use a `SlowThreshold` of 200ms.
+// Use a `SlowThreshold` of 200ms and a `RepeatedQueryThreshold` of 5.
 func NewLogger(slogger func() *slog.Logger) *Logger {
 	return &Logger{
-		slogger:       slogger,
-		SlowThreshold: 200 * time.Millisecond,
+		slogger:                slogger,
+		SlowThreshold:          200 * time.Millisecond,
+		RepeatedQueryThreshold: 5,
 	}
 }
 
@@ -74,13 +112,30 @@ func (l Logger) Error(ctx context.Context, msg string, data ...any) {
 //   - `LevelDebug`
 //   - `LevelWarn` if the query is slow
 //   - `LevelError` if the given error is not nil
+//
+// If the context contains `QueryStats`, the query is recorded and repeated statements are
+// logged at `LevelWarn` once they reach `RepeatedQueryThreshold`, along with the name of the route.
 func (l Logger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
-	if l.slogger == nil {
+	stats := GetQueryStats(ctx)
+	if l.slogger == nil && stats == nil && l.MetricsHook == nil {
 		return
 	}
 
 	elapsed := time.Since(begin)
 
+	if stats != nil || l.MetricsHook != nil {
+		var repeated bool
+		fc, repeated = l.account(ctx, stats, elapsed, fc, err)
+		if repeated && l.slogger != nil && l.slogger().Enabled(ctx, stdslog.LevelWarn) {
+			sql, _ := fc()
+			l.slogger().WarnWithSource(ctx, getSourceCaller(), fmt.Sprintf("REPEATED SQL (possible N+1) x%d in route %q\n"+slog.Reset+slog.Red+"[%.3fms] "+slog.Reset+"%s", l.RepeatedQueryThreshold, stats.Route, float64(elapsed.Nanoseconds())/1e6, sql))
+		}
+	}
+
+	if l.slogger == nil {
+		return
+	}
+
 	switch {
 	case err != nil && l.slogger().Enabled(ctx, stdslog.LevelError) && !errors.Is(err, gorm.ErrRecordNotFound):
 		sql, rows := fc()
@@ -95,6 +150,31 @@ func (l Logger) Trace(ctx context.Context, begin time.Time, fc func() (sql strin
 	}
 }
 
+// account records the query in the given stats (if not nil) and calls the metrics hook.
+// Returns a function returning the memoized result of `fc` and true if the statement
+// just reached the `RepeatedQueryThreshold`.
+func (l Logger) account(ctx context.Context, stats *QueryStats, elapsed time.Duration, fc func() (string, int64), err error) (func() (string, int64), bool) {
+	sql, rows := fc()
+	repeated := false
+	route := ""
+	if stats != nil {
+		repeats := stats.record(sql, elapsed)
+		repeated = l.RepeatedQueryThreshold > 0 && repeats == l.RepeatedQueryThreshold
+		route = stats.Route
+	}
+
+	if l.MetricsHook != nil {
+		l.MetricsHook(ctx, &QueryEvent{
+			Err:      err,
+			SQL:      sql,
+			Route:    route,
+			Duration: elapsed,
+			Rows:     rows,
+		})
+	}
+	return func() (string, int64) { return sql, rows }, repeated
+}
+
 func getSourceCaller() uintptr {
 	// function copied from gorm/utils/utils.go
 	// the second caller usually from gorm internal, so set i start from 2
@@ -23,6 +23,7 @@ func TestLogger(t *testing.T) {
 
 		assert.Equal(t, slogger, l.slogger())
 		assert.Equal(t, 200*time.Millisecond, l.SlowThreshold)
+		assert.Equal(t, 5, l.RepeatedQueryThreshold)
 	})
 
 	t.Run("LogMode", func(t *testing.T) {
@@ -210,5 +211,77 @@ func TestLogger(t *testing.T) {
 				}
 			})
 		}
+
+		t.Run("stats", func(t *testing.T) {
+			buf := bytes.NewBuffer(make([]byte, 0, 1024))
+			slogger := slog.New(stdslog.NewJSONHandler(buf, &stdslog.HandlerOptions{Level: stdslog.LevelWarn}))
+			l := NewLogger(func() *slog.Logger { return slogger })
+			l.RepeatedQueryThreshold = 2
+
+			stats := NewQueryStats("route-name")
+			ctx := WithQueryStats(context.Background(), stats)
+			for i := 0; i < 3; i++ {
+				l.Trace(ctx, time.Now(), func() (sql string, rowsAffected int64) {
+					return fmt.Sprintf("SELECT * FROM some_table WHERE id = %d", i), 1
+				}, nil)
+			}
+
+			assert.Equal(t, 3, stats.Count())
+			assert.Equal(t, 1, strings.Count(buf.String(), `REPEATED SQL (possible N+1) x2 in route \"route-name\"`), buf.String())
+			assert.Contains(t, buf.String(), "SELECT * FROM some_table WHERE id = 1")
+		})
+
+		t.Run("stats_nil_slogger", func(t *testing.T) {
+			l := NewLogger(nil)
+			stats := NewQueryStats("")
+			ctx := WithQueryStats(context.Background(), stats)
+			l.Trace(ctx, time.Now(), func() (sql string, rowsAffected int64) {
+				return "SELECT * FROM some_table", 4
+			}, nil)
+			assert.Equal(t, 1, stats.Count())
+		})
+
+		t.Run("stats_threshold_disabled", func(t *testing.T) {
+			buf := bytes.NewBuffer(make([]byte, 0, 1024))
+			slogger := slog.New(stdslog.NewJSONHandler(buf, &stdslog.HandlerOptions{Level: stdslog.LevelWarn}))
+			l := NewLogger(func() *slog.Logger { return slogger })
+			l.RepeatedQueryThreshold = 0
+			ctx := WithQueryStats(context.Background(), NewQueryStats(""))
+			for i := 0; i < 10; i++ {
+				l.Trace(ctx, time.Now(), func() (sql string, rowsAffected int64) {
+					return "SELECT * FROM some_table", 4
+				}, nil)
+			}
+			assert.Empty(t, buf.String())
+		})
+
+		t.Run("MetricsHook", func(t *testing.T) {
+			l := NewLogger(nil)
+			events := []*QueryEvent{}
+			l.MetricsHook = func(_ context.Context, event *QueryEvent) {
+				events = append(events, event)
+			}
+			err := fmt.Errorf("test error")
+			begin := time.Now().Add(-time.Second)
+
+			l.Trace(context.Background(), begin, func() (sql string, rowsAffected int64) {
+				return "SELECT * FROM some_table", 4
+			}, err)
+			l.Trace(WithQueryStats(context.Background(), NewQueryStats("route-name")), begin, func() (sql string, rowsAffected int64) {
+				return "SELECT * FROM some_table", -1
+			}, nil)
+
+			if assert.Len(t, events, 2) {
+				assert.Equal(t, "SELECT * FROM some_table", events[0].SQL)
+				assert.Equal(t, int64(4), events[0].Rows)
+				assert.Equal(t, err, events[0].Err)
+				assert.Empty(t, events[0].Route)
+				assert.GreaterOrEqual(t, events[0].Duration, time.Second)
+
+				assert.Equal(t, int64(-1), events[1].Rows)
+				assert.NoError(t, events[1].Err)
+				assert.Equal(t, "route-name", events[1].Route)
+			}
+		})
 	})
 }
@@ -0,0 +1,256 @@
+package database
+
+import (
+	"context"
+	"flag"
+	"io"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"goyave.dev/goyave/v5/config"
+	"goyave.dev/goyave/v5/slog"
+	"goyave.dev/goyave/v5/util/errors"
+)
+
+// Seeder a named unit of database seeding. A seeder can depend on other seeders,
+// which are guaranteed to be executed before it.
+type Seeder struct {
+	// Run the seeder. Returning an error aborts the seeding process and rolls
+	// back the changes made by all seeders.
+	Run func(s *Seeding) error
+
+	// Name unique name identifying the seeder.
+	Name string
+
+	// Dependencies the names of the seeders that must be executed before this one.
+	Dependencies []string
+}
+
+// Seeding the state of a running seeding process, shared by all the seeders
+// executed in this process.
+type Seeding struct {
+	// DB the database the seeders should insert records into. If the seeders
+	// are run in a transaction, this is the transaction.
+	DB *gorm.DB
+
+	// Rand the random source of this seeding process. Factories used by seeders
+	// should use it (see `Factory.WithRand()`) so the whole process is reproducible
+	// with the same seed.
+	Rand *rand.Rand
+
+	records map[string]any
+
+	// Seed the value used to initialize `Rand`.
+	Seed uint64
+}
+
+// Context returns the context of the seeding process.
+func (s *Seeding) Context() context.Context {
+	return s.DB.Statement.Context
+}
+
+// Store the given records under the given key so they can be reused by other
+// seeders, for example as parents of other records.
+func Store[T any](s *Seeding, key string, records []*T) {
+	s.records[key] = records
+}
+
+// Records returns the records stored with `Store()` under the given key.
+// Returns `nil` if nothing was stored under this key.
+//
+// Panics if the stored records are not of the requested type.
+func Records[T any](s *Seeding, key string) []*T {
+	records, ok := s.records[key]
+	if !ok {
+		return nil
+	}
+	r, ok := records.([]*T)
+	if !ok {
+		panic(errors.Errorf("seeding records %q are of type %T, not %T", key, records, r))
+	}
+	return r
+}
+
+// Seeders a registry of seeders. Seeders are executed in registration order,
+// unless their dependencies require otherwise.
+type Seeders struct {
+	seeders map[string]*Seeder
+	order   []string
+
+	// DisableTransaction if true, the seeders are not run inside a transaction.
+	DisableTransaction bool
+}
+
+// NewSeeders create a new seeders registry containing the given seeders.
+func NewSeeders(seeders ...*Seeder) *Seeders {
+	s := &Seeders{
+		seeders: make(map[string]*Seeder, len(seeders)),
+		order:   make([]string, 0, len(seeders)),
+	}
+	for _, seeder := range seeders {
+		s.Register(seeder)
+	}
+	return s
+}
+
+// Register a new seeder.
+//
+// Panics if a seeder with the same name is already registered.
+func (s *Seeders) Register(seeder *Seeder) {
+	if _, ok := s.seeders[seeder.Name]; ok {
+		panic(errors.Errorf("seeder %q is already registered", seeder.Name))
+	}
+	s.seeders[seeder.Name] = seeder
+	s.order = append(s.order, seeder.Name)
+}
+
+// Names returns the names of all the registered seeders, in registration order.
+func (s *Seeders) Names() []string {
+	return append([]string{}, s.order...)
+}
+
+// Resolve returns the seeders identified by the given names and all their dependencies,
+// sorted in execution order. If no name is given, all registered seeders are returned.
+//
+// Returns an error if a seeder doesn't exist or if there is a dependency cycle.
+func (s *Seeders) Resolve(names ...string) ([]*Seeder, error) {
+	if len(names) == 0 {
+		names = s.order
+	}
+
+	const (
+		visiting = iota + 1
+		visited
+	)
+	state := make(map[string]int, len(s.seeders))
+	result := make([]*Seeder, 0, len(s.seeders))
+
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		seeder, ok := s.seeders[name]
+		if !ok {
+			if len(path) > 0 {
+				return errors.Errorf("seeder %q (required by %q) doesn't exist", name, path[len(path)-1])
+			}
+			return errors.Errorf("seeder %q doesn't exist", name)
+		}
+		switch state[name] {
+		case visited:
+			return nil
+		case visiting:
+			return errors.Errorf("seeder dependency cycle detected: %s", strings.Join(append(path, name), " -> "))
+		}
+		state[name] = visiting
+		for _, dep := range seeder.Dependencies {
+			if err := visit(dep, append(path, name)); err != nil {
+				return err
+			}
+		}
+		state[name] = visited
+		result = append(result, seeder)
+		return nil
+	}
+
+	for _, name := range names {
+		if err := visit(name, nil); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// Run the seeders identified by the given names, and their dependencies, using the given seed
+// for the random source. If no name is given, all the registered seeders are executed.
+//
+// Unless `DisableTransaction` is `true`, all the seeders are run in a single transaction:
+// if one of them fails, no change is persisted.
+func (s *Seeders) Run(db *gorm.DB, seed uint64, names ...string) error {
+	seeders, err := s.Resolve(names...)
+	if err != nil {
+		return err
+	}
+
+	run := func(tx *gorm.DB) error {
+		seeding := &Seeding{
+			DB:      tx,
+			Rand:    rand.New(rand.NewPCG(seed, seed)),
+			Seed:    seed,
+			records: map[string]any{},
+		}
+		for _, seeder := range seeders {
+			if err := seeder.Run(seeding); err != nil {
+				return errors.Errorf("seeder %q failed: %w", seeder.Name, err)
+			}
+		}
+		return nil
+	}
+
+	if s.DisableTransaction {
+		return run(db)
+	}
+	return errors.New(db.Transaction(run))
+}
+
+// Command a command-style entry point to run seeders against the database defined in
+// the given configuration. This can be used to build a small seeding CLI:
+//
+//	func main() {
+//		cfg, err := config.Load()
+//		// ...
+//		seeders := database.NewSeeders(userSeeder, postSeeder)
+//		if err := seeders.Command(cfg, nil, os.Args[1:], os.Stdout); err != nil {
+//			fmt.Fprintln(os.Stderr, err.(*errors.Error).String())
+//			os.Exit(1)
+//		}
+//	}
+//
+// Supported arguments:
+//   - "-seed <uint>": the seed of the random source. If omitted, the current time is used.
+//     The seed used is always printed so a run can be reproduced.
+//   - "-list": print the names of the registered seeders, in execution order, and exit.
+//   - Any other positional argument is the name of a seeder to run. If none is given,
+//     all the seeders are run.
+//
+// In order to use a specific driver / dialect, you must not forget to blank-import it.
+// See `database.New()` for more details.
+func (s *Seeders) Command(cfg *config.Config, logger func() *slog.Logger, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("seed", flag.ContinueOnError)
+	flags.SetOutput(out)
+	seed := flags.Uint64("seed", uint64(time.Now().UnixNano()), "seed of the random source")
+	list := flags.Bool("list", false, "list the registered seeders in execution order")
+	if err := flags.Parse(args); err != nil {
+		return errors.New(err)
+	}
+
+	seeders, err := s.Resolve(flags.Args()...)
+	if err != nil {
+		return err
+	}
+
+	if *list {
+		for _, seeder := range seeders {
+			if _, err := io.WriteString(out, seeder.Name+"\n"); err != nil {
+				return errors.New(err)
+			}
+		}
+		return nil
+	}
+
+	db, err := New(cfg, logger)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if sqlDB, err := db.DB(); err == nil {
+			_ = sqlDB.Close()
+		}
+	}()
+
+	if _, err := io.WriteString(out, "Seeding with seed "+strconv.FormatUint(*seed, 10)+"\n"); err != nil {
+		return errors.New(err)
+	}
+	return s.Run(db, *seed, flags.Args()...)
+}
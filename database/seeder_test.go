@@ -0,0 +1,193 @@
+package database
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"goyave.dev/goyave/v5/config"
+)
+
+func prepareSeederTestConfig(name string) *config.Config {
+	cfg := config.LoadDefault()
+	cfg.Set("app.debug", false)
+	cfg.Set("database.connection", "sqlite3_seeder_test")
+	cfg.Set("database.name", name)
+	cfg.Set("database.options", "mode=memory&cache=shared")
+	return cfg
+}
+
+func openSeederTestDB(t *testing.T, name string) *gorm.DB {
+	db, err := New(prepareSeederTestConfig(name), nil)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		sqlDB, err := db.DB()
+		require.NoError(t, err)
+		require.NoError(t, sqlDB.Close())
+	})
+	require.NoError(t, db.AutoMigrate(&TestUser{}, &TestArticle{}))
+	return db
+}
+
+func makeTestSeeders(order *[]string) *Seeders {
+	users := &Seeder{
+		Name: "users",
+		Run: func(s *Seeding) error {
+			*order = append(*order, "users")
+			factory := NewRandFactory(func(r *rand.Rand) *TestUser {
+				return &TestUser{Name: fmt.Sprintf("user-%d", r.IntN(1000000))}
+			}).WithRand(s.Rand)
+			Store(s, "users", factory.Save(s.DB, 2))
+			return nil
+		},
+	}
+	articles := &Seeder{
+		Name:         "articles",
+		Dependencies: []string{"users"},
+		Run: func(s *Seeding) error {
+			*order = append(*order, "articles")
+			SaveFor(s.DB, Records[TestUser](s, "users"), NewFactory(articleGenerator), 3, func(u *TestUser, a *TestArticle) {
+				a.AuthorID = u.ID
+			})
+			return nil
+		},
+	}
+	// Registered before its dependency
+	return NewSeeders(articles, users)
+}
+
+func TestSeeders(t *testing.T) {
+	RegisterDialect("sqlite3_seeder_test", "file:{name}?{options}", sqlite.Open)
+	t.Cleanup(func() {
+		mu.Lock()
+		delete(dialects, "sqlite3_seeder_test")
+		mu.Unlock()
+	})
+
+	t.Run("Register", func(t *testing.T) {
+		seeders := NewSeeders(&Seeder{Name: "a"}, &Seeder{Name: "b"})
+		assert.Equal(t, []string{"a", "b"}, seeders.Names())
+		assert.Panics(t, func() {
+			seeders.Register(&Seeder{Name: "a"})
+		})
+	})
+
+	t.Run("Resolve", func(t *testing.T) {
+		seeders := NewSeeders(
+			&Seeder{Name: "c", Dependencies: []string{"b", "a"}},
+			&Seeder{Name: "a"},
+			&Seeder{Name: "b", Dependencies: []string{"a"}},
+			&Seeder{Name: "d"},
+		)
+
+		names := func(s []*Seeder) []string {
+			res := make([]string, 0, len(s))
+			for _, seeder := range s {
+				res = append(res, seeder.Name)
+			}
+			return res
+		}
+
+		resolved, err := seeders.Resolve()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a", "b", "c", "d"}, names(resolved))
+
+		resolved, err = seeders.Resolve("b")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a", "b"}, names(resolved))
+
+		_, err = seeders.Resolve("unknown")
+		require.ErrorContains(t, err, `seeder "unknown" doesn't exist`)
+
+		seeders.Register(&Seeder{Name: "e", Dependencies: []string{"unknown"}})
+		_, err = seeders.Resolve("e")
+		require.ErrorContains(t, err, `seeder "unknown" (required by "e") doesn't exist`)
+
+		seeders = NewSeeders(
+			&Seeder{Name: "a", Dependencies: []string{"c"}},
+			&Seeder{Name: "b", Dependencies: []string{"a"}},
+			&Seeder{Name: "c", Dependencies: []string{"b"}},
+		)
+		_, err = seeders.Resolve()
+		require.ErrorContains(t, err, "seeder dependency cycle detected: a -> c -> b -> a")
+	})
+
+	t.Run("Run", func(t *testing.T) {
+		db := openSeederTestDB(t, "seeder_run_test.db")
+
+		order := []string{}
+		require.NoError(t, makeTestSeeders(&order).Run(db, 42))
+		assert.Equal(t, []string{"users", "articles"}, order)
+
+		users := []*TestUser{}
+		require.NoError(t, db.Order("id").Find(&users).Error)
+		require.Len(t, users, 2)
+		var count int64
+		require.NoError(t, db.Model(&TestArticle{}).Where("author_id = ?", users[1].ID).Count(&count).Error)
+		assert.Equal(t, int64(3), count)
+
+		// Same seed, same data
+		require.NoError(t, makeTestSeeders(&order).Run(db, 42))
+		sameSeedUsers := []*TestUser{}
+		require.NoError(t, db.Order("id").Offset(2).Find(&sameSeedUsers).Error)
+		require.Len(t, sameSeedUsers, 2)
+		assert.Equal(t, users[0].Name, sameSeedUsers[0].Name)
+		assert.Equal(t, users[1].Name, sameSeedUsers[1].Name)
+	})
+
+	t.Run("Run_rollback", func(t *testing.T) {
+		db := openSeederTestDB(t, "seeder_rollback_test.db")
+
+		seeders := NewSeeders(
+			&Seeder{Name: "users", Run: func(s *Seeding) error {
+				NewFactory(userGenerator).Save(s.DB, 2)
+				return nil
+			}},
+			&Seeder{Name: "fail", Dependencies: []string{"users"}, Run: func(_ *Seeding) error {
+				return fmt.Errorf("test error")
+			}},
+		)
+		err := seeders.Run(db, 1)
+		require.ErrorContains(t, err, `seeder "fail" failed: test error`)
+
+		var count int64
+		require.NoError(t, db.Model(&TestUser{}).Count(&count).Error)
+		assert.Equal(t, int64(0), count)
+	})
+
+	t.Run("Records_wrong_type", func(t *testing.T) {
+		s := &Seeding{records: map[string]any{}}
+		Store(s, "users", []*TestUser{})
+		assert.Nil(t, Records[TestArticle](s, "unknown"))
+		assert.Panics(t, func() {
+			Records[TestArticle](s, "users")
+		})
+	})
+
+	t.Run("Command", func(t *testing.T) {
+		order := []string{}
+		buf := &bytes.Buffer{}
+		require.NoError(t, makeTestSeeders(&order).Command(prepareSeederTestConfig("seeder_command_test.db"), nil, []string{"-list"}, buf))
+		assert.Equal(t, "users\narticles\n", buf.String())
+		assert.Empty(t, order)
+
+		openSeederTestDB(t, "seeder_command_test.db")
+
+		buf.Reset()
+		require.NoError(t, makeTestSeeders(&order).Command(prepareSeederTestConfig("seeder_command_test.db"), nil, []string{"-seed", "123", "users"}, buf))
+		assert.Equal(t, "Seeding with seed 123\n", buf.String())
+		assert.Equal(t, []string{"users"}, order)
+
+		buf.Reset()
+		err := makeTestSeeders(&order).Command(prepareSeederTestConfig("seeder_command_test.db"), nil, []string{"-unknownflag"}, buf)
+		require.Error(t, err)
+
+		err = makeTestSeeders(&order).Command(prepareSeederTestConfig("seeder_command_test.db"), nil, []string{"unknown"}, buf)
+		require.ErrorContains(t, err, `seeder "unknown" doesn't exist`)
+	})
+}
@@ -0,0 +1,106 @@
+package database
+
+import (
+	"context"
+	"regexp"
+	"sync"
+	"time"
+)
+
+var (
+	regexSQLString = regexp.MustCompile(`'(?:[^']|'')*'`)
+	regexSQLNumber = regexp.MustCompile(`\b\d+(?:\.\d+)?\b`)
+	regexSQLList   = regexp.MustCompile(`\(\s*\?(?:\s*,\s*\?)*\s*\)`)
+)
+
+// statsKey the key used to store the query stats in the context.
+type statsKey struct{}
+
+// QueryStats accounting of the queries executed with a context, typically the context
+// of a single HTTP request. Every query executed with a context containing a `*QueryStats`
+// (see `WithQueryStats()`) is recorded by the `Logger`.
+//
+// Statements are grouped after their literal values are replaced with placeholders so that
+// repeated statements only differing by their parameters (N+1 patterns) can be detected.
+//
+// This structure is safe for concurrent use.
+type QueryStats struct {
+	statements map[string]int
+
+	// Route the name of the route that issued the queries, used in the logs
+	// when repeated statements are detected. May be empty.
+	Route string
+
+	duration time.Duration
+	count    int
+	mu       sync.Mutex
+}
+
+// NewQueryStats create a new empty `QueryStats` for the route identified by the given name.
+func NewQueryStats(route string) *QueryStats {
+	return &QueryStats{
+		statements: make(map[string]int, 8),
+		Route:      route,
+	}
+}
+
+// WithQueryStats returns a copy of the given context containing the given `QueryStats`.
+// Queries executed with the returned context will be recorded in `stats`.
+func WithQueryStats(ctx context.Context, stats *QueryStats) context.Context {
+	return context.WithValue(ctx, statsKey{}, stats)
+}
+
+// GetQueryStats returns the `QueryStats` stored in the given context, or `nil`
+// if there is none.
+func GetQueryStats(ctx context.Context) *QueryStats {
+	stats, _ := ctx.Value(statsKey{}).(*QueryStats)
+	return stats
+}
+
+// Count returns the number of queries recorded.
+func (s *QueryStats) Count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.count
+}
+
+// Duration returns the cumulative execution time of the recorded queries.
+func (s *QueryStats) Duration() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.duration
+}
+
+// Repeated returns the normalized statements that were executed more than once,
+// associated with the number of times they were executed.
+func (s *QueryStats) Repeated() map[string]int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	repeated := make(map[string]int)
+	for statement, count := range s.statements {
+		if count > 1 {
+			repeated[statement] = count
+		}
+	}
+	return repeated
+}
+
+// record a query and returns the number of times its normalized statement
+// has been executed, including this time.
+func (s *QueryStats) record(sql string, elapsed time.Duration) int {
+	statement := normalizeSQL(sql)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.count++
+	s.duration += elapsed
+	s.statements[statement]++
+	return s.statements[statement]
+}
+
+// normalizeSQL replaces the literal values of the given SQL statement with
+// placeholders so statements only differing by their parameters are identical.
+func normalizeSQL(sql string) string {
+	sql = regexSQLString.ReplaceAllString(sql, "?")
+	sql = regexSQLNumber.ReplaceAllString(sql, "?")
+	return regexSQLList.ReplaceAllString(sql, "(?)")
+}
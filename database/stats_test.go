@@ -0,0 +1,53 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryStats(t *testing.T) {
+	t.Run("Context", func(t *testing.T) {
+		assert.Nil(t, GetQueryStats(context.Background()))
+
+		stats := NewQueryStats("route-name")
+		ctx := WithQueryStats(context.Background(), stats)
+		assert.Same(t, stats, GetQueryStats(ctx))
+		assert.Equal(t, "route-name", stats.Route)
+	})
+
+	t.Run("record", func(t *testing.T) {
+		stats := NewQueryStats("")
+		assert.Equal(t, 1, stats.record("SELECT * FROM users WHERE id = 1", time.Millisecond))
+		assert.Equal(t, 2, stats.record("SELECT * FROM users WHERE id = 2", 2*time.Millisecond))
+		assert.Equal(t, 1, stats.record("SELECT * FROM users WHERE email = 'a''b@example.org'", time.Millisecond))
+		assert.Equal(t, 2, stats.record("SELECT * FROM users WHERE email = 'c@example.org'", time.Millisecond))
+		assert.Equal(t, 1, stats.record("SELECT * FROM table1", time.Millisecond))
+
+		assert.Equal(t, 5, stats.Count())
+		assert.Equal(t, 6*time.Millisecond, stats.Duration())
+		assert.Equal(t, map[string]int{
+			"SELECT * FROM users WHERE id = ?":    2,
+			"SELECT * FROM users WHERE email = ?": 2,
+		}, stats.Repeated())
+	})
+
+	t.Run("normalizeSQL", func(t *testing.T) {
+		cases := []struct {
+			sql  string
+			want string
+		}{
+			{sql: "SELECT * FROM users", want: "SELECT * FROM users"},
+			{sql: "SELECT * FROM users WHERE id = 12 AND score > 1.5", want: "SELECT * FROM users WHERE id = ? AND score > ?"},
+			{sql: "SELECT * FROM users WHERE name = 'it''s 12'", want: "SELECT * FROM users WHERE name = ?"},
+			{sql: "SELECT * FROM users WHERE id IN (1,2, 3)", want: "SELECT * FROM users WHERE id IN (?)"},
+			{sql: "SELECT * FROM users WHERE id IN (1)", want: "SELECT * FROM users WHERE id IN (?)"},
+			{sql: "SELECT * FROM table2 WHERE t2.id = 4", want: "SELECT * FROM table2 WHERE t2.id = ?"},
+		}
+		for _, c := range cases {
+			assert.Equal(t, c.want, normalizeSQL(c.sql))
+		}
+	})
+}
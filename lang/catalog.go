@@ -0,0 +1,261 @@
+package lang
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/samber/lo"
+	"goyave.dev/goyave/v5/util/errors"
+	"goyave.dev/goyave/v5/util/fsutil"
+)
+
+const (
+	rulesPrefix  = "validation.rules."
+	fieldsPrefix = "validation.fields."
+)
+
+// CatalogDiff the differences between the lines of a language and a reference
+// set of lines. Keys use the same format as `Language.Get()` ("validation.rules.required").
+type CatalogDiff struct {
+	// Language the name of the compared language.
+	Language string
+
+	// Missing the keys defined in the reference but not in the language.
+	Missing []string
+
+	// Unused the keys defined in the language but not in the reference.
+	Unused []string
+}
+
+// IsEmpty returns true if there are no missing and no unused keys.
+func (d *CatalogDiff) IsEmpty() bool {
+	return len(d.Missing) == 0 && len(d.Unused) == 0
+}
+
+// Keys returns the sorted keys of all the lines defined in this language, using the same
+// format as `Get()`: "validation.rules.<rule_name>", "validation.fields.<field_name>", and
+// the name of the normal lines.
+func (l *Language) Keys() []string {
+	keys := make([]string, 0, len(l.lines)+len(l.validation.rules)+len(l.validation.fields))
+	keys = append(keys, lo.Keys(l.lines)...)
+	for k := range l.validation.rules {
+		keys = append(keys, rulesPrefix+k)
+	}
+	for k := range l.validation.fields {
+		keys = append(keys, fieldsPrefix+k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// defines returns true if the given key is defined in this language.
+func (l *Language) defines(key string) bool {
+	if strings.HasPrefix(key, rulesPrefix) {
+		_, ok := l.validation.rules[key[len(rulesPrefix):]]
+		return ok
+	} else if strings.HasPrefix(key, fieldsPrefix) {
+		_, ok := l.validation.fields[key[len(fieldsPrefix):]]
+		return ok
+	}
+	_, ok := l.lines[key]
+	return ok
+}
+
+// covers returns true if the given key is defined in this language. Unlike `defines()`,
+// rule keys also match their type-dependent variants: "validation.rules.min" is covered
+// if "validation.rules.min.string" is defined.
+func (l *Language) covers(key string) bool {
+	if l.defines(key) {
+		return true
+	}
+	if !strings.HasPrefix(key, rulesPrefix) {
+		return false
+	}
+	rule := key[len(rulesPrefix):] + "."
+	for k := range l.validation.rules {
+		if strings.HasPrefix(k, rule) {
+			return true
+		}
+	}
+	return false
+}
+
+// DiffKeys compares this language with the given keys, typically extracted from the source
+// code with `Extract()`. Rule keys without type suffix match their type-dependent variants.
+//
+// Only the normal lines can be reported as unused: the validation lines are not, because
+// the messages of the built-in rules and the field names cannot be extracted from the source code.
+func (l *Language) DiffKeys(keys []string) *CatalogDiff {
+	diff := &CatalogDiff{Language: l.name, Missing: []string{}, Unused: []string{}}
+	for _, k := range lo.Uniq(keys) {
+		if !l.covers(k) {
+			diff.Missing = append(diff.Missing, k)
+		}
+	}
+	used := lo.SliceToMap(keys, func(k string) (string, struct{}) { return k, struct{}{} })
+	for k := range l.lines {
+		if _, ok := used[k]; !ok {
+			diff.Unused = append(diff.Unused, k)
+		}
+	}
+	slices.Sort(diff.Missing)
+	slices.Sort(diff.Unused)
+	return diff
+}
+
+// Diff compares the given language with the default language. Lines defined in the
+// default language but not in the given language are missing, lines defined in the given
+// language but not in the default language are unused.
+//
+// If the given language is not available, all the lines of the default language are missing.
+func (l *Languages) Diff(lang string) *CatalogDiff {
+	reference := l.GetDefault()
+	language, ok := l.languages[lang]
+	if !ok {
+		return &CatalogDiff{Language: lang, Missing: reference.Keys(), Unused: []string{}}
+	}
+
+	diff := &CatalogDiff{Language: lang, Missing: []string{}, Unused: []string{}}
+	for _, k := range reference.Keys() {
+		if !language.defines(k) {
+			diff.Missing = append(diff.Missing, k)
+		}
+	}
+	for _, k := range language.Keys() {
+		if !reference.defines(k) {
+			diff.Unused = append(diff.Unused, k)
+		}
+	}
+	return diff
+}
+
+// DiffAll compares every available language with the default language. See `Diff()`.
+// The results are sorted by language name.
+func (l *Languages) DiffAll() []*CatalogDiff {
+	names := lo.Without(l.GetAvailableLanguages(), l.Default)
+	slices.Sort(names)
+	return lo.Map(names, func(name string, _ int) *CatalogDiff {
+		return l.Diff(name)
+	})
+}
+
+// Skeleton returns a new language containing all the lines of the given language, plus
+// an empty line for each key defined in the default language or in the given keys
+// (typically extracted from the source code with `Extract()`) that is missing in the given
+// language. Empty lines are skipped by `Get()`, which returns the key instead.
+//
+// The returned language can be written to a language directory with `Language.WriteFiles()`.
+func (l *Languages) Skeleton(lang string, keys []string) *Language {
+	skeleton := &Language{
+		name:  lang,
+		lines: map[string]string{},
+		validation: validationLines{
+			rules:  map[string]string{},
+			fields: map[string]string{},
+		},
+	}
+	if existing, ok := l.languages[lang]; ok {
+		mergeLang(skeleton, existing)
+	}
+
+	for _, k := range l.GetDefault().Keys() {
+		if !skeleton.defines(k) {
+			skeleton.set(k, "")
+		}
+	}
+	for _, k := range keys {
+		if !skeleton.covers(k) {
+			skeleton.set(k, "")
+		}
+	}
+	return skeleton
+}
+
+func (l *Language) set(key, line string) {
+	switch {
+	case strings.HasPrefix(key, rulesPrefix):
+		l.validation.rules[key[len(rulesPrefix):]] = line
+	case strings.HasPrefix(key, fieldsPrefix):
+		l.validation.fields[key[len(fieldsPrefix):]] = line
+	default:
+		l.lines[key] = line
+	}
+}
+
+// WriteFiles writes the lines of this language to the "locale.json", "rules.json" and
+// "fields.json" files in the given directory. Files for which there are no lines are not written.
+// Existing files are overwritten.
+//
+// Creates the directory if needed and if the given FS implements `fsutil.MkdirFS`.
+func (l *Language) WriteFiles(fs fsutil.WritableFS, directory string) error {
+	return l.Export(fs, directory, FormatJSON)
+}
+
+// Export writes the lines of this language to the "locale", "rules" and "fields" files
+// in the given directory, using the given format. Files for which there are no lines are not written.
+// Existing files are overwritten. The lines are sorted by key.
+//
+// The comments and context of the lines are exported if the format supports them. When
+// exporting to XLIFF, the lines of the default language are used as source.
+//
+// Creates the directory if needed and if the given FS implements `fsutil.MkdirFS`.
+func (l *Language) Export(fs fsutil.WritableFS, directory string, format FileFormat) error {
+	codec, ok := codecs[string(format)]
+	if !ok {
+		return errors.Errorf("unsupported language file format %q", format)
+	}
+	if mkdirFS, ok := fs.(fsutil.MkdirFS); ok {
+		if err := mkdirFS.MkdirAll(directory, os.ModePerm); err != nil {
+			return errors.New(err)
+		}
+	}
+
+	info := fileInfo{language: l.name}
+	if l.languages != nil {
+		info.sourceLanguage = l.languages.Default
+	}
+	files := []struct {
+		lines  map[string]string
+		name   string
+		prefix string
+	}{
+		{name: "locale", lines: l.lines},
+		{name: "rules", lines: l.validation.rules, prefix: rulesPrefix},
+		{name: "fields", lines: l.validation.fields, prefix: fieldsPrefix},
+	}
+	for _, f := range files {
+		if len(f.lines) == 0 {
+			continue
+		}
+		info.name = f.name + string(format)
+		if err := writeLangFile(fs, directory+"/"+info.name, codec, info, l.sortedFileLines(f.prefix, f.lines)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeLangFile(fs fsutil.WritableFS, path string, codec fileCodec, info fileInfo, lines []fileLine) (err error) {
+	buf := &bytes.Buffer{}
+	if err := codec.encode(buf, info, lines); err != nil {
+		return errors.New(err)
+	}
+	var writer io.ReadWriteCloser
+	writer, err = fs.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0660)
+	if err != nil {
+		return errors.New(err)
+	}
+	defer func() {
+		closeError := writer.Close()
+		if err == nil && closeError != nil {
+			err = errors.New(closeError)
+		}
+	}()
+	if _, err = writer.Write(buf.Bytes()); err != nil {
+		err = errors.New(err)
+	}
+	return
+}
@@ -0,0 +1,126 @@
+package lang
+
+import (
+	"os"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"goyave.dev/goyave/v5/util/fsutil/osfs"
+)
+
+func newCatalogTestLanguages(t *testing.T) *Languages {
+	fs := fstest.MapFS{
+		"en-US/locale.json": &fstest.MapFile{Data: []byte(`{"greetings": "Hello", "farewell": "Goodbye"}`)},
+		"en-US/fields.json": &fstest.MapFile{Data: []byte(`{"email": "email address"}`)},
+		"fr-FR/locale.json": &fstest.MapFile{Data: []byte(`{"greetings": "Bonjour", "stale": "Obsolète"}`)},
+		"fr-FR/rules.json":  &fstest.MapFile{Data: []byte(`{"required": "Le champ :field est requis.", "min.string": ""}`)},
+		"de-DE/locale.json": &fstest.MapFile{Data: []byte(`{"greetings": "Hallo"}`)},
+	}
+	languages := &Languages{
+		languages: map[string]*Language{},
+		Default:   "en-US",
+	}
+	languages.add(&Language{
+		name:  "en-US",
+		lines: map[string]string{},
+		validation: validationLines{
+			rules:  map[string]string{"required": "The :field is required.", "min.string": "", "min.numeric": ""},
+			fields: map[string]string{},
+		},
+	})
+	require.NoError(t, languages.LoadDirectory(fs, "."))
+	return languages
+}
+
+func TestKeys(t *testing.T) {
+	languages := newCatalogTestLanguages(t)
+	assert.Equal(t, []string{
+		"farewell",
+		"greetings",
+		"validation.fields.email",
+		"validation.rules.min.numeric",
+		"validation.rules.min.string",
+		"validation.rules.required",
+	}, languages.GetDefault().Keys())
+}
+
+func TestDiff(t *testing.T) {
+	languages := newCatalogTestLanguages(t)
+
+	diff := languages.Diff("fr-FR")
+	assert.Equal(t, &CatalogDiff{
+		Language: "fr-FR",
+		Missing:  []string{"farewell", "validation.fields.email", "validation.rules.min.numeric"},
+		Unused:   []string{"stale"},
+	}, diff)
+	assert.False(t, diff.IsEmpty())
+
+	assert.True(t, languages.Diff("en-US").IsEmpty())
+
+	diff = languages.Diff("it-IT")
+	assert.Equal(t, languages.GetDefault().Keys(), diff.Missing)
+	assert.Empty(t, diff.Unused)
+
+	diffs := languages.DiffAll()
+	require.Len(t, diffs, 2)
+	assert.Equal(t, "de-DE", diffs[0].Language)
+	assert.Equal(t, "fr-FR", diffs[1].Language)
+}
+
+func TestDiffKeys(t *testing.T) {
+	languages := newCatalogTestLanguages(t)
+	keys := []string{"greetings", "greetings", "new-line", "validation.rules.min", "validation.rules.max", "validation.rules.required"}
+
+	diff := languages.GetDefault().DiffKeys(keys)
+	assert.Equal(t, &CatalogDiff{
+		Language: "en-US",
+		Missing:  []string{"new-line", "validation.rules.max"},
+		Unused:   []string{"farewell"},
+	}, diff)
+}
+
+func TestSkeleton(t *testing.T) {
+	languages := newCatalogTestLanguages(t)
+
+	skeleton := languages.Skeleton("fr-FR", []string{"new-line", "greetings", "validation.rules.min", "validation.rules.max"})
+	assert.Equal(t, "fr-FR", skeleton.Name())
+	assert.Equal(t, map[string]string{"greetings": "Bonjour", "stale": "Obsolète", "farewell": "", "new-line": ""}, skeleton.lines)
+	assert.Equal(t, map[string]string{"required": "Le champ :field est requis.", "min.string": "", "min.numeric": "", "max": ""}, skeleton.validation.rules)
+	assert.Equal(t, map[string]string{"email": ""}, skeleton.validation.fields)
+
+	// The existing language is not modified
+	assert.NotContains(t, languages.GetLanguage("fr-FR").lines, "farewell")
+
+	skeleton = languages.Skeleton("it-IT", nil)
+	assert.Equal(t, map[string]string{"greetings": "", "farewell": ""}, skeleton.lines)
+}
+
+func TestWriteFiles(t *testing.T) {
+	languages := newCatalogTestLanguages(t)
+	skeleton := languages.Skeleton("it-IT", []string{"a<b>"})
+	skeleton.validation.fields = map[string]string{}
+
+	fs := &osfs.FS{}
+	dir := t.TempDir() + "/it-IT"
+	require.NoError(t, skeleton.WriteFiles(fs, dir))
+
+	content, err := os.ReadFile(dir + "/locale.json")
+	require.NoError(t, err)
+	assert.Equal(t, "{\n    \"a<b>\": \"\",\n    \"farewell\": \"\",\n    \"greetings\": \"\"\n}\n", string(content))
+	assert.FileExists(t, dir+"/rules.json")
+	assert.NoFileExists(t, dir+"/fields.json")
+
+	// Generated files can be loaded
+	require.NoError(t, languages.Load(fs, "it-IT", dir))
+	assert.True(t, languages.IsAvailable("it-IT"))
+	assert.Equal(t, "Goodbye", languages.Get("it-IT", "farewell")) // Empty lines use the fallback chain
+
+	// Overwrite
+	skeleton.lines = map[string]string{"b": ""}
+	require.NoError(t, skeleton.WriteFiles(fs, dir))
+	content, err = os.ReadFile(dir + "/locale.json")
+	require.NoError(t, err)
+	assert.Equal(t, "{\n    \"b\": \"\"\n}\n", string(content))
+}
@@ -0,0 +1,179 @@
+package lang
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	pathutil "path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/samber/lo"
+	"goyave.dev/goyave/v5/util/errors"
+	"goyave.dev/goyave/v5/util/fsutil"
+)
+
+// Extract scans the Go source files in the given directory and its sub-directories and
+// returns the sorted keys of the language lines they use. Test files, hidden directories,
+// "vendor" and "testdata" directories are skipped.
+//
+// The following expressions are detected, as long as the key is a string literal:
+//   - calls to `Get()` and `Format()` on a language: `request.Lang.Get("key")`,
+//     `server.Lang.Get("en-US", "key")`, `lang.GetDefault().Format("key", args)`.
+//     The receiver must be named "Lang", "lang" or "language", or be a call to `GetLanguage()`,
+//     `GetDefault()` or `DetectLanguage()`.
+//   - validators: methods `Name()` returning a string literal, declared on a type that also
+//     declares a `Validate()` method, produce a "validation.rules.<name>" key.
+//
+// The result can be compared to a language with `Language.DiffKeys()`, or used to
+// generate a skeleton with `Languages.Skeleton()`.
+func Extract(filesystem fsutil.FS, directory string) ([]string, error) {
+	keys := []string{}
+	// Validator types are identified by the directory and the name of the type
+	validatorNames := map[string]string{}
+	validators := map[string]bool{}
+	err := fs.WalkDir(filesystem, directory, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		name := d.Name()
+		if d.IsDir() {
+			if path != directory && (strings.HasPrefix(name, ".") || name == "vendor" || name == "testdata") {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			return nil
+		}
+
+		src, err := fs.ReadFile(filesystem, path)
+		if err != nil {
+			return err
+		}
+		file, err := parser.ParseFile(token.NewFileSet(), path, src, parser.SkipObjectResolution)
+		if err != nil {
+			return err
+		}
+		pkg := pathutil.Dir(path)
+		ast.Inspect(file, func(n ast.Node) bool {
+			switch node := n.(type) {
+			case *ast.CallExpr:
+				if key, ok := extractCallKey(node); ok {
+					keys = append(keys, key)
+				}
+			case *ast.FuncDecl:
+				receiver := receiverTypeName(node)
+				if receiver == "" {
+					return true
+				}
+				switch node.Name.Name {
+				case "Validate":
+					validators[pkg+"."+receiver] = true
+				case "Name":
+					if name, ok := returnedLiteral(node); ok {
+						validatorNames[pkg+"."+receiver] = name
+					}
+				}
+			}
+			return true
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, errors.New(err)
+	}
+
+	for receiver, name := range validatorNames {
+		if validators[receiver] {
+			keys = append(keys, rulesPrefix+name)
+		}
+	}
+	keys = lo.Uniq(keys)
+	slices.Sort(keys)
+	return keys, nil
+}
+
+// extractCallKey returns the key used by the given call if it is a call to
+// `Get()` or `Format()` on a language.
+func extractCallKey(call *ast.CallExpr) (string, bool) {
+	selector, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || (selector.Sel.Name != "Get" && selector.Sel.Name != "Format") || !isLanguageExpr(selector.X) || len(call.Args) == 0 {
+		return "", false
+	}
+
+	first, firstIsLiteral := stringLiteral(call.Args[0])
+	if len(call.Args) >= 2 && (!firstIsLiteral || isLanguageName(first)) {
+		// `Languages.Get(lang, line)`: the first argument is the language.
+		// Placeholder names (":field") are not keys.
+		if line, ok := stringLiteral(call.Args[1]); ok && !strings.HasPrefix(line, ":") {
+			return line, true
+		}
+	}
+	return first, firstIsLiteral
+}
+
+func isLanguageExpr(expr ast.Expr) bool {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return e.Name == "Lang" || e.Name == "lang" || e.Name == "language"
+	case *ast.SelectorExpr:
+		return e.Sel.Name == "Lang"
+	case *ast.CallExpr:
+		if selector, ok := e.Fun.(*ast.SelectorExpr); ok {
+			switch selector.Sel.Name {
+			case "GetLanguage", "GetDefault", "DetectLanguage":
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isLanguageName returns true if the given string looks like a language tag ("en", "en-US").
+func isLanguageName(str string) bool {
+	base, region, _ := strings.Cut(str, "-")
+	return len(base) >= 2 && len(base) <= 3 && strings.ToLower(base) == base &&
+		len(region) <= 4 && !strings.ContainsAny(str, ". ")
+}
+
+func stringLiteral(expr ast.Expr) (string, bool) {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	str, err := strconv.Unquote(lit.Value)
+	return str, err == nil
+}
+
+func receiverTypeName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return ""
+	}
+	typ := fn.Recv.List[0].Type
+	if star, ok := typ.(*ast.StarExpr); ok {
+		typ = star.X
+	}
+	if index, ok := typ.(*ast.IndexExpr); ok {
+		typ = index.X
+	}
+	if ident, ok := typ.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+// returnedLiteral returns the string literal returned by the given function
+// if its body is a single return statement.
+func returnedLiteral(fn *ast.FuncDecl) (string, bool) {
+	if fn.Body == nil || len(fn.Body.List) != 1 || fn.Type.Params.NumFields() != 0 {
+		return "", false
+	}
+	ret, ok := fn.Body.List[0].(*ast.ReturnStmt)
+	if !ok || len(ret.Results) != 1 {
+		return "", false
+	}
+	return stringLiteral(ret.Results[0])
+}
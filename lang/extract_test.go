@@ -0,0 +1,86 @@
+package lang
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtract(t *testing.T) {
+	fs := fstest.MapFS{
+		"main.go": &fstest.MapFile{Data: []byte(`package main
+
+func handler(response *goyave.Response, request *goyave.Request) {
+	message := request.Lang.Get("greetings", ":name", "Alice")
+	_ = request.Lang.Format("cart.items", map[string]any{"count": 3})
+	_ = server.Lang.Get("en-US", "farewell")
+	_ = server.Lang.Format(request.Lang.Name(), "welcome", nil)
+	_ = server.Lang.GetLanguage("fr-FR").Get("validation.fields.email")
+	_ = lang.GetDefault().Get("default-line")
+	_ = request.Lang.Get(dynamic, ":placeholder")
+	_ = other.Get("not-a-line")
+	_ = request.Lang.Get()
+}
+`)},
+		"validation/custom.go": &fstest.MapFile{Data: []byte(`package validation
+
+type CustomValidator struct{ BaseValidator }
+
+func (v *CustomValidator) Validate(ctx *Context) bool { return true }
+
+type GenericValidator[T any] struct{ BaseValidator }
+
+func (v *GenericValidator[T]) Validate(ctx *Context) bool { return true }
+func (v *GenericValidator[T]) Name() string { return "generic" }
+
+type NotAValidator struct{}
+
+func (NotAValidator) Name() string { return "not-a-validator" }
+
+type DynamicValidator struct{}
+
+func (v *DynamicValidator) Validate(ctx *Context) bool { return true }
+func (v *DynamicValidator) Name() string { return v.name }
+`)},
+		"validation/custom_name.go": &fstest.MapFile{Data: []byte(`package validation
+
+func (v *CustomValidator) Name() string { return "custom" }
+`)},
+		"validation/custom_test.go": &fstest.MapFile{Data: []byte(`package validation
+
+func test() { request.Lang.Get("test-line") }
+`)},
+		"vendor/lib/lib.go":   &fstest.MapFile{Data: []byte(`package lib; func f() { request.Lang.Get("vendor-line") }`)},
+		"testdata/data.go":    &fstest.MapFile{Data: []byte(`package data; func f() { request.Lang.Get("testdata-line") }`)},
+		".hidden/hidden.go":   &fstest.MapFile{Data: []byte(`package hidden; func f() { request.Lang.Get("hidden-line") }`)},
+		"resources/lang.json": &fstest.MapFile{Data: []byte(`{}`)},
+	}
+
+	keys, err := Extract(fs, ".")
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"cart.items",
+		"default-line",
+		"farewell",
+		"greetings",
+		"validation.fields.email",
+		"validation.rules.custom",
+		"validation.rules.generic",
+		"welcome",
+	}, keys)
+
+	t.Run("syntax_error", func(t *testing.T) {
+		fs := fstest.MapFS{"main.go": &fstest.MapFile{Data: []byte(`package main; func {`)}}
+		keys, err := Extract(fs, ".")
+		require.Error(t, err)
+		assert.Nil(t, keys)
+	})
+
+	t.Run("directory_not_found", func(t *testing.T) {
+		keys, err := Extract(fstest.MapFS{}, "notadir")
+		require.Error(t, err)
+		assert.Nil(t, keys)
+	})
+}
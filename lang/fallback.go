@@ -0,0 +1,87 @@
+package lang
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/samber/lo"
+)
+
+// SetFallback defines the explicit fallback chain of the given language. The fallback
+// languages are used, in order, when a line is missing in the given language (see `Language.Get()`),
+// or when the given language is requested but not available (see `DetectLanguage()`).
+// The chain is followed recursively: the fallbacks of the fallback languages are used next.
+// The default language is always the last fallback.
+//
+//	languages.SetFallback("fr-CA", "fr-FR") // fr-CA → fr-FR → default
+//	languages.SetFallback("fr-BE", "fr-CA") // fr-BE → fr-CA → fr-FR → default
+//
+// The fallback languages don't need to be loaded yet. Languages that are not available
+// are skipped. Replaces the existing fallback chain of the given language. Calling this
+// without fallbacks removes the explicit chain.
+func (l *Languages) SetFallback(lang string, fallbacks ...string) {
+	if len(fallbacks) == 0 {
+		delete(l.fallbacks, lang)
+		return
+	}
+	l.fallbacks[lang] = slices.Clone(fallbacks)
+}
+
+// SetPreferredVariants defines the order in which the variants of the given base language
+// are chosen by `DetectLanguage()` when no variant is requested (for example "en").
+// Variants that are not in the given list come after, in the order in which they were loaded.
+//
+//	languages.SetPreferredVariants("en", "en-GB", "en-US")
+func (l *Languages) SetPreferredVariants(base string, variants ...string) {
+	l.preferences[base] = slices.Clone(variants)
+}
+
+// FallbackChain returns the names of the available languages used, in order, when a
+// line is missing in the given language. The chain is composed of the explicit fallbacks of
+// the language (see `SetFallback()`), followed by the default language. The given language
+// is never part of its chain.
+func (l *Languages) FallbackChain(lang string) []string {
+	chain := l.explicitFallbacks(lang)
+	if lang != l.Default && l.IsAvailable(l.Default) && !lo.Contains(chain, l.Default) {
+		chain = append(chain, l.Default)
+	}
+	return chain
+}
+
+// explicitFallbacks returns the available languages of the explicit fallback chain of
+// the given language, followed recursively.
+func (l *Languages) explicitFallbacks(lang string) []string {
+	chain := []string{}
+	visited := map[string]struct{}{lang: {}}
+	var walk func(lang string)
+	walk = func(lang string) {
+		for _, fallback := range l.fallbacks[lang] {
+			if _, ok := visited[fallback]; ok {
+				continue
+			}
+			visited[fallback] = struct{}{}
+			if l.IsAvailable(fallback) {
+				chain = append(chain, fallback)
+			}
+			walk(fallback)
+		}
+	}
+	walk(lang)
+	return chain
+}
+
+// preferredVariant returns the preferred available variant of the given
+// base language, or nil if there are none.
+func (l *Languages) preferredVariant(base string) *Language {
+	for _, name := range l.preferences[base] {
+		if language, ok := l.languages[name]; ok {
+			return language
+		}
+	}
+	for _, name := range l.order {
+		if strings.HasPrefix(name, base+"-") {
+			return l.languages[name]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package lang
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newFallbackTestLanguages(t *testing.T) *Languages {
+	fs := fstest.MapFS{
+		"fr-FR/locale.json": &fstest.MapFile{Data: []byte(`{"greetings": "Bonjour", "car": "voiture", "empty": ""}`)},
+		"fr-CA/locale.json": &fstest.MapFile{Data: []byte(`{"car": "char"}`)},
+		"fr-BE/locale.json": &fstest.MapFile{Data: []byte(`{}`)},
+		"en-GB/locale.json": &fstest.MapFile{Data: []byte(`{"colour": "colour"}`)},
+	}
+	languages := New()
+	languages.languages["en-US"].lines["colour"] = "color"
+	languages.languages["en-US"].lines["empty"] = "empty line"
+	// Load in a specific order
+	for _, name := range []string{"fr-FR", "fr-CA", "en-GB", "fr-BE"} {
+		require.NoError(t, languages.Load(fs, name, name))
+	}
+	return languages
+}
+
+func TestGetAvailableLanguagesOrder(t *testing.T) {
+	languages := newFallbackTestLanguages(t)
+	assert.Equal(t, []string{"en-US", "fr-FR", "fr-CA", "en-GB", "fr-BE"}, languages.GetAvailableLanguages())
+
+	// Reloading doesn't change the order
+	require.NoError(t, languages.Load(fstest.MapFS{"fr-FR/locale.json": &fstest.MapFile{Data: []byte(`{}`)}}, "fr-FR", "fr-FR"))
+	assert.Equal(t, []string{"en-US", "fr-FR", "fr-CA", "en-GB", "fr-BE"}, languages.GetAvailableLanguages())
+}
+
+func TestFallbackChain(t *testing.T) {
+	languages := newFallbackTestLanguages(t)
+
+	assert.Equal(t, []string{"en-US"}, languages.FallbackChain("fr-CA"))
+	assert.Empty(t, languages.FallbackChain("en-US"))
+
+	languages.SetFallback("fr-CA", "fr-FR")
+	languages.SetFallback("fr-BE", "fr-XX", "fr-CA")
+	assert.Equal(t, []string{"fr-FR", "en-US"}, languages.FallbackChain("fr-CA"))
+	assert.Equal(t, []string{"fr-CA", "fr-FR", "en-US"}, languages.FallbackChain("fr-BE"))
+
+	// Cycles and explicit default
+	languages.SetFallback("fr-FR", "en-US", "fr-BE")
+	assert.Equal(t, []string{"fr-CA", "fr-FR", "en-US"}, languages.FallbackChain("fr-BE"))
+	assert.Equal(t, []string{"en-US", "fr-BE", "fr-CA"}, languages.FallbackChain("fr-FR"))
+
+	languages.SetFallback("fr-FR")
+	assert.Equal(t, []string{"en-US"}, languages.FallbackChain("fr-FR"))
+
+	// Default changed
+	languages.Default = "fr-FR"
+	assert.Equal(t, []string{"fr-FR"}, languages.FallbackChain("en-US"))
+	assert.Equal(t, []string{"fr-FR"}, languages.FallbackChain("fr-CA"))
+}
+
+func TestGetWithFallback(t *testing.T) {
+	languages := newFallbackTestLanguages(t)
+	languages.SetFallback("fr-CA", "fr-FR")
+
+	frCA := languages.GetLanguage("fr-CA")
+	assert.Equal(t, "char", frCA.Get("car"))
+	assert.Equal(t, "Bonjour", frCA.Get("greetings"))
+	assert.Equal(t, "color", frCA.Get("colour"))
+	assert.Equal(t, "empty line", frCA.Get("empty"))
+	assert.Equal(t, "The email is required.", frCA.Get("validation.rules.required", ":field", "email"))
+	assert.Equal(t, "notaline", frCA.Get("notaline"))
+	assert.Equal(t, "Bonjour", frCA.Format("greetings", nil))
+
+	assert.Equal(t, "colour", languages.Get("en-GB", "colour"))
+	assert.Equal(t, "color", languages.Get("fr-BE", "colour"))
+	assert.Equal(t, "color", languages.Get("en-US", "colour"))
+	assert.Equal(t, "greetings", languages.Get("en-US", "greetings"))
+}
+
+func TestDetectLanguageFallback(t *testing.T) {
+	languages := newFallbackTestLanguages(t)
+
+	// Registration order
+	assert.Equal(t, "fr-FR", languages.DetectLanguage("fr").Name())
+	assert.Equal(t, "en-US", languages.DetectLanguage("en").Name())
+	assert.Equal(t, "en-GB", languages.DetectLanguage("en-GB").Name())
+	assert.Equal(t, "en-US", languages.DetectLanguage("f").Name()) // Not a prefix match
+
+	// Preferred variants
+	languages.SetPreferredVariants("fr", "fr-XX", "fr-CA")
+	languages.SetPreferredVariants("en", "en-GB")
+	assert.Equal(t, "fr-CA", languages.DetectLanguage("fr").Name())
+	assert.Equal(t, "en-GB", languages.DetectLanguage("en").Name())
+	assert.Equal(t, "fr-FR", languages.DetectLanguage("fr-FR").Name())
+
+	// Explicit fallback
+	assert.Equal(t, "en-US", languages.DetectLanguage("fr-CH").Name())
+	assert.Equal(t, "en-GB", languages.DetectLanguage("fr-CH, en-GB;q=0.8").Name())
+	languages.SetFallback("fr-CH", "fr-XX", "fr-BE")
+	assert.Equal(t, "fr-BE", languages.DetectLanguage("fr-CH, en-GB;q=0.8").Name())
+	languages.SetFallback("fr-LU", "en-US")
+	assert.Equal(t, "en-US", languages.DetectLanguage("fr-LU, en-GB;q=0.8").Name())
+}
+
+func TestMatch(t *testing.T) {
+	languages := newFallbackTestLanguages(t)
+	assert.Equal(t, "fr-CA", languages.Match("fr-CA").Name())
+	assert.Equal(t, "fr-FR", languages.Match("de-DE, fr;q=0.5").Name())
+	assert.Equal(t, "en-US", languages.Match("de-DE, *;q=0.5").Name())
+	assert.Nil(t, languages.Match("de-DE"))
+	assert.Nil(t, languages.Match(""))
+}
@@ -0,0 +1,429 @@
+package lang
+
+import (
+	"bufio"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/samber/lo"
+	"gopkg.in/yaml.v3"
+)
+
+// FileFormat a language file format, identified by its extension.
+type FileFormat string
+
+// Supported language file formats.
+const (
+	// FormatJSON a JSON object associating keys to lines.
+	FormatJSON FileFormat = ".json"
+
+	// FormatYAML a YAML mapping associating keys to lines. Nested mappings are flattened
+	// using dot-separated keys. Comments placed before a key or at the end of its line are preserved.
+	// A "# context: <context>" comment line defines the context of the line.
+	FormatYAML FileFormat = ".yml"
+
+	// FormatPO a gettext PO file. The "msgid" is the key and the "msgstr" is the line.
+	// Untranslated entries (empty "msgstr") are ignored.
+	// Translator and extracted comments ("#" and "#.") and the context ("msgctxt") are preserved.
+	// For plural entries, the first form ("msgstr[0]") is used: use the ICU MessageFormat
+	// syntax for plurals instead (see `Language.Format()`).
+	FormatPO FileFormat = ".po"
+
+	// FormatXLIFF an XLIFF 1.2 or 2.0 document. The "id" of the translation units is the key
+	// and the target is the line. Notes and context are preserved. Documents are exported
+	// using XLIFF 1.2, with the lines of the default language as source.
+	FormatXLIFF FileFormat = ".xlf"
+)
+
+// fileExtensions the supported language file extensions, in loading order.
+var fileExtensions = []string{".json", ".yaml", ".yml", ".po", ".xlf", ".xliff"}
+
+// lineMeta the metadata of a language line, preserved from the file it was loaded from.
+type lineMeta struct {
+	// comment the comments of the line, separated by line breaks.
+	comment string
+
+	// context the disambiguating context of the line.
+	context string
+}
+
+// fileLine a line read from or written to a language file. The key doesn't include
+// the file prefix ("validation.rules.").
+type fileLine struct {
+	lineMeta
+	key   string
+	value string
+
+	// source the line of the default language, only set when exporting.
+	source string
+}
+
+// fileInfo the information about the language file being written.
+type fileInfo struct {
+	language       string
+	sourceLanguage string
+	name           string
+}
+
+type fileCodec struct {
+	decode func(r io.Reader) ([]fileLine, error)
+	encode func(w io.Writer, info fileInfo, lines []fileLine) error
+}
+
+// codecs the supported language file formats, identified by their extension.
+var codecs = map[string]fileCodec{
+	".json":  {decode: decodeJSONLines, encode: encodeJSONLines},
+	".yaml":  {decode: decodeYAMLLines, encode: encodeYAMLLines},
+	".yml":   {decode: decodeYAMLLines, encode: encodeYAMLLines},
+	".po":    {decode: decodePOLines, encode: encodePOLines},
+	".xlf":   {decode: decodeXLIFFLines, encode: encodeXLIFFLines},
+	".xliff": {decode: decodeXLIFFLines, encode: encodeXLIFFLines},
+}
+
+func decodeJSONLines(r io.Reader) ([]fileLine, error) {
+	lines := map[string]string{}
+	if err := json.NewDecoder(r).Decode(&lines); err != nil {
+		return nil, err
+	}
+	return lo.MapToSlice(lines, func(k, v string) fileLine {
+		return fileLine{key: k, value: v}
+	}), nil
+}
+
+func encodeJSONLines(w io.Writer, _ fileInfo, lines []fileLine) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "    ")
+	return encoder.Encode(lo.SliceToMap(lines, func(l fileLine) (string, string) {
+		return l.key, l.value
+	}))
+}
+
+const yamlContextPrefix = "context: "
+
+func decodeYAMLLines(r io.Reader) ([]fileLine, error) {
+	doc := &yaml.Node{}
+	if err := yaml.NewDecoder(r).Decode(doc); err != nil {
+		if errors.Is(err, io.EOF) {
+			return []fileLine{}, nil
+		}
+		return nil, err
+	}
+	if len(doc.Content) == 0 {
+		return []fileLine{}, nil
+	}
+	lines := []fileLine{}
+	if err := flattenYAML(doc.Content[0], "", &lines); err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
+
+func flattenYAML(node *yaml.Node, prefix string, lines *[]fileLine) error {
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: expected a mapping", node.Line)
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		switch value.Kind {
+		case yaml.MappingNode:
+			if err := flattenYAML(value, prefix+key.Value+".", lines); err != nil {
+				return err
+			}
+		case yaml.ScalarNode:
+			comments := lo.Compact([]string{key.HeadComment, key.LineComment, value.LineComment})
+			*lines = append(*lines, fileLine{
+				key:      prefix + key.Value,
+				value:    value.Value,
+				lineMeta: parseYAMLComment(strings.Join(comments, "\n")),
+			})
+		default:
+			return fmt.Errorf("line %d: expected a string or a mapping for key %q", value.Line, prefix+key.Value)
+		}
+	}
+	return nil
+}
+
+// parseYAMLComment removes the "#" markers of the given YAML comment. A "context: "
+// comment line defines the context of the line.
+func parseYAMLComment(comment string) lineMeta {
+	meta := lineMeta{}
+	if comment == "" {
+		return meta
+	}
+	lines := []string{}
+	for _, l := range strings.Split(comment, "\n") {
+		l = strings.TrimPrefix(strings.TrimPrefix(l, "#"), " ")
+		if context, ok := strings.CutPrefix(l, yamlContextPrefix); ok {
+			meta.context = context
+			continue
+		}
+		lines = append(lines, l)
+	}
+	meta.comment = strings.Join(lines, "\n")
+	return meta
+}
+
+func encodeYAMLLines(w io.Writer, _ fileInfo, lines []fileLine) error {
+	mapping := &yaml.Node{Kind: yaml.MappingNode}
+	for _, l := range lines {
+		key := &yaml.Node{Kind: yaml.ScalarNode, Value: l.key}
+		comment := l.comment
+		if l.context != "" {
+			comment = strings.TrimPrefix(comment+"\n"+yamlContextPrefix+l.context, "\n")
+		}
+		if comment != "" {
+			key.HeadComment = "# " + strings.ReplaceAll(comment, "\n", "\n# ")
+		}
+		mapping.Content = append(mapping.Content, key, &yaml.Node{Kind: yaml.ScalarNode, Value: l.value, Tag: "!!str"})
+	}
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{mapping}}); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+func decodePOLines(r io.Reader) ([]fileLine, error) {
+	lines := []fileLine{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var (
+		current  fileLine
+		comments []string
+		field    *string // The field receiving the continuation strings
+		ignored  string  // Receives plural forms other than the first one
+		hasID    bool
+		obsolete bool
+		number   int
+	)
+	flush := func() {
+		// The entry with an empty msgid is the header. Untranslated entries (empty msgstr)
+		// are skipped so they don't override the lines of the other files.
+		if hasID && !obsolete && current.key != "" && current.value != "" {
+			current.comment = strings.Join(comments, "\n")
+			lines = append(lines, current)
+		}
+		current, comments, field, hasID, obsolete = fileLine{}, nil, nil, false, false
+	}
+
+	for scanner.Scan() {
+		number++
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "":
+			flush()
+			continue
+		case strings.HasPrefix(line, "#~"):
+			obsolete = true
+			continue
+		case strings.HasPrefix(line, "#:"), strings.HasPrefix(line, "#,"), strings.HasPrefix(line, "#|"):
+			continue // References, flags and previous strings are not preserved
+		case strings.HasPrefix(line, "#."):
+			comments = append(comments, strings.TrimSpace(line[2:]))
+			continue
+		case strings.HasPrefix(line, "#"):
+			comments = append(comments, strings.TrimPrefix(line[1:], " "))
+			continue
+		case strings.HasPrefix(line, "\""):
+			if field == nil {
+				return nil, fmt.Errorf("line %d: unexpected string", number)
+			}
+			str, err := unquotePO(line)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", number, err)
+			}
+			*field += str
+			continue
+		}
+
+		keyword, value, _ := strings.Cut(line, " ")
+		str, err := unquotePO(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", number, err)
+		}
+		switch {
+		case keyword == "msgctxt":
+			if hasID {
+				flush()
+			}
+			current.context = str
+			field = &current.context
+		case keyword == "msgid":
+			if hasID {
+				flush()
+			}
+			hasID = true
+			current.key = str
+			field = &current.key
+		case keyword == "msgstr", keyword == "msgstr[0]":
+			current.value = str
+			field = &current.value
+		case keyword == "msgid_plural", strings.HasPrefix(keyword, "msgstr["):
+			ignored = str
+			field = &ignored
+		default:
+			return nil, fmt.Errorf("line %d: unexpected keyword %q", number, keyword)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	flush()
+	return lines, nil
+}
+
+func unquotePO(str string) (string, error) {
+	if len(str) < 2 || str[0] != '"' || str[len(str)-1] != '"' {
+		return "", fmt.Errorf("invalid string %s", str)
+	}
+	s, err := strconv.Unquote(str)
+	if err != nil {
+		return "", fmt.Errorf("invalid string %s", str)
+	}
+	return s, nil
+}
+
+var poEscaper = strings.NewReplacer("\\", "\\\\", "\"", "\\\"", "\n", "\\n", "\t", "\\t", "\r", "\\r")
+
+func quotePO(str string) string {
+	return "\"" + poEscaper.Replace(str) + "\""
+}
+
+func encodePOLines(w io.Writer, info fileInfo, lines []fileLine) error {
+	b := &strings.Builder{}
+	b.WriteString("msgid \"\"\nmsgstr \"\"\n")
+	b.WriteString(quotePO("Language: "+info.language+"\n") + "\n")
+	b.WriteString(quotePO("MIME-Version: 1.0\n") + "\n")
+	b.WriteString(quotePO("Content-Type: text/plain; charset=UTF-8\n") + "\n")
+	b.WriteString(quotePO("Content-Transfer-Encoding: 8bit\n") + "\n")
+	for _, l := range lines {
+		b.WriteString("\n")
+		if l.comment != "" {
+			for _, c := range strings.Split(l.comment, "\n") {
+				b.WriteString(strings.TrimRight("# "+c, " ") + "\n")
+			}
+		}
+		if l.context != "" {
+			b.WriteString("msgctxt " + quotePO(l.context) + "\n")
+		}
+		b.WriteString("msgid " + quotePO(l.key) + "\n")
+		b.WriteString("msgstr " + quotePO(l.value) + "\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+type xliffDocument struct {
+	XMLName xml.Name `xml:"xliff"`
+	Version string   `xml:"version,attr"`
+	Files   []struct {
+		// XLIFF 1.2
+		TransUnits []struct {
+			ID       string   `xml:"id,attr"`
+			Target   string   `xml:"target"`
+			Notes    []string `xml:"note"`
+			Contexts []struct {
+				Type  string `xml:"context-type,attr"`
+				Value string `xml:",chardata"`
+			} `xml:"context-group>context"`
+		} `xml:"body>trans-unit"`
+
+		// XLIFF 2.0
+		Units []struct {
+			ID       string   `xml:"id,attr"`
+			Notes    []string `xml:"notes>note"`
+			Segments []struct {
+				Target string `xml:"target"`
+			} `xml:"segment"`
+		} `xml:"unit"`
+	} `xml:"file"`
+}
+
+func decodeXLIFFLines(r io.Reader) ([]fileLine, error) {
+	doc := &xliffDocument{}
+	if err := xml.NewDecoder(r).Decode(doc); err != nil {
+		return nil, err
+	}
+
+	lines := []fileLine{}
+	for _, f := range doc.Files {
+		for _, u := range f.TransUnits {
+			line := fileLine{key: u.ID, value: u.Target, lineMeta: lineMeta{comment: strings.Join(u.Notes, "\n")}}
+			for _, c := range u.Contexts {
+				if c.Type == "x-context" {
+					line.context = c.Value
+				}
+			}
+			lines = append(lines, line)
+		}
+		for _, u := range f.Units {
+			line := fileLine{key: u.ID, lineMeta: lineMeta{comment: strings.Join(u.Notes, "\n")}}
+			for _, s := range u.Segments {
+				line.value += s.Target
+			}
+			lines = append(lines, line)
+		}
+	}
+	return lines, nil
+}
+
+func encodeXLIFFLines(w io.Writer, info fileInfo, lines []fileLine) error {
+	b := &strings.Builder{}
+	b.WriteString(xml.Header)
+	b.WriteString(`<xliff version="1.2" xmlns="urn:oasis:names:tc:xliff:document:1.2">` + "\n")
+	fmt.Fprintf(b, "  <file original=\"%s\" source-language=\"%s\" target-language=\"%s\" datatype=\"plaintext\">\n", escapeXML(info.name), escapeXML(info.sourceLanguage), escapeXML(info.language))
+	b.WriteString("    <body>\n")
+	for _, l := range lines {
+		fmt.Fprintf(b, "      <trans-unit id=\"%s\">\n", escapeXML(l.key))
+		source := l.source
+		if source == "" {
+			source = l.key
+		}
+		fmt.Fprintf(b, "        <source>%s</source>\n", escapeXML(source))
+		fmt.Fprintf(b, "        <target>%s</target>\n", escapeXML(l.value))
+		if l.comment != "" {
+			fmt.Fprintf(b, "        <note>%s</note>\n", escapeXML(l.comment))
+		}
+		if l.context != "" {
+			b.WriteString("        <context-group purpose=\"information\">\n")
+			fmt.Fprintf(b, "          <context context-type=\"x-context\">%s</context>\n", escapeXML(l.context))
+			b.WriteString("        </context-group>\n")
+		}
+		b.WriteString("      </trans-unit>\n")
+	}
+	b.WriteString("    </body>\n  </file>\n</xliff>\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func escapeXML(str string) string {
+	b := &strings.Builder{}
+	_ = xml.EscapeText(b, []byte(str))
+	return b.String()
+}
+
+// sortedFileLines returns the lines of the given map with their metadata, sorted by key.
+func (l *Language) sortedFileLines(prefix string, lines map[string]string) []fileLine {
+	keys := lo.Keys(lines)
+	slices.Sort(keys)
+	var source *Language
+	if l.languages != nil {
+		source = l.languages.GetDefault()
+	}
+	return lo.Map(keys, func(k string, _ int) fileLine {
+		line := fileLine{key: k, value: lines[k], lineMeta: l.meta[prefix+k]}
+		if source != nil {
+			line.source = source.ownLine(prefix + k)
+		}
+		return line
+	})
+}
@@ -0,0 +1,282 @@
+package lang
+
+import (
+	"os"
+	"testing"
+	"testing/fstest"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"goyave.dev/goyave/v5/util/fsutil"
+	"goyave.dev/goyave/v5/util/fsutil/osfs"
+)
+
+func TestLoadFormats(t *testing.T) {
+	cases := []struct {
+		files  fstest.MapFS
+		lines  map[string]string
+		rules  map[string]string
+		fields map[string]string
+		meta   map[string]lineMeta
+		desc   string
+	}{
+		{
+			desc: "yaml",
+			files: fstest.MapFS{
+				"fr-FR/locale.yml": {Data: []byte(`# Shown on the home page
+greetings: Bonjour
+cart:
+  empty: Votre panier est vide # Cart page
+`)},
+				"fr-FR/rules.yaml": {Data: []byte("required: \"Le champ :field est requis.\"\n")},
+			},
+			lines: map[string]string{"greetings": "Bonjour", "cart.empty": "Votre panier est vide"},
+			rules: map[string]string{"required": "Le champ :field est requis."},
+			meta: map[string]lineMeta{
+				"greetings":  {comment: "Shown on the home page"},
+				"cart.empty": {comment: "Cart page"},
+			},
+		},
+		{
+			desc: "po",
+			files: fstest.MapFS{
+				"fr-FR/locale.po": {Data: []byte(`msgid ""
+msgstr ""
+"Language: fr-FR\n"
+
+# Shown on the home page
+#. extracted comment
+#: main.go:12
+#, fuzzy
+msgid "greetings"
+msgstr "Bonjour"
+
+msgctxt "button"
+msgid "open"
+msgstr ""
+"Ouv"
+"rir \"maintenant\""
+
+msgid "items"
+msgid_plural "items"
+msgstr[0] "{count, plural, one {# article} other {# articles}}"
+msgstr[1] "ignored"
+
+#~ msgid "obsolete"
+#~ msgstr "Obsolète"
+`)},
+				"fr-FR/fields.po": {Data: []byte("msgid \"email\"\nmsgstr \"adresse e-mail\"\n")},
+			},
+			lines: map[string]string{
+				"greetings": "Bonjour",
+				"open":      "Ouvrir \"maintenant\"",
+				"items":     "{count, plural, one {# article} other {# articles}}",
+			},
+			fields: map[string]string{"email": "adresse e-mail"},
+			meta: map[string]lineMeta{
+				"greetings": {comment: "Shown on the home page\nextracted comment"},
+				"open":      {context: "button"},
+			},
+		},
+		{
+			desc: "xliff_1.2",
+			files: fstest.MapFS{
+				"fr-FR/locale.xlf": {Data: []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<xliff version="1.2" xmlns="urn:oasis:names:tc:xliff:document:1.2">
+  <file original="locale.xlf" source-language="en-US" target-language="fr-FR" datatype="plaintext">
+    <body>
+      <trans-unit id="greetings">
+        <source>Hello</source>
+        <target>Bonjour &amp; bienvenue</target>
+        <note>Shown on the home page</note>
+        <context-group purpose="information">
+          <context context-type="x-context">home</context>
+        </context-group>
+      </trans-unit>
+    </body>
+  </file>
+</xliff>`)},
+			},
+			lines: map[string]string{"greetings": "Bonjour & bienvenue"},
+			meta: map[string]lineMeta{
+				"greetings": {comment: "Shown on the home page", context: "home"},
+			},
+		},
+		{
+			desc: "xliff_2.0",
+			files: fstest.MapFS{
+				"fr-FR/rules.xliff": {Data: []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<xliff version="2.0" xmlns="urn:oasis:names:tc:xliff:document:2.0" srcLang="en-US" trgLang="fr-FR">
+  <file id="rules">
+    <unit id="required">
+      <notes><note>Validation message</note></notes>
+      <segment>
+        <source>The :field is required.</source>
+        <target>Le champ :field est requis.</target>
+      </segment>
+    </unit>
+  </file>
+</xliff>`)},
+			},
+			rules: map[string]string{"required": "Le champ :field est requis."},
+			meta: map[string]lineMeta{
+				"validation.rules.required": {comment: "Validation message"},
+			},
+		},
+		{
+			desc: "override",
+			files: fstest.MapFS{
+				"fr-FR/locale.json": {Data: []byte(`{"greetings": "Salut", "farewell": "Au revoir"}`)},
+				"fr-FR/locale.po":   {Data: []byte("msgid \"greetings\"\nmsgstr \"Bonjour\"\n")},
+			},
+			lines: map[string]string{"greetings": "Bonjour", "farewell": "Au revoir"},
+		},
+		{
+			desc: "override_untranslated_po",
+			files: fstest.MapFS{
+				"fr-FR/locale.json": {Data: []byte(`{"greetings": "Salut", "farewell": "Au revoir"}`)},
+				"fr-FR/locale.po":   {Data: []byte("msgid \"greetings\"\nmsgstr \"Bonjour\"\n\n# Not translated yet\nmsgid \"farewell\"\nmsgstr \"\"\n")},
+			},
+			lines: map[string]string{"greetings": "Bonjour", "farewell": "Au revoir"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			languages := New()
+			require.NoError(t, languages.Load(fsutil.NewEmbed(c.files), "fr-FR", "fr-FR"))
+			language := languages.GetLanguage("fr-FR")
+			assert.Equal(t, lo.Ternary(c.lines == nil, map[string]string{}, c.lines), language.lines)
+			assert.Equal(t, lo.Ternary(c.rules == nil, map[string]string{}, c.rules), language.validation.rules)
+			assert.Equal(t, lo.Ternary(c.fields == nil, map[string]string{}, c.fields), language.validation.fields)
+			assert.Equal(t, c.meta, language.meta)
+		})
+	}
+}
+
+func TestLoadFormatsInvalid(t *testing.T) {
+	cases := map[string]string{
+		"locale.yml":  "greetings: [a, b]",
+		"locale.po":   "msgid \"greetings\"\nmsgstr Bonjour\n",
+		"locale.xlf":  "<xliff",
+		"rules.json":  "[]",
+		"fields.yaml": "- a",
+	}
+	for name, content := range cases {
+		t.Run(name, func(t *testing.T) {
+			files := fstest.MapFS{"fr-FR/" + name: {Data: []byte(content)}}
+			err := New().Load(fsutil.NewEmbed(files), "fr-FR", "fr-FR")
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "failed to load language file fr-FR/"+name)
+		})
+	}
+}
+
+func TestExport(t *testing.T) {
+	newLanguages := func(t *testing.T) *Languages {
+		languages := New()
+		languages.GetDefault().lines["greetings"] = "Hello"
+		files := fstest.MapFS{
+			"fr-FR/locale.po": {Data: []byte(`# Shown on the home page
+msgctxt "home"
+msgid "greetings"
+msgstr "Bonjour \"<b>\"\n"
+
+msgid "farewell"
+msgstr "Au revoir"
+`)},
+			"fr-FR/rules.json": {Data: []byte(`{"required": "Le champ :field est requis."}`)},
+		}
+		require.NoError(t, languages.Load(fsutil.NewEmbed(files), "fr-FR", "fr-FR"))
+		return languages
+	}
+
+	cases := []struct {
+		format FileFormat
+		locale string
+	}{
+		{
+			format: FormatJSON,
+			locale: "{\n    \"farewell\": \"Au revoir\",\n    \"greetings\": \"Bonjour \\\"<b>\\\"\\n\"\n}\n",
+		},
+		{
+			format: FormatYAML,
+			locale: "farewell: Au revoir\n# Shown on the home page\n# context: home\ngreetings: |\n  Bonjour \"<b>\"\n",
+		},
+		{
+			format: FormatPO,
+			locale: `msgid ""
+msgstr ""
+"Language: fr-FR\n"
+"MIME-Version: 1.0\n"
+"Content-Type: text/plain; charset=UTF-8\n"
+"Content-Transfer-Encoding: 8bit\n"
+
+msgid "farewell"
+msgstr "Au revoir"
+
+# Shown on the home page
+msgctxt "home"
+msgid "greetings"
+msgstr "Bonjour \"<b>\"\n"
+`,
+		},
+		{
+			format: FormatXLIFF,
+			locale: `<?xml version="1.0" encoding="UTF-8"?>
+<xliff version="1.2" xmlns="urn:oasis:names:tc:xliff:document:1.2">
+  <file original="locale.xlf" source-language="en-US" target-language="fr-FR" datatype="plaintext">
+    <body>
+      <trans-unit id="farewell">
+        <source>farewell</source>
+        <target>Au revoir</target>
+      </trans-unit>
+      <trans-unit id="greetings">
+        <source>Hello</source>
+        <target>Bonjour &#34;&lt;b&gt;&#34;&#xA;</target>
+        <note>Shown on the home page</note>
+        <context-group purpose="information">
+          <context context-type="x-context">home</context>
+        </context-group>
+      </trans-unit>
+    </body>
+  </file>
+</xliff>
+`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(string(c.format), func(t *testing.T) {
+			languages := newLanguages(t)
+			language := languages.GetLanguage("fr-FR")
+
+			fs := &osfs.FS{}
+			dir := t.TempDir() + "/fr-FR"
+			require.NoError(t, language.Export(fs, dir, c.format))
+
+			content, err := os.ReadFile(dir + "/locale" + string(c.format))
+			require.NoError(t, err)
+			assert.Equal(t, c.locale, string(content))
+			assert.FileExists(t, dir+"/rules"+string(c.format))
+			assert.NoFileExists(t, dir+"/fields"+string(c.format))
+
+			// Exported files can be loaded back
+			loaded := New()
+			require.NoError(t, loaded.Load(fs, "fr-FR", dir))
+			reloaded := loaded.GetLanguage("fr-FR")
+			assert.Equal(t, language.lines, reloaded.lines)
+			assert.Equal(t, language.validation.rules, reloaded.validation.rules)
+			if c.format != FormatJSON { // JSON doesn't support comments
+				assert.Equal(t, language.meta, reloaded.meta)
+			}
+		})
+	}
+
+	t.Run("unsupported_format", func(t *testing.T) {
+		language := newLanguages(t).GetLanguage("fr-FR")
+		err := language.Export(&osfs.FS{}, t.TempDir(), FileFormat(".txt"))
+		require.Error(t, err)
+	})
+}
@@ -1,8 +1,9 @@
 package lang
 
 import (
-	"encoding/json"
-	"strings"
+	"maps"
+	pathutil "path"
+	"slices"
 
 	"github.com/samber/lo"
 	"goyave.dev/goyave/v5/util/errors"
@@ -16,7 +17,17 @@ import (
 // more languages when this instance is expected to receive reads.
 type Languages struct {
 	languages map[string]*Language
-	Default   string
+
+	// fallbacks the explicit fallback chains, indexed by language name.
+	fallbacks map[string][]string
+
+	// preferences the preferred variants, indexed by base language.
+	preferences map[string][]string
+
+	Default string
+
+	// order the names of the languages, in the order in which they were added.
+	order []string
 }
 
 // New create a `Languages` with preloaded default language "en-US".
@@ -25,10 +36,12 @@ type Languages struct {
 // in the returned struct.
 func New() *Languages {
 	l := &Languages{
-		languages: make(map[string]*Language, 1),
-		Default:   enUS.name,
+		languages:   make(map[string]*Language, 1),
+		fallbacks:   map[string][]string{},
+		preferences: map[string][]string{},
+		Default:     enUS.name,
 	}
-	l.languages[enUS.name] = enUS.clone()
+	l.add(enUS.clone())
 	return l
 }
 
@@ -79,7 +92,13 @@ func (l *Languages) LoadDirectory(fs fsutil.FS, directory string) error {
 //	  ├─ rules.json      (contains the validation messages)
 //	  └─ fields.json     (contains the field names)
 //
-// Each file is optional.
+// Each file is optional. Instead of JSON, the files can use the YAML (".yaml", ".yml"),
+// gettext PO (".po") or XLIFF (".xlf", ".xliff") formats. See `FileFormat` for more details.
+// If a file exists in several formats, they are all loaded in this order: JSON, YAML, PO, XLIFF.
+// Lines from the later files override the ones from the previous files.
+//
+// The comments and context of the lines are preserved when exporting the language
+// with `Language.Export()`, if the format supports them.
 func (l *Languages) Load(fs fsutil.FS, language, path string) error {
 	if fsutil.IsDirectory(fs, path) {
 		return l.load(fs, language, path)
@@ -97,24 +116,38 @@ func (l *Languages) load(fs fsutil.FS, lang string, path string) error {
 			fields: map[string]string{},
 		},
 	}
-	if err := readLangFile(fs, path+"/locale.json", &langStruct.lines); err != nil {
-		return err
+	files := []struct {
+		lines  map[string]string
+		name   string
+		prefix string
+	}{
+		{name: "locale", lines: langStruct.lines},
+		{name: "rules", lines: langStruct.validation.rules, prefix: rulesPrefix},
+		{name: "fields", lines: langStruct.validation.fields, prefix: fieldsPrefix},
 	}
-	if err := readLangFile(fs, path+"/rules.json", &langStruct.validation.rules); err != nil {
-		return err
-	}
-	if err := readLangFile(fs, path+"/fields.json", &langStruct.validation.fields); err != nil {
-		return err
+	for _, f := range files {
+		for _, ext := range fileExtensions {
+			if err := readLangFile(fs, path+"/"+f.name+ext, langStruct, f.prefix, f.lines); err != nil {
+				return err
+			}
+		}
 	}
 
 	if existingLang, exists := l.languages[lang]; exists {
 		mergeLang(existingLang, langStruct)
 	} else {
-		l.languages[lang] = langStruct
+		l.add(langStruct)
 	}
 	return nil
 }
 
+// add registers a new language.
+func (l *Languages) add(language *Language) {
+	language.languages = l
+	l.languages[language.name] = language
+	l.order = append(l.order, language.name)
+}
+
 // GetLanguage returns a language by its name.
 // If the language is not available, returns a dummy language
 // that will always return the entry name.
@@ -143,15 +176,15 @@ func (l *Languages) IsAvailable(lang string) bool {
 	return exists
 }
 
-// GetAvailableLanguages returns a slice of all loaded languages.
-// This can be used to generate different routes for all languages
+// GetAvailableLanguages returns a slice of all loaded languages, in the order
+// in which they were loaded. This can be used to generate different routes for all languages
 // supported by your applications.
 //
 //	/en/products
 //	/fr/produits
 //	...
 func (l *Languages) GetAvailableLanguages() []string {
-	return lo.Keys(l.languages)
+	return slices.Clone(l.order)
 }
 
 // DetectLanguage detects the language to use based on the given lang string.
@@ -160,27 +193,41 @@ func (l *Languages) GetAvailableLanguages() []string {
 // If "*" is provided, the default language will be used.
 // If multiple languages are given, the first available language will be used,
 // and if none are available, the default language will be used.
-// If no variant is given (for example "en"), the first available variant will be used.
+//
+// See `Match()` for the matching rules.
 func (l *Languages) DetectLanguage(lang string) *Language {
+	if match := l.Match(lang); match != nil {
+		return match
+	}
+	return l.GetLanguage(l.Default)
+}
+
+// Match returns the first available language matching the given lang string, or `nil`
+// if none of the given languages are available. The given lang string can use the HTTP
+// "Accept-Language" header format. If "*" is accepted, the default language is returned.
+//
+// For each given language, in order of preference:
+//   - if the language is available, it is used.
+//   - otherwise, the first available language of its explicit fallback chain is used (see `SetFallback()`).
+//   - if no variant is given (for example "en"), the preferred variant is used (see `SetPreferredVariants()`).
+//     If no preference is set for the language, the first loaded variant is used.
+func (l *Languages) Match(lang string) *Language {
 	values := httputil.ParseMultiValuesHeader(lang)
 	for _, lang := range values {
 		if lang.Value == "*" { // Accept anything, so return default language
-			break
+			return l.GetLanguage(l.Default)
 		}
 		if match, ok := l.languages[lang.Value]; ok {
 			return match
 		}
-		for key, v := range l.languages {
-			if strings.HasPrefix(key, lang.Value) {
-				// TODO priority for languages? The "first available variant" is random because keys are not ordered.
-				// Ordering alphabetically won't always produce the desired result (e.g. en-UK < en-US)
-				// Can create a slice of language names (so the order will be the order in which the languages have been added)
-				return v
-			}
+		if fallbacks := l.explicitFallbacks(lang.Value); len(fallbacks) > 0 {
+			return l.languages[fallbacks[0]]
+		}
+		if variant := l.preferredVariant(lang.Value); variant != nil {
+			return variant
 		}
 	}
-
-	return l.GetLanguage(l.Default)
+	return nil
 }
 
 // Get a language line.
@@ -208,7 +255,22 @@ func (l *Languages) Get(lang string, line string, placeholders ...string) string
 	return language.Get(line, placeholders...)
 }
 
-func readLangFile(fs fsutil.FS, path string, dest any) (err error) {
+// Format a language line using the ICU MessageFormat syntax.
+// See `Language.Format()` for more details.
+//
+// If the language doesn't exist, returns the exact "line" argument.
+//
+//	lang.Format("en-US", "cart.items", map[string]any{"count": 3})
+func (l *Languages) Format(lang string, line string, args map[string]any) string {
+	language, exists := l.languages[lang]
+	if !exists {
+		return line
+	}
+
+	return language.Format(line, args)
+}
+
+func readLangFile(fs fsutil.FS, path string, language *Language, prefix string, dest map[string]string) (err error) {
 	if !fsutil.FileExists(fs, path) {
 		return nil
 	}
@@ -221,17 +283,32 @@ func readLangFile(fs fsutil.FS, path string, dest any) (err error) {
 		}
 	}()
 
-	err = json.NewDecoder(langFile).Decode(&dest)
+	lines, err := codecs[pathutil.Ext(path)].decode(langFile)
 	if err != nil {
-		err = errors.Errorf("failed to load language file %s: %w", path, err)
+		return errors.Errorf("failed to load language file %s: %w", path, err)
 	}
-	return
+	for _, line := range lines {
+		dest[line.key] = line.value
+		if line.comment != "" || line.context != "" {
+			if language.meta == nil {
+				language.meta = map[string]lineMeta{}
+			}
+			language.meta[prefix+line.key] = line.lineMeta
+		}
+	}
+	return nil
 }
 
 func mergeLang(dst *Language, src *Language) {
 	mergeMap(dst.lines, src.lines)
 	mergeMap(dst.validation.rules, src.validation.rules)
 	mergeMap(dst.validation.fields, src.validation.fields)
+	if src.meta != nil {
+		if dst.meta == nil {
+			dst.meta = make(map[string]lineMeta, len(src.meta))
+		}
+		maps.Copy(dst.meta, src.meta)
+	}
 }
 
 func mergeMap(dst map[string]string, src map[string]string) {
@@ -35,10 +35,15 @@ func (suite *LangTestSuite) SetupSuite() {
 
 func (suite *LangTestSuite) TestNew() {
 	l := New()
+	expectedLang := enUS.clone()
 	expected := &Languages{
-		languages: map[string]*Language{enUS.name: enUS},
-		Default:   enUS.name,
+		languages:   map[string]*Language{enUS.name: expectedLang},
+		fallbacks:   map[string][]string{},
+		preferences: map[string][]string{},
+		Default:     enUS.name,
+		order:       []string{enUS.name},
 	}
+	expectedLang.languages = expected
 	suite.Equal(expected, l)
 }
 
@@ -51,7 +56,7 @@ func (suite *LangTestSuite) TestLoadError() {
 
 func (suite *LangTestSuite) TestLoadInvalid() {
 	dst := map[string]string{}
-	suite.Error(readLangFile(&osfs.FS{}, "resources/lang/invalid.json", &dst))
+	suite.Error(readLangFile(&osfs.FS{}, "resources/lang/invalid.json", &Language{}, "", dst))
 }
 
 func (suite *LangTestSuite) TestLoadOverride() {
@@ -69,7 +74,8 @@ func (suite *LangTestSuite) TestLoad() {
 	suite.NoError(err)
 	suite.Len(l.languages, 2)
 	expected := &Language{
-		name: "en-UK",
+		name:      "en-UK",
+		languages: l,
 		lines: map[string]string{
 			"malformed-request": "Malformed request",
 			"malformed-json":    "Malformed JSON",
@@ -86,7 +92,8 @@ func (suite *LangTestSuite) TestLoad() {
 	suite.NoError(err)
 	suite.Len(l.languages, 2)
 	expected = &Language{
-		name: "en-UK",
+		name:      "en-UK",
+		languages: l,
 		lines: map[string]string{
 			"malformed-request": "Malformed request",
 			"malformed-json":    "Malformed JSON",
@@ -1,6 +1,9 @@
 package lang
 
-import "strings"
+import (
+	"maps"
+	"strings"
+)
 
 type validationLines struct {
 	// Default messages for rules
@@ -12,7 +15,15 @@ type validationLines struct {
 
 // Language represents a full Language.
 type Language struct {
-	lines      map[string]string
+	lines map[string]string
+
+	// languages the container of this language, used to resolve the fallback chain.
+	languages *Languages
+
+	// meta the comments and context of the lines loaded from files supporting them,
+	// indexed by full key. Nil if no line has metadata.
+	meta map[string]lineMeta
+
 	validation validationLines
 	name       string
 }
@@ -35,6 +46,9 @@ func (l *Language) clone() *Language {
 	mergeMap(cpy.lines, l.lines)
 	mergeMap(cpy.validation.rules, l.validation.rules)
 	mergeMap(cpy.validation.fields, l.validation.fields)
+	if l.meta != nil {
+		cpy.meta = maps.Clone(l.meta)
+	}
 
 	return cpy
 }
@@ -48,7 +62,9 @@ func (l *Language) clone() *Language {
 // For normal lines, just use the name of the line. Note that if you have
 // a line called "validation", it won't conflict with the dot-separated paths.
 //
-// If not found, returns the exact "line" argument.
+// If not found, the line is looked up in the languages of the fallback chain of this
+// language (see `Languages.FallbackChain()`). If not found in any of them,
+// returns the exact "line" argument.
 //
 // The placeholders parameter is a variadic associative slice of placeholders and their
 // replacement. In the following example, the placeholder ":username" will be replaced
@@ -56,13 +72,33 @@ func (l *Language) clone() *Language {
 //
 //	lang.Get("greetings", ":username", user.Name)
 func (l *Language) Get(line string, placeholders ...string) string {
+	return convertEmptyLine(line, l.lookup(line), placeholders)
+}
+
+// lookup returns the raw language line identified by the given path, using
+// the fallback chain if this language doesn't define it. Returns an empty
+// string if the line doesn't exist.
+func (l *Language) lookup(line string) string {
+	if str := l.ownLine(line); str != "" || l.languages == nil {
+		return str
+	}
+	for _, fallback := range l.languages.FallbackChain(l.name) {
+		if str := l.languages.languages[fallback].ownLine(line); str != "" {
+			return str
+		}
+	}
+	return ""
+}
+
+// ownLine returns the raw language line identified by the given path,
+// or an empty string if this language doesn't define it.
+func (l *Language) ownLine(line string) string {
 	if strings.HasPrefix(line, "validation.rules.") {
-		return convertEmptyLine(line, l.validation.rules[line[17:]], placeholders)
+		return l.validation.rules[line[17:]]
 	} else if strings.HasPrefix(line, "validation.fields.") {
-		return convertEmptyLine(line, l.validation.fields[line[18:]], placeholders)
+		return l.validation.fields[line[18:]]
 	}
-
-	return convertEmptyLine(line, l.lines[line], placeholders)
+	return l.lines[line]
 }
 
 func convertEmptyLine(entry, line string, placeholders []string) string {
@@ -0,0 +1,328 @@
+package lang
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
+)
+
+// DateStyle the length of a formatted date or time.
+type DateStyle string
+
+// Date styles, matching the ICU MessageFormat date and time styles.
+const (
+	DateShort  DateStyle = "short"
+	DateMedium DateStyle = "medium"
+	DateLong   DateStyle = "long"
+)
+
+// Locale the date and time formatting conventions of a language, used by the date formatting
+// methods of `Language`. Numbers are formatted using the CLDR data of `golang.org/x/text`.
+//
+// Date and time patterns use a subset of the CLDR date field symbols:
+//   - "y": year, "yy": two-digit year
+//   - "M": month, "MM": zero-padded month, "MMM": abbreviated month name, "MMMM": month name
+//   - "d": day, "dd": zero-padded day
+//   - "H", "HH": hour (0-23), "h", "hh": hour (1-12), "a": AM/PM marker
+//   - "mm": minutes, "ss": seconds
+//
+// Text between single quotes is not interpreted ("d 'de' MMMM").
+type Locale struct {
+	// DatePatterns the date patterns for each style.
+	DatePatterns map[DateStyle]string
+
+	// TimePatterns the time patterns for each style.
+	TimePatterns map[DateStyle]string
+
+	// MonthNames the names of the months, used in the patterns containing "MMMM".
+	MonthNames [12]string
+
+	// MonthAbbreviations the abbreviated names of the months, used in the patterns containing "MMM".
+	MonthAbbreviations [12]string
+
+	// DayPeriods the AM and PM markers.
+	DayPeriods [2]string
+}
+
+var (
+	localesMu sync.RWMutex
+	locales   = map[string]*Locale{
+		"en": {
+			DatePatterns:       map[DateStyle]string{DateShort: "M/d/yy", DateMedium: "MMM d, y", DateLong: "MMMM d, y"},
+			TimePatterns:       map[DateStyle]string{DateShort: "h:mm a", DateMedium: "h:mm:ss a", DateLong: "h:mm:ss a"},
+			MonthNames:         [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+			MonthAbbreviations: [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+			DayPeriods:         [2]string{"AM", "PM"},
+		},
+		"en-GB": {
+			DatePatterns:       map[DateStyle]string{DateShort: "dd/MM/y", DateMedium: "d MMM y", DateLong: "d MMMM y"},
+			TimePatterns:       map[DateStyle]string{DateShort: "HH:mm", DateMedium: "HH:mm:ss", DateLong: "HH:mm:ss"},
+			MonthNames:         [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+			MonthAbbreviations: [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sept", "Oct", "Nov", "Dec"},
+			DayPeriods:         [2]string{"am", "pm"},
+		},
+		"fr": {
+			DatePatterns:       map[DateStyle]string{DateShort: "dd/MM/y", DateMedium: "d MMM y", DateLong: "d MMMM y"},
+			TimePatterns:       map[DateStyle]string{DateShort: "HH:mm", DateMedium: "HH:mm:ss", DateLong: "HH:mm:ss"},
+			MonthNames:         [12]string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+			MonthAbbreviations: [12]string{"janv.", "févr.", "mars", "avr.", "mai", "juin", "juil.", "août", "sept.", "oct.", "nov.", "déc."},
+			DayPeriods:         [2]string{"AM", "PM"},
+		},
+		"de": {
+			DatePatterns:       map[DateStyle]string{DateShort: "dd.MM.yy", DateMedium: "dd.MM.y", DateLong: "d. MMMM y"},
+			TimePatterns:       map[DateStyle]string{DateShort: "HH:mm", DateMedium: "HH:mm:ss", DateLong: "HH:mm:ss"},
+			MonthNames:         [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+			MonthAbbreviations: [12]string{"Jan.", "Feb.", "März", "Apr.", "Mai", "Juni", "Juli", "Aug.", "Sept.", "Okt.", "Nov.", "Dez."},
+			DayPeriods:         [2]string{"AM", "PM"},
+		},
+		"es": {
+			DatePatterns:       map[DateStyle]string{DateShort: "d/M/yy", DateMedium: "d MMM y", DateLong: "d 'de' MMMM 'de' y"},
+			TimePatterns:       map[DateStyle]string{DateShort: "H:mm", DateMedium: "H:mm:ss", DateLong: "H:mm:ss"},
+			MonthNames:         [12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+			MonthAbbreviations: [12]string{"ene", "feb", "mar", "abr", "may", "jun", "jul", "ago", "sept", "oct", "nov", "dic"},
+			DayPeriods:         [2]string{"a.\u00a0m.", "p.\u00a0m."},
+		},
+		"it": {
+			DatePatterns:       map[DateStyle]string{DateShort: "dd/MM/yy", DateMedium: "d MMM y", DateLong: "d MMMM y"},
+			TimePatterns:       map[DateStyle]string{DateShort: "HH:mm", DateMedium: "HH:mm:ss", DateLong: "HH:mm:ss"},
+			MonthNames:         [12]string{"gennaio", "febbraio", "marzo", "aprile", "maggio", "giugno", "luglio", "agosto", "settembre", "ottobre", "novembre", "dicembre"},
+			MonthAbbreviations: [12]string{"gen", "feb", "mar", "apr", "mag", "giu", "lug", "ago", "set", "ott", "nov", "dic"},
+			DayPeriods:         [2]string{"AM", "PM"},
+		},
+		"pt": {
+			DatePatterns:       map[DateStyle]string{DateShort: "dd/MM/y", DateMedium: "d 'de' MMM 'de' y", DateLong: "d 'de' MMMM 'de' y"},
+			TimePatterns:       map[DateStyle]string{DateShort: "HH:mm", DateMedium: "HH:mm:ss", DateLong: "HH:mm:ss"},
+			MonthNames:         [12]string{"janeiro", "fevereiro", "março", "abril", "maio", "junho", "julho", "agosto", "setembro", "outubro", "novembro", "dezembro"},
+			MonthAbbreviations: [12]string{"jan.", "fev.", "mar.", "abr.", "mai.", "jun.", "jul.", "ago.", "set.", "out.", "nov.", "dez."},
+			DayPeriods:         [2]string{"AM", "PM"},
+		},
+		"nl": {
+			DatePatterns:       map[DateStyle]string{DateShort: "dd-MM-y", DateMedium: "d MMM y", DateLong: "d MMMM y"},
+			TimePatterns:       map[DateStyle]string{DateShort: "HH:mm", DateMedium: "HH:mm:ss", DateLong: "HH:mm:ss"},
+			MonthNames:         [12]string{"januari", "februari", "maart", "april", "mei", "juni", "juli", "augustus", "september", "oktober", "november", "december"},
+			MonthAbbreviations: [12]string{"jan", "feb", "mrt", "apr", "mei", "jun", "jul", "aug", "sep", "okt", "nov", "dec"},
+			DayPeriods:         [2]string{"a.m.", "p.m."},
+		},
+		"ru": {
+			DatePatterns:       map[DateStyle]string{DateShort: "dd.MM.y", DateMedium: "d MMM y 'г'.", DateLong: "d MMMM y 'г'."},
+			TimePatterns:       map[DateStyle]string{DateShort: "HH:mm", DateMedium: "HH:mm:ss", DateLong: "HH:mm:ss"},
+			MonthNames:         [12]string{"января", "февраля", "марта", "апреля", "мая", "июня", "июля", "августа", "сентября", "октября", "ноября", "декабря"},
+			MonthAbbreviations: [12]string{"янв.", "февр.", "мар.", "апр.", "мая", "июн.", "июл.", "авг.", "сент.", "окт.", "нояб.", "дек."},
+			DayPeriods:         [2]string{"AM", "PM"},
+		},
+		"pl": {
+			DatePatterns:       map[DateStyle]string{DateShort: "d.MM.y", DateMedium: "d MMM y", DateLong: "d MMMM y"},
+			TimePatterns:       map[DateStyle]string{DateShort: "HH:mm", DateMedium: "HH:mm:ss", DateLong: "HH:mm:ss"},
+			MonthNames:         [12]string{"stycznia", "lutego", "marca", "kwietnia", "maja", "czerwca", "lipca", "sierpnia", "września", "października", "listopada", "grudnia"},
+			MonthAbbreviations: [12]string{"sty", "lut", "mar", "kwi", "maj", "cze", "lip", "sie", "wrz", "paź", "lis", "gru"},
+			DayPeriods:         [2]string{"AM", "PM"},
+		},
+		"ja": {
+			DatePatterns:       map[DateStyle]string{DateShort: "y/MM/dd", DateMedium: "y/MM/dd", DateLong: "y年M月d日"},
+			TimePatterns:       map[DateStyle]string{DateShort: "H:mm", DateMedium: "H:mm:ss", DateLong: "H:mm:ss"},
+			MonthNames:         [12]string{"1月", "2月", "3月", "4月", "5月", "6月", "7月", "8月", "9月", "10月", "11月", "12月"},
+			MonthAbbreviations: [12]string{"1月", "2月", "3月", "4月", "5月", "6月", "7月", "8月", "9月", "10月", "11月", "12月"},
+			DayPeriods:         [2]string{"午前", "午後"},
+		},
+		"zh": {
+			DatePatterns:       map[DateStyle]string{DateShort: "y/M/d", DateMedium: "y年M月d日", DateLong: "y年M月d日"},
+			TimePatterns:       map[DateStyle]string{DateShort: "HH:mm", DateMedium: "HH:mm:ss", DateLong: "HH:mm:ss"},
+			MonthNames:         [12]string{"一月", "二月", "三月", "四月", "五月", "六月", "七月", "八月", "九月", "十月", "十一月", "十二月"},
+			MonthAbbreviations: [12]string{"1月", "2月", "3月", "4月", "5月", "6月", "7月", "8月", "9月", "10月", "11月", "12月"},
+			DayPeriods:         [2]string{"上午", "下午"},
+		},
+	}
+)
+
+// RegisterLocale registers the date and time formatting conventions of the given language. The language
+// can either be a base language ("fr"), used for all its variants, or a variant ("fr-CH"),
+// which has priority over its base language. Replaces the existing locale, if any.
+func RegisterLocale(language string, locale *Locale) {
+	localesMu.Lock()
+	defer localesMu.Unlock()
+	locales[language] = locale
+}
+
+// Locale returns the date and time formatting conventions of this language, its base language, or the
+// conventions of "en" if none are registered.
+func (l *Language) Locale() *Locale {
+	localesMu.RLock()
+	defer localesMu.RUnlock()
+	if locale, ok := locales[l.name]; ok {
+		return locale
+	}
+	if locale, ok := locales[baseLanguage(l.name)]; ok {
+		return locale
+	}
+	return locales["en"]
+}
+
+// FormatNumber formats the given number (of any integer or float type, or a string representing
+// a number) using the conventions of this language. Floats are rounded to three fraction digits at most.
+// Values that are not numbers are formatted with `fmt.Sprint()`.
+//
+//	lang.FormatNumber(1234567.891) // "1,234,567.891" in "en-US", "1 234 567,891" in "fr-FR"
+func (l *Language) FormatNumber(value any) string {
+	return l.formatNumber(value, 0, 3)
+}
+
+// FormatDecimal formats the given number using the conventions of this language, with
+// exactly the given number of fraction digits. Numbers are rounded half to even.
+func (l *Language) FormatDecimal(value any, fractionDigits int) string {
+	return l.formatNumber(value, fractionDigits, fractionDigits)
+}
+
+// FormatPercent formats the given ratio as a percentage using the conventions of this language.
+//
+//	lang.FormatPercent(0.25) // "25%" in "en-US", "25 %" in "fr-FR"
+func (l *Language) FormatPercent(ratio any) string {
+	n, ok := toNumber(ratio)
+	if !ok {
+		return fmt.Sprint(ratio)
+	}
+	return message.NewPrinter(languageTag(l.name)).Sprint(number.Percent(n))
+}
+
+// FormatDate formats the date of the given time using the conventions of this language.
+//
+//	lang.FormatDate(t, lang.DateLong) // "March 7, 2024" in "en-US", "7 mars 2024" in "fr-FR"
+func (l *Language) FormatDate(t time.Time, style DateStyle) string {
+	locale := l.Locale()
+	return locale.formatTime(t, locale.DatePatterns[style])
+}
+
+// FormatTime formats the time of day of the given time using the conventions of this language.
+func (l *Language) FormatTime(t time.Time, style DateStyle) string {
+	locale := l.Locale()
+	return locale.formatTime(t, locale.TimePatterns[style])
+}
+
+func (l *Language) formatNumber(value any, minFraction, maxFraction int) string {
+	n, ok := toNumber(value)
+	if !ok {
+		return fmt.Sprint(value)
+	}
+	if f, isFloat := n.(float64); isFloat && math.Abs(f) < math.Pow10(-maxFraction)/2 {
+		n = 0 // Don't format "-0"
+	}
+	return message.NewPrinter(languageTag(l.name)).Sprint(number.Decimal(n, number.MinFractionDigits(minFraction), number.MaxFractionDigits(maxFraction)))
+}
+
+// languageTag returns the `golang.org/x/text` tag of the given language, or English if
+// the language is unknown.
+func languageTag(name string) language.Tag {
+	tag, err := language.Parse(name)
+	if err != nil {
+		return language.English
+	}
+	return tag
+}
+
+func (l *Locale) formatTime(t time.Time, pattern string) string {
+	result := strings.Builder{}
+	runes := []rune(pattern)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		if r == '\'' {
+			end := i + 1
+			for end < len(runes) && runes[end] != '\'' {
+				end++
+			}
+			if end == i+1 && end < len(runes) {
+				result.WriteRune('\'') // '' is an escaped quote
+			} else {
+				result.WriteString(string(runes[i+1 : min(end, len(runes))]))
+			}
+			i = end + 1
+			continue
+		}
+
+		count := 1
+		for i+count < len(runes) && runes[i+count] == r {
+			count++
+		}
+		i += count
+
+		switch r {
+		case 'y':
+			if count == 2 {
+				result.WriteString(pad(t.Year()%100, 2))
+			} else {
+				result.WriteString(pad(t.Year(), count))
+			}
+		case 'M':
+			switch {
+			case count >= 4:
+				result.WriteString(l.MonthNames[t.Month()-1])
+			case count == 3:
+				result.WriteString(l.MonthAbbreviations[t.Month()-1])
+			default:
+				result.WriteString(pad(int(t.Month()), count))
+			}
+		case 'd':
+			result.WriteString(pad(t.Day(), count))
+		case 'H':
+			result.WriteString(pad(t.Hour(), count))
+		case 'h':
+			hour := t.Hour() % 12
+			if hour == 0 {
+				hour = 12
+			}
+			result.WriteString(pad(hour, count))
+		case 'm':
+			result.WriteString(pad(t.Minute(), count))
+		case 's':
+			result.WriteString(pad(t.Second(), count))
+		case 'a':
+			result.WriteString(l.DayPeriods[t.Hour()/12])
+		default:
+			result.WriteString(strings.Repeat(string(r), count))
+		}
+	}
+	return result.String()
+}
+
+func pad(value, width int) string {
+	str := strconv.Itoa(value)
+	if len(str) < width {
+		str = strings.Repeat("0", width-len(str)) + str
+	}
+	return str
+}
+
+func toFloat64(value any) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	default:
+		i, ok := toInt64(value)
+		return float64(i), ok
+	}
+}
+
+// toNumber returns the given value if it is of an integer or float type, or the number
+// represented by the given string. Returns false if the value is not a number.
+func toNumber(value any) (any, bool) {
+	if str, ok := value.(string); ok {
+		str = strings.TrimSpace(str)
+		if i, err := strconv.ParseInt(str, 10, 64); err == nil {
+			return i, true
+		}
+		f, err := strconv.ParseFloat(str, 64)
+		return f, err == nil
+	}
+	if f, ok := value.(float32); ok {
+		return float64(f), true
+	}
+	_, ok := toFloat64(value)
+	return value, ok
+}
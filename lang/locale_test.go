@@ -0,0 +1,106 @@
+package lang
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatNumber(t *testing.T) {
+	cases := []struct {
+		number   any
+		language string
+		want     string
+	}{
+		{language: "en-US", number: 1234567, want: "1,234,567"},
+		{language: "en-US", number: -1234.5, want: "-1,234.5"},
+		{language: "en-US", number: 0.12345, want: "0.123"},
+		{language: "en-US", number: -0.0001, want: "0"},
+		{language: "en-US", number: 999, want: "999"},
+		{language: "en-US", number: uint16(1000), want: "1,000"},
+		{language: "en-US", number: uint64(math.MaxUint64), want: "18,446,744,073,709,551,615"},
+		{language: "fr-FR", number: 1234567.891, want: "1\u00a0234\u00a0567,891"},
+		{language: "de-DE", number: 1234.5, want: "1.234,5"},
+		{language: "es-ES", number: 12345, want: "12.345"},
+		{language: "ru", number: 1234.5, want: "1\u00a0234,5"},
+		{language: "xx", number: 1234.5, want: "1,234.5"},
+		{language: "en-US", number: math.Inf(1), want: "∞"},
+		{language: "en-US", number: "3", want: "3"},
+		{language: "fr-FR", number: " 1234.5 ", want: "1\u00a0234,5"},
+		{language: "en-US", number: "abc", want: "abc"},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.want, (&Language{name: c.language}).FormatNumber(c.number), "%s %v", c.language, c.number)
+	}
+}
+
+func TestFormatDecimalAndPercent(t *testing.T) {
+	en := &Language{name: "en-US"}
+	fr := &Language{name: "fr-FR"}
+
+	assert.Equal(t, "1,234.50", en.FormatDecimal(1234.5, 2))
+	assert.Equal(t, "3.00", en.FormatDecimal(3, 2))
+	assert.Equal(t, "1\u00a0234", fr.FormatDecimal(1234.5, 0))
+	assert.Equal(t, "2", en.FormatDecimal(2.5, 0))
+
+	assert.Equal(t, "25%", en.FormatPercent(0.25))
+	assert.Equal(t, "25\u00a0%", fr.FormatPercent(0.25))
+	assert.Equal(t, "1,250%", en.FormatPercent(12.5))
+	assert.Equal(t, "50%", en.FormatPercent("0.5"))
+	assert.Equal(t, "abc", en.FormatPercent("abc"))
+}
+
+func TestFormatDate(t *testing.T) {
+	date := time.Date(2024, time.March, 7, 14, 5, 9, 0, time.UTC)
+
+	cases := []struct {
+		language string
+		style    DateStyle
+		date     string
+		time     string
+	}{
+		{language: "en-US", style: DateShort, date: "3/7/24", time: "2:05 PM"},
+		{language: "en-US", style: DateMedium, date: "Mar 7, 2024", time: "2:05:09 PM"},
+		{language: "en-US", style: DateLong, date: "March 7, 2024", time: "2:05:09 PM"},
+		{language: "en-GB", style: DateShort, date: "07/03/2024", time: "14:05"},
+		{language: "fr-FR", style: DateLong, date: "7 mars 2024", time: "14:05:09"},
+		{language: "de-DE", style: DateLong, date: "7. März 2024", time: "14:05:09"},
+		{language: "es-ES", style: DateLong, date: "7 de marzo de 2024", time: "14:05:09"},
+		{language: "ja-JP", style: DateLong, date: "2024年3月7日", time: "14:05:09"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.language+"_"+string(c.style), func(t *testing.T) {
+			l := &Language{name: c.language}
+			assert.Equal(t, c.date, l.FormatDate(date, c.style))
+			assert.Equal(t, c.time, l.FormatTime(date, c.style))
+		})
+	}
+
+	t.Run("midnight", func(t *testing.T) {
+		l := &Language{name: "en-US"}
+		assert.Equal(t, "12:00 AM", l.FormatTime(time.Date(2024, time.March, 7, 0, 0, 0, 0, time.UTC), DateShort))
+	})
+}
+
+func TestRegisterLocale(t *testing.T) {
+	t.Cleanup(func() {
+		localesMu.Lock()
+		delete(locales, "fr-CH")
+		localesMu.Unlock()
+	})
+
+	locale := *locales["fr"]
+	locale.DatePatterns = map[DateStyle]string{DateShort: "dd.MM.yy", DateLong: "d MMMM y 'à' HH'h'"}
+	RegisterLocale("fr-CH", &locale)
+
+	ch := &Language{name: "fr-CH"}
+	assert.Same(t, &locale, ch.Locale())
+	date := time.Date(2024, time.March, 7, 14, 5, 9, 0, time.UTC)
+	assert.Equal(t, "07.03.24", ch.FormatDate(date, DateShort))
+	assert.Equal(t, "7 mars 2024 à 14h", ch.FormatDate(date, DateLong))
+	assert.Equal(t, "7 mars 2024", (&Language{name: "fr-FR"}).FormatDate(date, DateLong))
+}
@@ -0,0 +1,412 @@
+package lang
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"goyave.dev/goyave/v5/util/errors"
+)
+
+// messageCache parsed messages, indexed by their source. Values are `*parsedMessage`.
+var messageCache sync.Map
+
+type parsedMessage struct {
+	err   error
+	nodes []messageNode
+}
+
+type messageNode interface {
+	format(b *strings.Builder, ctx *formatContext)
+}
+
+type formatContext struct {
+	language *Language
+	args     map[string]any
+
+	// plural the number formatted by "#" in the current plural case.
+	plural string
+}
+
+type textNode string
+
+func (n textNode) format(b *strings.Builder, _ *formatContext) {
+	b.WriteString(string(n))
+}
+
+// hashNode the "#" symbol in a plural case.
+type hashNode struct{}
+
+func (hashNode) format(b *strings.Builder, ctx *formatContext) {
+	b.WriteString(ctx.plural)
+}
+
+// argumentNode a simple argument ("{name}") or a formatted argument ("{count, number}").
+type argumentNode struct {
+	name  string
+	typ   string
+	style string
+}
+
+func (n argumentNode) format(b *strings.Builder, ctx *formatContext) {
+	value, ok := ctx.args[n.name]
+	if !ok {
+		b.WriteString("{" + n.name + "}")
+		return
+	}
+
+	l := ctx.language
+	switch n.typ {
+	case "number":
+		switch n.style {
+		case "integer":
+			b.WriteString(l.FormatDecimal(value, 0))
+		case "percent":
+			b.WriteString(l.FormatPercent(value))
+		default:
+			b.WriteString(l.FormatNumber(value))
+		}
+		return
+	case "date", "time":
+		t, isTime := value.(time.Time)
+		if !isTime {
+			break
+		}
+		style := DateStyle(n.style)
+		switch style {
+		case DateShort, DateMedium, DateLong:
+		case "full":
+			style = DateLong
+		default:
+			style = DateMedium
+		}
+		if n.typ == "date" {
+			b.WriteString(l.FormatDate(t, style))
+		} else {
+			b.WriteString(l.FormatTime(t, style))
+		}
+		return
+	}
+
+	switch v := value.(type) {
+	case string:
+		b.WriteString(v)
+	case time.Time:
+		b.WriteString(l.FormatDate(v, DateShort) + " " + l.FormatTime(v, DateShort))
+	default:
+		if _, isNumber := toFloat64(value); isNumber {
+			b.WriteString(l.FormatNumber(value))
+			return
+		}
+		b.WriteString(fmt.Sprint(value))
+	}
+}
+
+// pluralNode a plural argument ("{count, plural, one {# item} other {# items}}").
+type pluralNode struct {
+	cases  map[string][]messageNode
+	name   string
+	offset float64
+}
+
+func (n pluralNode) format(b *strings.Builder, ctx *formatContext) {
+	value := ctx.args[n.name]
+	number, isNumber := toFloat64(value)
+	if str, ok := value.(string); ok {
+		f, err := strconv.ParseFloat(strings.TrimSpace(str), 64)
+		number, isNumber = f, err == nil
+	}
+
+	selected := n.cases[PluralOther]
+	previous := ctx.plural
+	ctx.plural = ""
+	if isNumber {
+		if n.offset != 0 {
+			value = number - n.offset
+		}
+		ctx.plural = ctx.language.FormatNumber(number - n.offset)
+		if c, ok := n.cases["="+strconv.FormatFloat(number, 'f', -1, 64)]; ok {
+			selected = c
+		} else if c, ok := n.cases[ctx.language.PluralCategory(value)]; ok {
+			selected = c
+		}
+	}
+	formatNodes(b, selected, ctx)
+	ctx.plural = previous
+}
+
+// selectNode a select argument ("{gender, select, female {she} male {he} other {they}}").
+type selectNode struct {
+	cases map[string][]messageNode
+	name  string
+}
+
+func (n selectNode) format(b *strings.Builder, ctx *formatContext) {
+	selected, ok := n.cases[fmt.Sprint(ctx.args[n.name])]
+	if !ok {
+		selected = n.cases[PluralOther]
+	}
+	formatNodes(b, selected, ctx)
+}
+
+func formatNodes(b *strings.Builder, nodes []messageNode, ctx *formatContext) {
+	for _, n := range nodes {
+		n.format(b, ctx)
+	}
+}
+
+// parseMessage parses the given ICU MessageFormat message. Parsed messages are cached.
+func parseMessage(message string) ([]messageNode, error) {
+	if cached, ok := messageCache.Load(message); ok {
+		p := cached.(*parsedMessage)
+		return p.nodes, p.err
+	}
+	parser := &messageParser{src: []rune(message)}
+	nodes, err := parser.parse(false, false)
+	if err != nil {
+		err = errors.Errorf("invalid message %q: %w", message, err)
+	}
+	messageCache.Store(message, &parsedMessage{nodes: nodes, err: err})
+	return nodes, err
+}
+
+type messageParser struct {
+	src []rune
+	pos int
+}
+
+func (p *messageParser) peek(offset int) rune {
+	if p.pos+offset >= len(p.src) {
+		return 0
+	}
+	return p.src[p.pos+offset]
+}
+
+func (p *messageParser) skipSpaces() {
+	for p.pos < len(p.src) && unicode.IsSpace(p.src[p.pos]) {
+		p.pos++
+	}
+}
+
+// token reads characters until a space or one of the given delimiters.
+func (p *messageParser) token(delimiters string) string {
+	start := p.pos
+	for p.pos < len(p.src) && !unicode.IsSpace(p.src[p.pos]) && !strings.ContainsRune(delimiters, p.src[p.pos]) {
+		p.pos++
+	}
+	return string(p.src[start:p.pos])
+}
+
+// parse parses a message until its end, or until the closing brace of the
+// current case if nested.
+func (p *messageParser) parse(inPlural, nested bool) ([]messageNode, error) {
+	nodes := []messageNode{}
+	text := strings.Builder{}
+	flush := func() {
+		if text.Len() > 0 {
+			nodes = append(nodes, textNode(text.String()))
+			text.Reset()
+		}
+	}
+
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		switch {
+		case c == '\'':
+			p.quoted(&text, inPlural)
+		case c == '{':
+			flush()
+			p.pos++
+			node, err := p.argument(inPlural)
+			if err != nil {
+				return nil, err
+			}
+			nodes = append(nodes, node)
+		case c == '}':
+			if !nested {
+				return nil, errors.Errorf("unexpected \"}\" at position %d", p.pos)
+			}
+			flush()
+			p.pos++
+			return nodes, nil
+		case c == '#' && inPlural:
+			flush()
+			nodes = append(nodes, hashNode{})
+			p.pos++
+		default:
+			text.WriteRune(c)
+			p.pos++
+		}
+	}
+
+	if nested {
+		return nil, errors.New("unclosed \"{\"")
+	}
+	flush()
+	return nodes, nil
+}
+
+// quoted handles apostrophes: a doubled apostrophe is a literal apostrophe, and an apostrophe
+// followed by a special character starts a quoted literal text, ending with the
+// next single apostrophe. Other apostrophes are literal.
+func (p *messageParser) quoted(text *strings.Builder, inPlural bool) {
+	next := p.peek(1)
+	if next == '\'' {
+		text.WriteRune('\'')
+		p.pos += 2
+		return
+	}
+	if next != '{' && next != '}' && (next != '#' || !inPlural) {
+		text.WriteRune('\'')
+		p.pos++
+		return
+	}
+
+	p.pos++
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		if c == '\'' {
+			if p.peek(1) == '\'' {
+				text.WriteRune('\'')
+				p.pos += 2
+				continue
+			}
+			p.pos++
+			return
+		}
+		text.WriteRune(c)
+		p.pos++
+	}
+}
+
+// argument parses an argument, after its opening brace.
+func (p *messageParser) argument(inPlural bool) (messageNode, error) {
+	p.skipSpaces()
+	name := p.token(",}")
+	if name == "" {
+		return nil, errors.Errorf("missing argument name at position %d", p.pos)
+	}
+	p.skipSpaces()
+	if p.peek(0) == '}' {
+		p.pos++
+		return argumentNode{name: name}, nil
+	}
+	if p.peek(0) != ',' {
+		return nil, errors.Errorf("expected \",\" or \"}\" after argument %q", name)
+	}
+	p.pos++
+	p.skipSpaces()
+	typ := p.token(",}")
+	p.skipSpaces()
+
+	switch typ {
+	case "plural", "select":
+		if p.peek(0) != ',' {
+			return nil, errors.Errorf("expected \",\" after %q in argument %q", typ, name)
+		}
+		p.pos++
+		return p.cases(name, typ, inPlural)
+	case "number", "date", "time":
+		style := ""
+		if p.peek(0) == ',' {
+			p.pos++
+			start := p.pos
+			for p.pos < len(p.src) && p.src[p.pos] != '}' {
+				p.pos++
+			}
+			style = strings.TrimSpace(string(p.src[start:p.pos]))
+		}
+		if p.peek(0) != '}' {
+			return nil, errors.Errorf("unclosed argument %q", name)
+		}
+		p.pos++
+		return argumentNode{name: name, typ: typ, style: style}, nil
+	default:
+		return nil, errors.Errorf("unsupported argument type %q in argument %q", typ, name)
+	}
+}
+
+// cases parses the cases of a plural or select argument, until its closing brace.
+func (p *messageParser) cases(name, typ string, inPlural bool) (messageNode, error) {
+	cases := map[string][]messageNode{}
+	offset := 0.0
+	for {
+		p.skipSpaces()
+		if p.pos >= len(p.src) {
+			return nil, errors.Errorf("unclosed argument %q", name)
+		}
+		if p.peek(0) == '}' {
+			p.pos++
+			break
+		}
+
+		selector := p.token("{}")
+		if typ == "plural" && strings.HasPrefix(selector, "offset:") {
+			if len(cases) > 0 {
+				return nil, errors.Errorf("offset must be defined before the cases in argument %q", name)
+			}
+			o, err := strconv.ParseFloat(selector[7:], 64)
+			if err != nil {
+				return nil, errors.Errorf("invalid offset %q in argument %q", selector[7:], name)
+			}
+			offset = o
+			continue
+		}
+		if selector == "" {
+			return nil, errors.Errorf("missing selector at position %d in argument %q", p.pos, name)
+		}
+		p.skipSpaces()
+		if p.peek(0) != '{' {
+			return nil, errors.Errorf("expected \"{\" after selector %q in argument %q", selector, name)
+		}
+		p.pos++
+		nodes, err := p.parse(inPlural || typ == "plural", true)
+		if err != nil {
+			return nil, err
+		}
+		cases[selector] = nodes
+	}
+
+	if _, ok := cases[PluralOther]; !ok {
+		return nil, errors.Errorf("missing \"other\" case in argument %q", name)
+	}
+	if typ == "plural" {
+		return pluralNode{name: name, offset: offset, cases: cases}, nil
+	}
+	return selectNode{name: name, cases: cases}, nil
+}
+
+// Format a language line using the ICU MessageFormat syntax. The line is identified the
+// same way as with `Get()`. If not found, the "line" argument is used as the message.
+//
+// Supported arguments:
+//   - simple: "{name}"
+//   - number: "{n, number}", "{n, number, integer}", "{n, number, percent}"
+//   - date and time: "{d, date, short}", "{d, time, medium}" (styles: short, medium, long)
+//   - plural: "{count, plural, =0 {no item} one {# item} other {# items}}". The "#" symbol
+//     is replaced with the formatted number. An offset can be defined before the cases
+//     ("offset:1"). The category is selected using the CLDR plural rules of this language.
+//   - select: "{gender, select, female {her} male {his} other {their}}"
+//
+// Numbers and dates are formatted using the conventions of this language (see `Locale()`).
+// Special characters can be escaped with apostrophes ("'{'"). Use a doubled apostrophe
+// for a literal apostrophe.
+// Parsed messages are cached. If the message is invalid, the raw message is returned.
+//
+//	lang.Format("cart.items", map[string]any{"count": 3}) // "You have 3 items in your cart."
+func (l *Language) Format(line string, args map[string]any) string {
+	message := l.lookup(line)
+	if message == "" {
+		message = line
+	}
+	nodes, err := parseMessage(message)
+	if err != nil {
+		return message
+	}
+	b := &strings.Builder{}
+	formatNodes(b, nodes, &formatContext{language: l, args: args})
+	return b.String()
+}
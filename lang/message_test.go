@@ -0,0 +1,143 @@
+package lang
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormat(t *testing.T) {
+	en := &Language{name: "en-US"}
+	fr := &Language{name: "fr-FR"}
+	ru := &Language{name: "ru-RU"}
+
+	items := "{count, plural, =0 {No items} one {# item} other {# items}}"
+	date := time.Date(2024, time.March, 7, 14, 5, 9, 0, time.UTC)
+
+	cases := []struct {
+		language *Language
+		args     map[string]any
+		message  string
+		want     string
+	}{
+		{language: en, message: "Hello {name}!", args: map[string]any{"name": "Alice"}, want: "Hello Alice!"},
+		{language: en, message: "Hello {name}!", args: nil, want: "Hello {name}!"},
+		{language: en, message: "Hello { name }!", args: map[string]any{"name": "Alice"}, want: "Hello Alice!"},
+		{language: en, message: items, args: map[string]any{"count": 0}, want: "No items"},
+		{language: en, message: items, args: map[string]any{"count": 1}, want: "1 item"},
+		{language: en, message: items, args: map[string]any{"count": 1234}, want: "1,234 items"},
+		{language: en, message: items, args: map[string]any{"count": "1.0"}, want: "1 items"},
+		{language: en, message: items, args: map[string]any{}, want: " items"},
+		{language: fr, message: "{count, plural, one {# article} other {# articles}}", args: map[string]any{"count": 1.5}, want: "1,5 article"},
+		{language: fr, message: "{count, plural, one {# article} other {# articles}}", args: map[string]any{"count": 1000000}, want: "1\u00a0000\u00a0000 articles"},
+		{language: ru, message: "{n, plural, one {# файл} few {# файла} many {# файлов} other {# файла}}", args: map[string]any{"n": 3}, want: "3 файла"},
+		{language: ru, message: "{n, plural, one {# файл} few {# файла} many {# файлов} other {# файла}}", args: map[string]any{"n": 11}, want: "11 файлов"},
+		{
+			language: en,
+			message:  "{count, plural, offset:1 =0 {Nobody} =1 {{name}} one {{name} and # other} other {{name} and # others}}",
+			args:     map[string]any{"count": 3, "name": "Alice"},
+			want:     "Alice and 2 others",
+		},
+		{
+			language: en,
+			message:  "{count, plural, offset:1 =0 {Nobody} =1 {{name}} one {{name} and # other} other {{name} and # others}}",
+			args:     map[string]any{"count": 2, "name": "Alice"},
+			want:     "Alice and 1 other",
+		},
+		{language: en, message: "{gender, select, female {She} male {He} other {They}} liked it", args: map[string]any{"gender": "female"}, want: "She liked it"},
+		{language: en, message: "{gender, select, female {She} male {He} other {They}} liked it", args: map[string]any{"gender": "x"}, want: "They liked it"},
+		{
+			language: en,
+			message:  "{gender, select, female {{n, plural, one {She has # cat} other {She has # cats}}} other {{n, plural, one {They have # cat} other {They have # cats}}}}",
+			args:     map[string]any{"gender": "female", "n": 2},
+			want:     "She has 2 cats",
+		},
+		{language: en, message: "{n, plural, other {# {n, plural, one {#} other {# and #}}}}", args: map[string]any{"n": 2}, want: "2 2 and 2"},
+		{language: fr, message: "Total : {amount, number}", args: map[string]any{"amount": 1234.5}, want: "Total : 1\u00a0234,5"},
+		{language: en, message: "{amount, number, integer}", args: map[string]any{"amount": 1234.6}, want: "1,235"},
+		{language: en, message: "{ratio, number, percent}", args: map[string]any{"ratio": 0.5}, want: "50%"},
+		{language: en, message: "{amount}", args: map[string]any{"amount": 1234}, want: "1,234"},
+		{language: en, message: "{n, number}", args: map[string]any{"n": "3"}, want: "3"},
+		{language: en, message: "{n, number}", args: map[string]any{"n": "1234.5"}, want: "1,234.5"},
+		{language: en, message: "{n, number, integer}", args: map[string]any{"n": "abc"}, want: "abc"},
+		{language: en, message: "{value}", args: map[string]any{"value": true}, want: "true"},
+		{language: fr, message: "Le {d, date, long} à {d, time, short}", args: map[string]any{"d": date}, want: "Le 7 mars 2024 à 14:05"},
+		{language: en, message: "{d, date}", args: map[string]any{"d": date}, want: "Mar 7, 2024"},
+		{language: en, message: "{d, date, full}", args: map[string]any{"d": date}, want: "March 7, 2024"},
+		{language: en, message: "{d, date, short}", args: map[string]any{"d": "not a date"}, want: "not a date"},
+		{language: en, message: "{d}", args: map[string]any{"d": date}, want: "3/7/24 2:05 PM"},
+		{language: en, message: "It's '{'name'}' and '#'", args: map[string]any{"name": "Alice"}, want: "It's {name} and '#'"},
+		{language: en, message: "{n, plural, other {'#' is #}}", args: map[string]any{"n": 5}, want: "# is 5"},
+		{language: en, message: "Don''t", args: nil, want: "Don't"},
+		{language: en, message: "'{quoted ''text''}'", args: nil, want: "{quoted 'text'}"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.message, func(t *testing.T) {
+			assert.Equal(t, c.want, c.language.Format(c.message, c.args))
+		})
+	}
+}
+
+func TestFormatInvalid(t *testing.T) {
+	cases := []struct {
+		message string
+		err     string
+	}{
+		{message: "Hello {name", err: "invalid message \"Hello {name\": expected \",\" or \"}\" after argument \"name\""},
+		{message: "Hello }", err: "invalid message \"Hello }\": unexpected \"}\" at position 6"},
+		{message: "{}", err: "invalid message \"{}\": missing argument name at position 1"},
+		{message: "{n, plural, one {#}}", err: "invalid message \"{n, plural, one {#}}\": missing \"other\" case in argument \"n\""},
+		{message: "{n, plural, other {#}", err: "invalid message \"{n, plural, other {#}\": unclosed argument \"n\""},
+		{message: "{n, plural, other {#", err: "invalid message \"{n, plural, other {#\": unclosed \"{\""},
+		{message: "{n, plural, other #}", err: "invalid message \"{n, plural, other #}\": expected \"{\" after selector \"other\" in argument \"n\""},
+		{message: "{n, plural, offset:a other {#}}", err: "invalid message \"{n, plural, offset:a other {#}}\": invalid offset \"a\" in argument \"n\""},
+		{message: "{n, plural, other {#} offset:1}", err: "invalid message \"{n, plural, other {#} offset:1}\": offset must be defined before the cases in argument \"n\""},
+		{message: "{n, plural}", err: "invalid message \"{n, plural}\": expected \",\" after \"plural\" in argument \"n\""},
+		{message: "{n, spellout}", err: "invalid message \"{n, spellout}\": unsupported argument type \"spellout\" in argument \"n\""},
+		{message: "{n, number, integer", err: "invalid message \"{n, number, integer\": unclosed argument \"n\""},
+	}
+
+	for _, c := range cases {
+		t.Run(c.message, func(t *testing.T) {
+			_, err := parseMessage(c.message)
+			require.Error(t, err)
+			assert.Equal(t, c.err, err.Error())
+			assert.Equal(t, c.message, (&Language{name: "en-US"}).Format(c.message, map[string]any{"n": 1}))
+		})
+	}
+}
+
+func TestFormatLines(t *testing.T) {
+	l := &Language{
+		name:  "en-US",
+		lines: map[string]string{"cart.items": "You have {count, plural, one {# item} other {# items}} in your cart."},
+		validation: validationLines{
+			rules:  map[string]string{"max": "The {field} must have at most {max, plural, one {# item} other {# items}}."},
+			fields: map[string]string{"email": "{gender, select, other {email address}}"},
+		},
+	}
+
+	assert.Equal(t, "You have 3 items in your cart.", l.Format("cart.items", map[string]any{"count": 3}))
+	assert.Equal(t, "The tags must have at most 1 item.", l.Format("validation.rules.max", map[string]any{"field": "tags", "max": 1}))
+	assert.Equal(t, "email address", l.Format("validation.fields.email", nil))
+	assert.Equal(t, "undefined 1", l.Format("undefined {n}", map[string]any{"n": 1}))
+
+	languages := &Languages{languages: map[string]*Language{"en-US": l}, Default: "en-US"}
+	assert.Equal(t, "You have 1 item in your cart.", languages.Format("en-US", "cart.items", map[string]any{"count": 1}))
+	assert.Equal(t, "cart.items", languages.Format("fr-FR", "cart.items", map[string]any{"count": 1}))
+}
+
+func TestParseMessageCache(t *testing.T) {
+	message := "{n, plural, other {# cached}}"
+	first, err := parseMessage(message)
+	require.NoError(t, err)
+	cached, ok := messageCache.Load(message)
+	require.True(t, ok)
+	assert.Equal(t, first, cached.(*parsedMessage).nodes)
+	second, err := parseMessage(message)
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+}
@@ -0,0 +1,164 @@
+package lang
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/text/feature/plural"
+)
+
+// CLDR plural categories.
+const (
+	PluralZero  = "zero"
+	PluralOne   = "one"
+	PluralTwo   = "two"
+	PluralFew   = "few"
+	PluralMany  = "many"
+	PluralOther = "other"
+)
+
+// PluralOperands the operands of a number used by the CLDR plural rules.
+// See https://unicode.org/reports/tr35/tr35-numbers.html#Operands
+type PluralOperands struct {
+	// N the absolute value of the number.
+	N float64
+
+	// I the integer digits of N.
+	I int64
+
+	// F the visible fraction digits of N, with trailing zeros.
+	F int64
+
+	// T the visible fraction digits of N, without trailing zeros.
+	T int64
+
+	// V the number of visible fraction digits of N, with trailing zeros.
+	V int
+
+	// W the number of visible fraction digits of N, without trailing zeros.
+	W int
+}
+
+// NewPluralOperands returns the plural operands of the given number. Numbers can be of
+// any integer or float type, or strings representing a number (which allows to keep trailing zeros
+// in the visible fraction digits: "1.50"). Returns the operands of zero for any other value.
+func NewPluralOperands(number any) PluralOperands {
+	str := ""
+	switch n := number.(type) {
+	case string:
+		str = strings.TrimSpace(n)
+	case float64:
+		str = strconv.FormatFloat(n, 'f', -1, 64)
+	case float32:
+		str = strconv.FormatFloat(float64(n), 'f', -1, 32)
+	default:
+		if i, ok := toInt64(number); ok {
+			str = strconv.FormatInt(i, 10)
+		}
+	}
+	str = strings.TrimPrefix(str, "-")
+
+	ops := PluralOperands{}
+	n, err := strconv.ParseFloat(str, 64)
+	if err != nil || math.IsInf(n, 0) || math.IsNaN(n) {
+		return ops
+	}
+	ops.N = n
+	integer, fraction, _ := strings.Cut(str, ".")
+	ops.I, _ = strconv.ParseInt(integer, 10, 64)
+	ops.V = len(fraction)
+	if fraction != "" {
+		ops.F, _ = strconv.ParseInt(fraction, 10, 64)
+		if trimmed := strings.TrimRight(fraction, "0"); trimmed != "" {
+			ops.T, _ = strconv.ParseInt(trimmed, 10, 64)
+			ops.W = len(trimmed)
+		}
+	}
+	return ops
+}
+
+// PluralRule returns the plural category (`PluralOne`, `PluralOther`, etc) of a number.
+type PluralRule func(ops PluralOperands) string
+
+var (
+	pluralRulesMu sync.RWMutex
+	pluralRules   = map[string]PluralRule{}
+)
+
+// pluralForms the name of the CLDR plural categories returned by the rules of `golang.org/x/text`.
+var pluralForms = map[plural.Form]string{
+	plural.Zero:  PluralZero,
+	plural.One:   PluralOne,
+	plural.Two:   PluralTwo,
+	plural.Few:   PluralFew,
+	plural.Many:  PluralMany,
+	plural.Other: PluralOther,
+}
+
+// RegisterPluralRule registers the plural rule of the given language, overriding the CLDR rule
+// provided by `golang.org/x/text`. The language can either be a base language ("pt"), used for
+// all its variants, or a variant ("pt-PT"), which has priority over its base language. Replaces
+// the existing rule, if any.
+func RegisterPluralRule(language string, rule PluralRule) {
+	pluralRulesMu.Lock()
+	defer pluralRulesMu.Unlock()
+	pluralRules[language] = rule
+}
+
+// pluralRuleFor returns the plural rule registered for the given language or its base
+// language, or the CLDR rule of the language if none are registered.
+func pluralRuleFor(name string) PluralRule {
+	pluralRulesMu.RLock()
+	defer pluralRulesMu.RUnlock()
+	if rule, ok := pluralRules[name]; ok {
+		return rule
+	}
+	if rule, ok := pluralRules[baseLanguage(name)]; ok {
+		return rule
+	}
+	tag := languageTag(name)
+	return func(ops PluralOperands) string {
+		return pluralForms[plural.Cardinal.MatchPlural(tag, int(ops.I), ops.V, ops.W, int(ops.F), int(ops.T))]
+	}
+}
+
+// baseLanguage returns the language subtag of the given language tag ("en-US" -> "en").
+func baseLanguage(language string) string {
+	base, _, _ := strings.Cut(language, "-")
+	return strings.ToLower(base)
+}
+
+// PluralCategory returns the CLDR plural category of the given number in this language.
+// See `NewPluralOperands()` for the accepted number types.
+func (l *Language) PluralCategory(number any) string {
+	return pluralRuleFor(l.name)(NewPluralOperands(number))
+}
+
+func toInt64(value any) (int64, bool) {
+	switch v := value.(type) {
+	case int:
+		return int64(v), true
+	case int8:
+		return int64(v), true
+	case int16:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	case uint:
+		return int64(v), true
+	case uint8:
+		return int64(v), true
+	case uint16:
+		return int64(v), true
+	case uint32:
+		return int64(v), true
+	case uint64:
+		return int64(v), true
+	default:
+		return 0, false
+	}
+}
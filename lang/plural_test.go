@@ -0,0 +1,71 @@
+package lang
+
+import (
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPluralOperands(t *testing.T) {
+	cases := []struct {
+		number any
+		want   PluralOperands
+	}{
+		{number: 1, want: PluralOperands{N: 1, I: 1}},
+		{number: int64(-12), want: PluralOperands{N: 12, I: 12}},
+		{number: uint8(3), want: PluralOperands{N: 3, I: 3}},
+		{number: 1.5, want: PluralOperands{N: 1.5, I: 1, F: 5, T: 5, V: 1, W: 1}},
+		{number: float32(0.25), want: PluralOperands{N: 0.25, I: 0, F: 25, T: 25, V: 2, W: 2}},
+		{number: "1.50", want: PluralOperands{N: 1.5, I: 1, F: 50, T: 5, V: 2, W: 1}},
+		{number: "1.0", want: PluralOperands{N: 1, I: 1, F: 0, T: 0, V: 1}},
+		{number: "abc", want: PluralOperands{}},
+		{number: []string{}, want: PluralOperands{}},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.want, NewPluralOperands(c.number), "%v", c.number)
+	}
+}
+
+func TestPluralCategory(t *testing.T) {
+	cases := []struct {
+		language string
+		want     map[any]string
+	}{
+		{language: "en-US", want: map[any]string{0: PluralOther, 1: PluralOne, 2: PluralOther, "1.0": PluralOther, 1.5: PluralOther}},
+		{language: "fr-FR", want: map[any]string{0: PluralOne, 1: PluralOne, 1.5: PluralOne, 2: PluralOther, 1000000: PluralOther}},
+		{language: "es", want: map[any]string{1: PluralOne, "1.0": PluralOne, 2: PluralOther, 1000000: PluralOther}},
+		{language: "it", want: map[any]string{1: PluralOne, 2: PluralOther, 1000000: PluralOther}},
+		{language: "ru", want: map[any]string{1: PluralOne, 21: PluralOne, 11: PluralMany, 2: PluralFew, 24: PluralFew, 12: PluralMany, 5: PluralMany, 1.5: PluralOther}},
+		{language: "pl", want: map[any]string{1: PluralOne, 21: PluralMany, 2: PluralFew, 22: PluralFew, 14: PluralMany, 0.5: PluralOther}},
+		{language: "cs", want: map[any]string{1: PluralOne, 3: PluralFew, 5: PluralOther, 1.5: PluralMany}},
+		{language: "ar", want: map[any]string{0: PluralZero, 1: PluralOne, 2: PluralTwo, 3: PluralFew, 110: PluralFew, 111: PluralMany, 100: PluralOther, 0.5: PluralOther}},
+		{language: "ja", want: map[any]string{0: PluralOther, 1: PluralOther}},
+		{language: "xx-YY", want: map[any]string{1: PluralOne, 2: PluralOther}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.language, func(t *testing.T) {
+			l := &Language{name: c.language}
+			for number, want := range c.want {
+				assert.Equal(t, want, l.PluralCategory(number), "%v", number)
+			}
+		})
+	}
+}
+
+func TestRegisterPluralRule(t *testing.T) {
+	t.Cleanup(func() {
+		pluralRulesMu.Lock()
+		delete(pluralRules, "pt-PT")
+		pluralRulesMu.Unlock()
+	})
+
+	RegisterPluralRule("pt-PT", func(ops PluralOperands) string {
+		return lo.Ternary(ops.I == 1 && ops.V == 0, PluralOne, PluralOther)
+	})
+
+	assert.Equal(t, PluralOther, (&Language{name: "pt-PT"}).PluralCategory(0))
+	assert.Equal(t, PluralOne, (&Language{name: "pt-BR"}).PluralCategory(0))
+}
@@ -0,0 +1,142 @@
+package goyave
+
+import (
+	"net/http"
+	"time"
+)
+
+// LanguageParameter the name of the route parameter holding the language in
+// URLs prefixed with the language, such as "/fr/products".
+//
+//	localized := router.Subrouter("/{lang}")
+//	localized.Get("/products", handler)
+const LanguageParameter = "lang"
+
+// LanguageResolver provides the language requested by the client, from a request header,
+// a cookie, the URL, or the authenticated user for example. See `LanguageMiddleware`.
+type LanguageResolver interface {
+	// ResolveLanguage returns the language requested by the client. The returned
+	// string can use the HTTP "Accept-Language" header format. Returns an empty string
+	// if the request doesn't contain any language information.
+	ResolveLanguage(request *Request) string
+}
+
+// LanguagePreferrer is implemented by users that have a saved language preference.
+// See `UserLanguage`.
+type LanguagePreferrer interface {
+	// PreferredLanguage returns the preferred language of the user, or an empty string
+	// if the user has no preference.
+	PreferredLanguage() string
+}
+
+// varyingLanguageResolver a resolver reading a request header, which must be
+// added to the "Vary" response header.
+type varyingLanguageResolver interface {
+	varyHeader() string
+}
+
+// languagePersister a resolver that can remember the resolved language for
+// the next requests.
+type languagePersister interface {
+	persist(response *Response, request *Request, language string)
+}
+
+// HeaderLanguage resolves the language from the "Accept-Language" header.
+type HeaderLanguage struct{}
+
+// ResolveLanguage returns the value of the "Accept-Language" header.
+func (HeaderLanguage) ResolveLanguage(request *Request) string {
+	return request.Header().Get("Accept-Language")
+}
+
+func (HeaderLanguage) varyHeader() string {
+	return "Accept-Language"
+}
+
+// RouteLanguage resolves the language from the route parameter named `LanguageParameter`.
+// Use this resolver for URLs prefixed with the language ("/fr/products").
+type RouteLanguage struct{}
+
+// ResolveLanguage returns the value of the route parameter named `LanguageParameter`.
+func (RouteLanguage) ResolveLanguage(request *Request) string {
+	return request.RouteParams[LanguageParameter]
+}
+
+// QueryLanguage resolves the language from a query parameter ("?lang=fr").
+type QueryLanguage struct {
+	// Name the name of the query parameter. Defaults to "lang".
+	Name string
+}
+
+// ResolveLanguage returns the value of the query parameter.
+func (r QueryLanguage) ResolveLanguage(request *Request) string {
+	return request.URL().Query().Get(defaultName(r.Name))
+}
+
+// CookieLanguage resolves the language from a cookie.
+type CookieLanguage struct {
+	// Name the name of the cookie. Defaults to "lang".
+	Name string
+
+	// MaxAge the lifetime of the cookie set when `Persist` is true.
+	// If zero, the cookie is a session cookie.
+	MaxAge time.Duration
+
+	// Persist if true, the cookie is set on the response when the language is resolved by
+	// a resolver placed before this one in the chain and is different from the value of the cookie.
+	// This way, the language chosen with a URL prefix or a query parameter for example is
+	// remembered for the next requests.
+	Persist bool
+}
+
+// ResolveLanguage returns the value of the cookie.
+func (r CookieLanguage) ResolveLanguage(request *Request) string {
+	cookie, err := request.Request().Cookie(defaultName(r.Name))
+	if err != nil {
+		return ""
+	}
+	return cookie.Value
+}
+
+func (CookieLanguage) varyHeader() string {
+	return "Cookie"
+}
+
+func (r CookieLanguage) persist(response *Response, request *Request, language string) {
+	if !r.Persist || r.ResolveLanguage(request) == language {
+		return
+	}
+	cookie := &http.Cookie{
+		Name:     defaultName(r.Name),
+		Value:    language,
+		Path:     "/",
+		SameSite: http.SameSiteLaxMode,
+	}
+	if r.MaxAge > 0 {
+		cookie.MaxAge = int(r.MaxAge.Seconds())
+	}
+	response.Cookie(cookie)
+}
+
+// UserLanguage resolves the language from the preference of the authenticated user,
+// if `request.User` implements `LanguagePreferrer`.
+//
+// The user is only available after the authentication middleware has been executed.
+// Therefore, this resolver cannot be used in the global language middleware. Add a
+// `LanguageMiddleware` after the authentication middleware instead.
+type UserLanguage struct{}
+
+// ResolveLanguage returns the preferred language of the authenticated user.
+func (UserLanguage) ResolveLanguage(request *Request) string {
+	if user, ok := request.User.(LanguagePreferrer); ok {
+		return user.PreferredLanguage()
+	}
+	return ""
+}
+
+func defaultName(name string) string {
+	if name == "" {
+		return LanguageParameter
+	}
+	return name
+}
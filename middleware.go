@@ -92,33 +92,82 @@ func (m *recoveryMiddleware) Handle(next Handler) Handler {
 	}
 }
 
-// languageMiddleware is a middleware that sets the language of a request.
-//
-// Uses the "Accept-Language" header to determine which language to use. If
-// the header is not set or the language is not available, uses the default
-// language as fallback.
+// LanguageMiddleware is a middleware that sets the language of a request (`request.Lang`).
 //
+// The resolvers are tried in order: the first one returning an available language
+// wins (see `lang.Languages.Match()`). If none of them do, the default language is used.
 // If "*" is provided, the default language will be used.
-// If multiple languages are given, the first available language will be used,
-// and if none are available, the default language will be used.
-// If no variant is given (for example "en"), the first available variant will be used.
-// For example, if "en-US" and "en-UK" are available and the request accepts "en",
-// "en-US" will be used.
-type languageMiddleware struct {
+//
+// This middleware is applied globally by default, using the "Accept-Language" header only.
+// Use `Router.LanguageResolvers()` to configure it:
+//
+//	router.LanguageResolvers(
+//		goyave.QueryLanguage{},
+//		goyave.RouteLanguage{},
+//		goyave.CookieLanguage{Persist: true, MaxAge: 365 * 24 * time.Hour},
+//		goyave.HeaderLanguage{},
+//	)
+//
+// The "Content-Language" response header is set to the name of the language, and the "Vary"
+// response header is completed with the request headers used by the resolvers.
+//
+// This middleware can also be added after the authentication middleware so the preference
+// of the authenticated user (see `UserLanguage`) takes priority.
+type LanguageMiddleware struct {
 	Component
+
+	// Resolvers the chain of resolvers. If empty, only the "Accept-Language" header is used.
+	Resolvers []LanguageResolver
 }
 
-func (m *languageMiddleware) Handle(next Handler) Handler {
+// Handle sets the language of the request and the "Content-Language" and "Vary" response headers.
+func (m *LanguageMiddleware) Handle(next Handler) Handler {
+	resolvers := m.Resolvers
+	if len(resolvers) == 0 {
+		resolvers = []LanguageResolver{HeaderLanguage{}}
+	}
 	return func(response *Response, request *Request) {
-		if header := request.Header().Get("Accept-Language"); len(header) > 0 {
-			request.Lang = m.Lang().DetectLanguage(header)
-		} else {
+		request.Lang = nil
+		resolvedBy := len(resolvers)
+		for i, resolver := range resolvers {
+			if value := resolver.ResolveLanguage(request); value != "" {
+				if request.Lang = m.Lang().Match(value); request.Lang != nil {
+					resolvedBy = i
+					break
+				}
+			}
+		}
+		if request.Lang == nil {
 			request.Lang = m.Lang().GetDefault()
 		}
+
+		headers := response.Header()
+		headers.Set("Content-Language", request.Lang.Name())
+		for i, resolver := range resolvers {
+			if r, ok := resolver.(varyingLanguageResolver); ok {
+				addVary(headers, r.varyHeader())
+			}
+			if r, ok := resolver.(languagePersister); ok && i > resolvedBy {
+				// The language was resolved by a resolver with higher priority
+				r.persist(response, request, request.Lang.Name())
+			}
+		}
 		next(response, request)
 	}
 }
 
+// addVary adds the given header to the "Vary" header if not already present.
+func addVary(headers http.Header, header string) {
+	for _, value := range headers.Values("Vary") {
+		for _, v := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(v), header) {
+				return
+			}
+		}
+	}
+	headers.Add("Vary", header)
+}
+
 // validateRequestMiddleware is a middleware that validates the request.
 // If validation is not rules are not met, sets the response status to 422 Unprocessable Entity
 // or 400 Bad Request and the response error (which can be retrieved with `GetError()`) to the
@@ -134,7 +183,8 @@ type validateRequestMiddleware struct {
 func (m *validateRequestMiddleware) Handle(next Handler) Handler {
 	return func(response *Response, r *Request) {
 		extra := map[any]any{
-			validation.ExtraRequest{}: r,
+			validation.ExtraRequest{}:     r,
+			validation.ExtraRouteParams{}: r.RouteParams,
 		}
 		contentType := r.Header().Get("Content-Type")
 
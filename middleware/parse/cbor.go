@@ -0,0 +1,42 @@
+package parse
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/samber/lo"
+)
+
+// cborDecMode the CBOR decoding options. The size of arrays and maps is only limited
+// by the maximum size of the request body.
+var cborDecMode = lo.Must(cbor.DecOptions{
+	MaxNestedLevels:       maxDepth,
+	MaxArrayElements:      math.MaxInt32,
+	MaxMapPairs:           math.MaxInt32,
+	MapKeyByteString:      cbor.MapKeyByteStringAllowed,
+	UnrecognizedTagToAny:  cbor.UnrecognizedTagContentToAny,
+	DefaultByteStringType: reflect.TypeOf(""),
+}.DecMode())
+
+// decodeCBOR decodes a CBOR document. Integers are decoded as `float64`, byte strings as
+// `string` and standard date/time tags (0 and 1) as `time.Time`. Other tags are ignored and their
+// content is decoded. Map keys that are not strings are formatted with `fmt.Sprint()`.
+// "undefined" is decoded as `nil`.
+func decodeCBOR(body []byte) (any, error) {
+	var value any
+	err := cborDecMode.Unmarshal(body, &value)
+	var maxDepthErr *cbor.MaxNestedLevelError
+	switch {
+	case err == io.EOF:
+		return nil, io.ErrUnexpectedEOF
+	case errors.As(err, &maxDepthErr):
+		return nil, fmt.Errorf("%w: %w", errMaxDepth, err)
+	case err != nil:
+		return nil, err
+	}
+	return toJSONValue(value)
+}
@@ -0,0 +1,95 @@
+package parse
+
+import (
+	"encoding/hex"
+	"io"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeCBOR(t *testing.T) {
+	cases := []struct {
+		expected any
+		hex      string
+		desc     string
+	}{
+		{
+			desc:     "object",
+			hex:      "a461610161628" + "3f5f661786163206164f93e00",
+			expected: map[string]any{"a": 1.0, "b": []any{true, nil, "x"}, "c": -1.0, "d": 1.5},
+		},
+		{desc: "uint8", hex: "1818", expected: 24.0},
+		{desc: "uint16", hex: "190100", expected: 256.0},
+		{desc: "uint32", hex: "1a00010000", expected: 65536.0},
+		{desc: "uint64", hex: "1b0000000000000001", expected: 1.0},
+		{desc: "negative", hex: "38ff", expected: -256.0},
+		{desc: "float32", hex: "fa3fc00000", expected: 1.5},
+		{desc: "float64", hex: "fb3ff8000000000000", expected: 1.5},
+		{desc: "half_subnormal", hex: "f90001", expected: math.Ldexp(1, -24)},
+		{desc: "half_negative", hex: "f9c000", expected: -2.0},
+		{desc: "half_infinity", hex: "f97c00", expected: math.Inf(1)},
+		{desc: "false", hex: "f4", expected: false},
+		{desc: "undefined", hex: "f7", expected: nil},
+		{desc: "bytes", hex: "426869", expected: "hi"},
+		{desc: "indefinite_string", hex: "7f62686961" + "21ff", expected: "hi!"},
+		{desc: "indefinite_array", hex: "9f0102ff", expected: []any{1.0, 2.0}},
+		{desc: "indefinite_map", hex: "bf616b01ff", expected: map[string]any{"k": 1.0}},
+		{desc: "non_string_key", hex: "a10102", expected: map[string]any{"1": 2.0}},
+		{desc: "byte_string_key", hex: "a1416b01", expected: map[string]any{"k": 1.0}},
+		{desc: "bignum", hex: "c249010000000000000000", expected: math.Pow(2, 64)},
+		{desc: "epoch", hex: "c11a00000001", expected: time.Unix(1, 0).UTC()},
+		{desc: "epoch_float", hex: "c1fb3ff8000000000000", expected: time.Unix(1, 5e8).UTC()},
+		{desc: "date_time", hex: "c074" + hex.EncodeToString([]byte("2013-03-21T20:04:00Z")), expected: time.Date(2013, 3, 21, 20, 4, 0, 0, time.UTC)},
+		{desc: "other_tag", hex: "d82063616263", expected: "abc"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			body, err := hex.DecodeString(c.hex)
+			require.NoError(t, err)
+			value, err := decodeCBOR(body)
+			require.NoError(t, err)
+			assert.Equal(t, c.expected, value)
+		})
+	}
+}
+
+func TestDecodeCBORErrors(t *testing.T) {
+	cases := []struct {
+		expected error
+		hex      string
+		desc     string
+	}{
+		{desc: "empty", hex: "", expected: io.ErrUnexpectedEOF},
+		{desc: "unexpected_break", hex: "ff"},
+		{desc: "break_in_definite_array", hex: "8201ff"},
+		{desc: "break_in_nested_definite_array", hex: "9f8201ff"},
+		{desc: "break_as_map_value", hex: "bf6161ff"},
+		{desc: "reserved_information", hex: "1c"},
+		{desc: "truncated_array", hex: "8201", expected: io.ErrUnexpectedEOF},
+		{desc: "huge_length", hex: "5affffffff", expected: io.ErrUnexpectedEOF},
+		{desc: "trailing_data", hex: "0101"},
+		{desc: "invalid_chunk", hex: "7f01ff"},
+		{desc: "unsupported_simple_value", hex: "f820"},
+		{desc: "invalid_epoch", hex: "c16161"},
+		{desc: "invalid_date_time", hex: "c001"},
+		{desc: "max_depth", hex: strings.Repeat("81", maxDepth+2) + "01", expected: errMaxDepth},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			body, err := hex.DecodeString(c.hex)
+			require.NoError(t, err)
+			_, err = decodeCBOR(body)
+			require.Error(t, err)
+			if c.expected != nil {
+				assert.ErrorIs(t, err, c.expected)
+			}
+		})
+	}
+}
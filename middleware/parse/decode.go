@@ -0,0 +1,226 @@
+package parse
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"mime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Decoder decodes a request body. The returned value must only contain values of the
+// types produced by the standard JSON decoder (`map[string]any`, `[]any`, `float64`, `string`, `bool`
+// and `nil`) so the validation of the request is identical regardless of the format. `time.Time`
+// is also accepted for formats having a native date type.
+//
+// A `nil` decoder means the body is raw and must not be read by the middleware.
+type Decoder func(body []byte) (any, error)
+
+// maxDepth the maximum nesting depth of the decoded bodies.
+const maxDepth = 10000
+
+var errMaxDepth = errors.New("exceeded max depth")
+
+// toJSONValue converts a value decoded by a binary format library to the types
+// produced by the standard JSON decoder (see `Decoder`).
+func toJSONValue(value any) (any, error) {
+	switch v := value.(type) {
+	case nil, bool, string, float64:
+		return v, nil
+	case float32:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case uint64:
+		return float64(v), nil
+	case big.Int:
+		f, _ := new(big.Float).SetInt(&v).Float64()
+		return f, nil
+	case []byte:
+		return string(v), nil
+	case time.Time:
+		return v.UTC(), nil
+	case []any:
+		array := make([]any, len(v))
+		for i, e := range v {
+			value, err := toJSONValue(e)
+			if err != nil {
+				return nil, err
+			}
+			array[i] = value
+		}
+		return array, nil
+	case map[any]any:
+		object := make(map[string]any, len(v))
+		for k, e := range v {
+			value, err := toJSONValue(e)
+			if err != nil {
+				return nil, err
+			}
+			object[mapKey(k)] = value
+		}
+		return object, nil
+	}
+	return nil, fmt.Errorf("unsupported value of type %T", value)
+}
+
+var (
+	decoders = map[string]Decoder{
+		"application/json":         decodeJSON,
+		"application/x-ndjson":     decodeNDJSON,
+		"application/ndjson":       decodeNDJSON,
+		"application/xml":          decodeXML,
+		"text/xml":                 decodeXML,
+		"application/msgpack":      decodeMessagePack,
+		"application/x-msgpack":    decodeMessagePack,
+		"application/vnd.msgpack":  decodeMessagePack,
+		"application/cbor":         decodeCBOR,
+		"application/octet-stream": nil,
+	}
+	decodersMu sync.RWMutex
+)
+
+// RegisterDecoder registers the decoder used by the parse middleware for the
+// given media type (for example "application/yaml"), replacing the existing one if any.
+// If the decoder is `nil`, bodies of this media type are not read by the middleware
+// and are left to the handler.
+//
+// The media types "application/x-www-form-urlencoded" and "multipart/form-data" are always
+// parsed as forms and cannot be overridden.
+func RegisterDecoder(mediaType string, decoder Decoder) {
+	decodersMu.Lock()
+	defer decodersMu.Unlock()
+	decoders[strings.ToLower(mediaType)] = decoder
+}
+
+// decoderFor returns the decoder registered for the given media type. Media types using
+// a structured syntax suffix (such as "application/vnd.api+json") fall back to the
+// decoder of the suffix ("application/json").
+func (m *Middleware) decoderFor(mediaType string) (Decoder, bool) {
+	if decoder, ok := m.Decoders[mediaType]; ok {
+		return decoder, true
+	}
+	decodersMu.RLock()
+	defer decodersMu.RUnlock()
+	if decoder, ok := decoders[mediaType]; ok {
+		return decoder, true
+	}
+	if i := strings.LastIndexByte(mediaType, '+'); i != -1 {
+		decoder, ok := decoders["application/"+mediaType[i+1:]]
+		return decoder, ok
+	}
+	return nil, false
+}
+
+// parseMediaType returns the lowercase media type of the given "Content-Type" header value,
+// without its parameters.
+func parseMediaType(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType, _, _ = strings.Cut(contentType, ";")
+		return strings.ToLower(strings.TrimSpace(mediaType))
+	}
+	return mediaType
+}
+
+func decodeJSON(body []byte) (any, error) {
+	var data any
+	err := json.Unmarshal(body, &data)
+	return data, err
+}
+
+// decodeNDJSON decodes newline-delimited JSON: each non-empty line is a JSON value.
+// The result is a `[]any` containing the values of all the lines.
+func decodeNDJSON(body []byte) (any, error) {
+	data := []any{}
+	for i, line := range bytes.Split(body, []byte{'\n'}) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		var value any
+		if err := json.Unmarshal(line, &value); err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		data = append(data, value)
+	}
+	return data, nil
+}
+
+// decodeXML decodes the root element of an XML document:
+//   - an element containing only text is decoded as a `string`.
+//   - other elements are decoded as a `map[string]any`. Child elements are identified by their local name.
+//     Repeated child elements are decoded as a `[]any`. Attributes are prefixed with "@" and the text
+//     surrounding the child elements, if it is not blank, is stored in "#text".
+func decodeXML(body []byte) (any, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			if err == io.EOF {
+				return nil, errors.New("XML document has no root element")
+			}
+			return nil, err
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			return decodeXMLElement(decoder, start, 0)
+		}
+	}
+}
+
+func decodeXMLElement(decoder *xml.Decoder, start xml.StartElement, depth int) (any, error) {
+	if depth > maxDepth {
+		return nil, errMaxDepth
+	}
+	element := make(map[string]any, len(start.Attr))
+	for _, attr := range start.Attr {
+		if attr.Name.Space == "xmlns" || attr.Name.Local == "xmlns" {
+			continue
+		}
+		element["@"+attr.Name.Local] = attr.Value
+	}
+	text := &strings.Builder{}
+	hasChildren := false
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			if err == io.EOF {
+				return nil, io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			hasChildren = true
+			value, err := decodeXMLElement(decoder, t, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			name := t.Name.Local
+			switch existing := element[name].(type) {
+			case nil:
+				element[name] = value
+			case []any:
+				element[name] = append(existing, value)
+			default:
+				element[name] = []any{existing, value}
+			}
+		case xml.CharData:
+			text.Write(t)
+		case xml.EndElement:
+			if !hasChildren && len(element) == 0 {
+				return text.String(), nil
+			}
+			if str := strings.TrimSpace(text.String()); str != "" {
+				element["#text"] = str
+			}
+			return element, nil
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package parse
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMediaType(t *testing.T) {
+	cases := []struct {
+		contentType string
+		expected    string
+	}{
+		{contentType: "application/json", expected: "application/json"},
+		{contentType: "Application/JSON; charset=utf-8", expected: "application/json"},
+		{contentType: "application/xml;", expected: "application/xml"},
+		{contentType: "text/xml; charset", expected: "text/xml"},
+		{contentType: "", expected: ""},
+	}
+
+	for _, c := range cases {
+		t.Run(c.contentType, func(t *testing.T) {
+			assert.Equal(t, c.expected, parseMediaType(c.contentType))
+		})
+	}
+}
+
+func TestDecoderFor(t *testing.T) {
+	custom := func(_ []byte) (any, error) { return "custom", nil }
+	registered := func(_ []byte) (any, error) { return "registered", nil }
+
+	RegisterDecoder("Application/X-Test", registered)
+	t.Cleanup(func() {
+		decodersMu.Lock()
+		delete(decoders, "application/x-test")
+		decodersMu.Unlock()
+	})
+
+	m := &Middleware{
+		Decoders: map[string]Decoder{
+			"application/json": custom,
+		},
+	}
+
+	cases := []struct {
+		expected  any
+		mediaType string
+		body      string
+		ok        bool
+	}{
+		{mediaType: "application/json", expected: "custom", ok: true},
+		{mediaType: "application/x-test", expected: "registered", ok: true},
+		{mediaType: "application/vnd.api+json", body: "{}", expected: map[string]any{}, ok: true},
+		{mediaType: "application/atom+xml", body: "<root></root>", expected: "", ok: true},
+		{mediaType: "application/vnd.unknown+yaml", ok: false},
+		{mediaType: "text/plain", ok: false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.mediaType, func(t *testing.T) {
+			decoder, ok := m.decoderFor(c.mediaType)
+			require.Equal(t, c.ok, ok)
+			if !ok {
+				return
+			}
+			value, err := decoder([]byte(c.body))
+			require.NoError(t, err)
+			assert.Equal(t, c.expected, value)
+		})
+	}
+}
+
+func TestDecodeNDJSON(t *testing.T) {
+	value, err := decodeNDJSON([]byte("{\"a\":1}\n\n  [\"b\"]  \r\n\"c\"\n"))
+	require.NoError(t, err)
+	assert.Equal(t, []any{map[string]any{"a": 1.0}, []any{"b"}, "c"}, value)
+
+	value, err = decodeNDJSON([]byte(""))
+	require.NoError(t, err)
+	assert.Equal(t, []any{}, value)
+
+	_, err = decodeNDJSON([]byte("{\"a\":1}\n{invalid}"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "line 2")
+}
+
+func TestDecodeXML(t *testing.T) {
+	cases := []struct {
+		expected any
+		body     string
+		desc     string
+	}{
+		{
+			desc: "object",
+			body: `<?xml version="1.0" encoding="UTF-8"?>
+<!-- comment -->
+<user xmlns="http://example.org" xmlns:x="http://example.org/x" id="1">
+	<name>John</name>
+	<x:tag>a</x:tag>
+	<x:tag>b</x:tag>
+	<tag>c</tag>
+	<address country="FR"><city>Paris</city></address>
+	<empty/>
+</user>`,
+			expected: map[string]any{
+				"@id":     "1",
+				"name":    "John",
+				"tag":     []any{"a", "b", "c"},
+				"address": map[string]any{"@country": "FR", "city": "Paris"},
+				"empty":   "",
+			},
+		},
+		{
+			desc:     "text",
+			body:     `<root> text </root>`,
+			expected: " text ",
+		},
+		{
+			desc:     "mixed_content",
+			body:     `<root> text <b>bold</b> end </root>`,
+			expected: map[string]any{"#text": "text  end", "b": "bold"},
+		},
+		{
+			desc:     "attribute_only",
+			body:     `<root a="b"/>`,
+			expected: map[string]any{"@a": "b"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			value, err := decodeXML([]byte(c.body))
+			require.NoError(t, err)
+			assert.Equal(t, c.expected, value)
+		})
+	}
+}
+
+func TestDecodeXMLErrors(t *testing.T) {
+	cases := []struct {
+		body string
+		desc string
+	}{
+		{desc: "empty", body: ""},
+		{desc: "no_root", body: `<?xml version="1.0"?><!-- comment -->`},
+		{desc: "unclosed", body: `<root><a>b</a>`},
+		{desc: "mismatched", body: `<root><a>b</c></root>`},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			_, err := decodeXML([]byte(c.body))
+			require.Error(t, err)
+		})
+	}
+}
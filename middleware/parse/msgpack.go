@@ -0,0 +1,91 @@
+package parse
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/vmihailenco/msgpack/v5"
+	"github.com/vmihailenco/msgpack/v5/msgpcode"
+)
+
+// decodeMessagePack decodes a MessagePack document. Integers are decoded as `float64`,
+// binary data as `string` and timestamps as `time.Time`. Map keys that are not strings are
+// formatted with `fmt.Sprint()`. Other extension types are not supported.
+func decodeMessagePack(body []byte) (any, error) {
+	reader := bytes.NewReader(body)
+	decoder := msgpack.NewDecoder(reader)
+	value, err := decodeMessagePackValue(decoder, len(body), 0)
+	if err == io.EOF {
+		return nil, io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return nil, err
+	}
+	if reader.Len() != 0 {
+		return nil, errors.New("invalid MessagePack: trailing data")
+	}
+	return value, nil
+}
+
+// decodeMessagePackValue decodes the next value. Arrays and maps are decoded here so their
+// nesting depth can be limited, scalar values are decoded by the library. `size` is the size
+// of the body, used to limit the preallocated length of arrays and maps.
+func decodeMessagePackValue(decoder *msgpack.Decoder, size, depth int) (any, error) {
+	if depth > maxDepth {
+		return nil, errMaxDepth
+	}
+	code, err := decoder.PeekCode()
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case msgpcode.IsFixedArray(code) || code == msgpcode.Array16 || code == msgpcode.Array32:
+		length, err := decoder.DecodeArrayLen()
+		if err != nil {
+			return nil, err
+		}
+		array := make([]any, 0, min(length, size))
+		for range length {
+			value, err := decodeMessagePackValue(decoder, size, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			array = append(array, value)
+		}
+		return array, nil
+	case msgpcode.IsFixedMap(code) || code == msgpcode.Map16 || code == msgpcode.Map32:
+		length, err := decoder.DecodeMapLen()
+		if err != nil {
+			return nil, err
+		}
+		object := make(map[string]any, min(length, size))
+		for range length {
+			key, err := decodeMessagePackValue(decoder, size, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeMessagePackValue(decoder, size, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			object[mapKey(key)] = value
+		}
+		return object, nil
+	}
+
+	value, err := decoder.DecodeInterfaceLoose()
+	if err != nil {
+		return nil, err
+	}
+	return toJSONValue(value)
+}
+
+// mapKey returns the given map key as a string.
+func mapKey(key any) string {
+	if str, ok := key.(string); ok {
+		return str
+	}
+	return fmt.Sprint(key)
+}
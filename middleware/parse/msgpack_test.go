@@ -0,0 +1,88 @@
+package parse
+
+import (
+	"encoding/hex"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeMessagePack(t *testing.T) {
+	cases := []struct {
+		expected any
+		hex      string
+		desc     string
+	}{
+		{
+			desc:     "object",
+			hex:      "84a16101a1629" + "3c3c0a178a163ffa164cb3ff8000000000000",
+			expected: map[string]any{"a": 1.0, "b": []any{true, nil, "x"}, "c": -1.0, "d": 1.5},
+		},
+		{desc: "uint8", hex: "cc80", expected: 128.0},
+		{desc: "uint16", hex: "cd0100", expected: 256.0},
+		{desc: "uint32", hex: "ce00010000", expected: 65536.0},
+		{desc: "uint64", hex: "cf0000000000000001", expected: 1.0},
+		{desc: "int8", hex: "d080", expected: -128.0},
+		{desc: "int16", hex: "d1ff00", expected: -256.0},
+		{desc: "int32", hex: "d2ffffffff", expected: -1.0},
+		{desc: "int64", hex: "d3fffffffffffffffe", expected: -2.0},
+		{desc: "float32", hex: "ca3fc00000", expected: 1.5},
+		{desc: "false", hex: "c2", expected: false},
+		{desc: "str8", hex: "d903616263", expected: "abc"},
+		{desc: "str16", hex: "da0003616263", expected: "abc"},
+		{desc: "str32", hex: "db00000003616263", expected: "abc"},
+		{desc: "bin8", hex: "c4026869", expected: "hi"},
+		{desc: "array16", hex: "dc000101", expected: []any{1.0}},
+		{desc: "array32", hex: "dd0000000101", expected: []any{1.0}},
+		{desc: "map16", hex: "de0001a16b01", expected: map[string]any{"k": 1.0}},
+		{desc: "map32", hex: "df00000001a16b01", expected: map[string]any{"k": 1.0}},
+		{desc: "non_string_key", hex: "810102", expected: map[string]any{"1": 2.0}},
+		{desc: "timestamp32", hex: "d6ff00000001", expected: time.Unix(1, 0).UTC()},
+		{desc: "timestamp64", hex: "d7ff0000000400000001", expected: time.Unix(1, 1).UTC()},
+		{desc: "timestamp96", hex: "c70cff00000001ffffffffffffffff", expected: time.Unix(-1, 1).UTC()},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			body, err := hex.DecodeString(c.hex)
+			require.NoError(t, err)
+			value, err := decodeMessagePack(body)
+			require.NoError(t, err)
+			assert.Equal(t, c.expected, value)
+		})
+	}
+}
+
+func TestDecodeMessagePackErrors(t *testing.T) {
+	cases := []struct {
+		expected error
+		hex      string
+		desc     string
+	}{
+		{desc: "empty", hex: "", expected: io.ErrUnexpectedEOF},
+		{desc: "unknown_format", hex: "c1"},
+		{desc: "truncated_array", hex: "9201", expected: io.ErrUnexpectedEOF},
+		{desc: "truncated_string", hex: "a3616263"[:6], expected: io.ErrUnexpectedEOF},
+		{desc: "huge_length", hex: "dbffffffff", expected: io.ErrUnexpectedEOF},
+		{desc: "trailing_data", hex: "0101"},
+		{desc: "unsupported_extension", hex: "d40100"},
+		{desc: "invalid_timestamp", hex: "c703ff000000"},
+		{desc: "max_depth", hex: strings.Repeat("91", maxDepth+2) + "01", expected: errMaxDepth},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			body, err := hex.DecodeString(c.hex)
+			require.NoError(t, err)
+			_, err = decodeMessagePack(body)
+			require.Error(t, err)
+			if c.expected != nil {
+				assert.ErrorIs(t, err, c.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package parse
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"goyave.dev/goyave/v5/util/fsutil"
+)
+
+// errPartTooLarge returned when a multipart part exceeds the per-file or per-field limit.
+var errPartTooLarge = errors.New("multipart part too large")
+
+// bodyReader records the error returned by the underlying reader so read errors
+// can be told apart from malformed content.
+type bodyReader struct {
+	reader io.Reader
+	err    error
+}
+
+func (r *bodyReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	if err != nil && err != io.EOF {
+		r.err = err
+	}
+	return n, err
+}
+
+// multipartBoundary returns the boundary of the given content type if it is "multipart/form-data".
+func multipartBoundary(contentType string) (string, bool) {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil || mediaType != "multipart/form-data" || params["boundary"] == "" {
+		return "", false
+	}
+	return params["boundary"], true
+}
+
+// getMaxMemory returns the maximum size of the file parts kept in memory in bytes.
+func (m *Middleware) getMaxMemory() int64 {
+	if m.MaxMemory == 0 {
+		return defaultMaxMemory
+	}
+	return int64(m.MaxMemory * 1024 * 1024)
+}
+
+// parseMultipartStream reads the multipart body part by part, without buffering it entirely.
+// The returned form must be removed with `RemoveAll()` once the request is finished.
+func (m *Middleware) parseMultipartStream(body io.Reader, boundary string, budget *elementBudget) (map[string]any, *multipart.Form, error) {
+	form, err := m.readMultipartStream(body, boundary)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	flatMap := make(map[string]any, len(form.Value)+len(form.File))
+	if err := m.setValues(flatMap, form.Value, budget); err != nil {
+		_ = form.RemoveAll()
+		return nil, nil, err
+	}
+	for field, headers := range form.File {
+		files, err := fsutil.ParseMultipartFiles(headers)
+		if err == nil {
+			err = m.setFiles(flatMap, field, files, budget)
+		}
+		if err != nil {
+			_ = form.RemoveAll()
+			return nil, nil, err
+		}
+	}
+	return flatMap, form, nil
+}
+
+// readMultipartStream reads the parts of the body one by one, enforcing `MaxFieldSize` and
+// `MaxFileSize` while they are read. Values are kept in memory. Files are kept in memory as
+// long as their total size doesn't exceed `MaxMemory` and are written to temporary files otherwise.
+// If an error occurs, the temporary files already written are removed.
+func (m *Middleware) readMultipartStream(body io.Reader, boundary string) (form *multipart.Form, err error) {
+	maxFileSize := int64(m.MaxFileSize * 1024 * 1024)
+	maxFieldSize := int64(m.MaxFieldSize * 1024 * 1024)
+	memory := m.getMaxMemory()
+
+	form = &multipart.Form{Value: url.Values{}, File: map[string][]*multipart.FileHeader{}}
+	defer func() {
+		if err != nil {
+			_ = form.RemoveAll()
+			form = nil
+		}
+	}()
+
+	reader := multipart.NewReader(body, boundary)
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return form, nil
+		}
+		if err != nil {
+			return form, err
+		}
+
+		name := part.FormName()
+		if name == "" {
+			continue
+		}
+		if part.FileName() == "" {
+			var value strings.Builder
+			if _, err := io.Copy(&value, &limitedPart{part: part, limit: maxFieldSize}); err != nil {
+				return form, err
+			}
+			form.Value[name] = append(form.Value[name], value.String())
+			continue
+		}
+
+		header, err := readFilePart(part, maxFileSize, memory)
+		if err != nil {
+			return form, err
+		}
+		if header.Size <= memory {
+			// The file is kept in memory
+			memory -= header.Size
+		}
+		form.File[name] = append(form.File[name], header)
+	}
+}
+
+// readFilePart reads the content of a file part. The content is kept in memory if its size
+// doesn't exceed `maxMemory` and is written to a temporary file otherwise.
+//
+// A `*multipart.FileHeader` can only be created by `multipart.Reader.ReadForm()`, so the part
+// is read as the only part of a form wrapping it, without copying it beforehand.
+func readFilePart(part *multipart.Part, maxFileSize, maxMemory int64) (*multipart.FileHeader, error) {
+	var prefix bytes.Buffer
+	writer := multipart.NewWriter(&prefix)
+	if _, err := writer.CreatePart(part.Header); err != nil {
+		return nil, err
+	}
+	content := io.MultiReader(
+		&prefix,
+		&limitedPart{part: part, limit: maxFileSize},
+		strings.NewReader("\r\n--"+writer.Boundary()+"--\r\n"),
+	)
+	form, err := multipart.NewReader(content, writer.Boundary()).ReadForm(maxMemory)
+	if err != nil {
+		return nil, err
+	}
+	return form.File[part.FormName()][0], nil
+}
+
+// limitedPart reads a multipart part and returns an error wrapping `errPartTooLarge`
+// as soon as more than `limit` bytes are read. If `limit` is 0, the part is not limited.
+type limitedPart struct {
+	part  *multipart.Part
+	limit int64
+	read  int64
+}
+
+func (r *limitedPart) Read(p []byte) (int, error) {
+	n, err := r.part.Read(p)
+	r.read += int64(n)
+	if r.limit > 0 && r.read > r.limit {
+		return n, fmt.Errorf("%w: %q exceeds %d bytes", errPartTooLarge, r.part.FormName(), r.limit)
+	}
+	return n, err
+}
+
+func isTooLarge(err error) bool {
+	var maxBytesErr *http.MaxBytesError
+	return errors.Is(err, errPartTooLarge) || errors.As(err, &maxBytesErr)
+}
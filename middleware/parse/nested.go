@@ -0,0 +1,265 @@
+package parse
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"goyave.dev/goyave/v5/util/fsutil"
+)
+
+const (
+	defaultMaxDepth    = 10
+	defaultMaxIndex    = 20
+	defaultMaxElements = 1000
+)
+
+type segmentKind int
+
+const (
+	segmentKey    segmentKind = iota // "user.name" or "user[name]"
+	segmentIndex                     // "tags[0]"
+	segmentAppend                    // "tags[]"
+)
+
+type keySegment struct {
+	key   string
+	index int
+	kind  segmentKind
+}
+
+// getMaxDepth returns the maximum nesting depth of the keys.
+func (m *Middleware) getMaxDepth() int {
+	if m.MaxDepth <= 0 {
+		return defaultMaxDepth
+	}
+	return m.MaxDepth
+}
+
+// getMaxIndex returns the maximum array index of the keys.
+func (m *Middleware) getMaxIndex() int {
+	if m.MaxIndex <= 0 {
+		return defaultMaxIndex
+	}
+	return m.MaxIndex
+}
+
+// getMaxElements returns the maximum total number of array elements created for a request.
+func (m *Middleware) getMaxElements() int {
+	if m.MaxElements <= 0 {
+		return defaultMaxElements
+	}
+	return m.MaxElements
+}
+
+// elementBudget the number of array elements that can still be created while
+// parsing the nested keys of a request. It is shared by the query and the body.
+type elementBudget struct {
+	remaining int
+	max       int
+}
+
+func (m *Middleware) newElementBudget() *elementBudget {
+	return &elementBudget{remaining: m.getMaxElements(), max: m.getMaxElements()}
+}
+
+// take removes `n` elements from the budget. Returns false if there are not enough
+// elements remaining.
+func (b *elementBudget) take(n int) bool {
+	if n > b.remaining {
+		return false
+	}
+	b.remaining -= n
+	return true
+}
+
+// setValues puts the given values in `dst`. Fields having a single value are
+// converted to non-array. If `NestedKeys` is enabled, the keys are parsed to build
+// nested objects and arrays.
+func (m *Middleware) setValues(dst map[string]any, values url.Values, budget *elementBudget) error {
+	if !m.NestedKeys {
+		flatten(dst, values)
+		return nil
+	}
+	for key, value := range values {
+		if err := setNested(m, dst, key, value, stringLeaf, budget); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setFiles puts the given files in `dst`. If `NestedKeys` is enabled, the field
+// name is parsed to build nested objects and arrays.
+func (m *Middleware) setFiles(dst map[string]any, field string, files []fsutil.File, budget *elementBudget) error {
+	if !m.NestedKeys {
+		dst[field] = files
+		return nil
+	}
+	return setNested(m, dst, field, files, fileLeaf, budget)
+}
+
+func stringLeaf(values []string, array bool) any {
+	if array || len(values) > 1 {
+		return values
+	}
+	return values[0]
+}
+
+func fileLeaf(files []fsutil.File, _ bool) any {
+	return files
+}
+
+// setNested parses the given key and puts the values at the resulting path in `dst`.
+// `leaf` converts the values to the final value of the field. Its `array` parameter is
+// `true` if the key ends with "[]". The elements added to arrays are taken from `budget`.
+func setNested[T any](m *Middleware, dst map[string]any, key string, values []T, leaf func(values []T, array bool) any, budget *elementBudget) error {
+	path, err := parseKey(key)
+	if err != nil {
+		return err
+	}
+	if len(path)-1 > m.getMaxDepth() {
+		return fmt.Errorf("key %q exceeds the max depth of %d", key, m.getMaxDepth())
+	}
+	b := &nestedBuilder[T]{key: key, leaf: leaf, maxIndex: m.getMaxIndex(), budget: budget}
+	_, err = b.insert(dst, path, values)
+	return err
+}
+
+// parseKey splits a key using bracket and dot notation into segments. Dot segments and
+// non-numeric bracket segments are object keys. Numeric bracket segments are array indexes
+// and empty bracket segments append to an array.
+//
+// For example, "user.addresses[0][city]" is parsed to `["user", "addresses", 0, "city"]`.
+func parseKey(key string) ([]keySegment, error) {
+	end := strings.IndexAny(key, ".[")
+	if end == -1 {
+		return []keySegment{{key: key}}, nil
+	}
+	if end == 0 {
+		return nil, fmt.Errorf("invalid key %q: empty segment", key)
+	}
+	path := []keySegment{{key: key[:end]}}
+	for i := end; i < len(key); {
+		switch key[i] {
+		case '.':
+			end = strings.IndexAny(key[i+1:], ".[")
+			if end == -1 {
+				end = len(key) - i - 1
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("invalid key %q: empty segment", key)
+			}
+			path = append(path, keySegment{key: key[i+1 : i+1+end]})
+			i += end + 1
+		case '[':
+			end = strings.IndexByte(key[i+1:], ']')
+			if end == -1 {
+				return nil, fmt.Errorf("invalid key %q: unclosed bracket", key)
+			}
+			path = append(path, bracketSegment(key[i+1:i+1+end]))
+			i += end + 2
+		default:
+			return nil, fmt.Errorf("invalid key %q: unexpected character after closing bracket", key)
+		}
+	}
+	return path, nil
+}
+
+func bracketSegment(content string) keySegment {
+	if content == "" {
+		return keySegment{kind: segmentAppend}
+	}
+	if index, err := strconv.Atoi(content); err == nil && index >= 0 && content[0] != '+' {
+		return keySegment{index: index, kind: segmentIndex}
+	}
+	return keySegment{key: content}
+}
+
+// nestedBuilder inserts the values of a single key in a structure made
+// of `map[string]any` and `[]any`.
+type nestedBuilder[T any] struct {
+	leaf     func(values []T, array bool) any
+	budget   *elementBudget
+	key      string
+	maxIndex int
+}
+
+// insert inserts the values at the given path in the container and returns the
+// updated container. If the container is `nil`, a new one is created.
+func (b *nestedBuilder[T]) insert(container any, path []keySegment, values []T) (any, error) {
+	if len(path) == 0 {
+		if container != nil {
+			return nil, b.conflictError()
+		}
+		return b.leaf(values, false), nil
+	}
+
+	segment := path[0]
+	switch segment.kind {
+	case segmentKey:
+		object, ok := container.(map[string]any)
+		if container == nil {
+			object, ok = map[string]any{}, true
+		}
+		if !ok {
+			return nil, b.conflictError()
+		}
+		value, err := b.insert(object[segment.key], path[1:], values)
+		if err != nil {
+			return nil, err
+		}
+		object[segment.key] = value
+		return object, nil
+	case segmentIndex:
+		array, err := b.grow(container, segment.index)
+		if err != nil {
+			return nil, err
+		}
+		array[segment.index], err = b.insert(array[segment.index], path[1:], values)
+		return array, err
+	default: // segmentAppend
+		if len(path) == 1 {
+			if container != nil {
+				return nil, b.conflictError()
+			}
+			return b.leaf(values, true), nil
+		}
+		// Not the last segment: the n-th value goes to the n-th element of the array.
+		array, err := b.grow(container, len(values)-1)
+		if err != nil {
+			return nil, err
+		}
+		for i := range values {
+			array[i], err = b.insert(array[i], path[1:], values[i:i+1])
+			if err != nil {
+				return nil, err
+			}
+		}
+		return array, nil
+	}
+}
+
+// grow returns the container as a `[]any` long enough to contain the given index.
+// Missing elements are `nil` and count towards the element budget.
+func (b *nestedBuilder[T]) grow(container any, index int) ([]any, error) {
+	array, ok := container.([]any)
+	if container != nil && !ok {
+		return nil, b.conflictError()
+	}
+	if index > b.maxIndex {
+		return nil, fmt.Errorf("key %q exceeds the max array index of %d", b.key, b.maxIndex)
+	}
+	if n := index + 1 - len(array); n > 0 {
+		if !b.budget.take(n) {
+			return nil, fmt.Errorf("key %q exceeds the max number of array elements of %d", b.key, b.budget.max)
+		}
+		array = append(array, make([]any, n)...)
+	}
+	return array, nil
+}
+
+func (b *nestedBuilder[T]) conflictError() error {
+	return fmt.Errorf("key %q conflicts with another key", b.key)
+}
@@ -0,0 +1,237 @@
+package parse
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"goyave.dev/goyave/v5/util/fsutil"
+)
+
+func TestParseKey(t *testing.T) {
+	cases := []struct {
+		key      string
+		wantErr  string
+		expected []keySegment
+	}{
+		{key: "", expected: []keySegment{{key: ""}}},
+		{key: "name", expected: []keySegment{{key: "name"}}},
+		{key: "user.name", expected: []keySegment{{key: "user"}, {key: "name"}}},
+		{key: "user[name]", expected: []keySegment{{key: "user"}, {key: "name"}}},
+		{key: "tags[]", expected: []keySegment{{key: "tags"}, {kind: segmentAppend}}},
+		{key: "tags[12]", expected: []keySegment{{key: "tags"}, {index: 12, kind: segmentIndex}}},
+		{key: "tags[-1]", expected: []keySegment{{key: "tags"}, {key: "-1"}}},
+		{key: "tags[+1]", expected: []keySegment{{key: "tags"}, {key: "+1"}}},
+		{key: "tags.0", expected: []keySegment{{key: "tags"}, {key: "0"}}},
+		{key: "a[b.c]", expected: []keySegment{{key: "a"}, {key: "b.c"}}},
+		{
+			key:      "user.addresses[0][city].name",
+			expected: []keySegment{{key: "user"}, {key: "addresses"}, {index: 0, kind: segmentIndex}, {key: "city"}, {key: "name"}},
+		},
+		{key: ".a", wantErr: "empty segment"},
+		{key: "[a]", wantErr: "empty segment"},
+		{key: "a..b", wantErr: "empty segment"},
+		{key: "a.", wantErr: "empty segment"},
+		{key: "a[b", wantErr: "unclosed bracket"},
+		{key: "a[b]c", wantErr: "unexpected character"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.key, func(t *testing.T) {
+			path, err := parseKey(c.key)
+			if c.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), c.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, c.expected, path)
+		})
+	}
+}
+
+func TestSetValues(t *testing.T) {
+	cases := []struct {
+		middleware *Middleware
+		expected   map[string]any
+		desc       string
+		query      string
+		wantErr    string
+	}{
+		{
+			desc:       "disabled",
+			middleware: &Middleware{},
+			query:      "user[name]=x&tags[]=a&tags[]=b",
+			expected:   map[string]any{"user[name]": "x", "tags[]": []string{"a", "b"}},
+		},
+		{
+			desc:       "objects",
+			middleware: &Middleware{NestedKeys: true},
+			query:      "user[name]=x&user.email=y&user[address][city]=z&a=b",
+			expected: map[string]any{
+				"user": map[string]any{"name": "x", "email": "y", "address": map[string]any{"city": "z"}},
+				"a":    "b",
+			},
+		},
+		{
+			desc:       "arrays",
+			middleware: &Middleware{NestedKeys: true},
+			query:      "tags[]=a&tags[]=b&single[]=c&multi=d&multi=e&indexed[2]=f&indexed[0]=g",
+			expected: map[string]any{
+				"tags":    []string{"a", "b"},
+				"single":  []string{"c"},
+				"multi":   []string{"d", "e"},
+				"indexed": []any{"g", nil, "f"},
+			},
+		},
+		{
+			desc:       "array_of_objects",
+			middleware: &Middleware{NestedKeys: true},
+			query:      "users[][name]=a&users[][age]=1&users[][name]=b&rows[1][id]=2&rows[0][id]=1&rows[0][tags][]=t",
+			expected: map[string]any{
+				"users": []any{map[string]any{"name": "a", "age": "1"}, map[string]any{"name": "b"}},
+				"rows": []any{
+					map[string]any{"id": "1", "tags": []string{"t"}},
+					map[string]any{"id": "2"},
+				},
+			},
+		},
+		{
+			desc:       "nested_arrays",
+			middleware: &Middleware{NestedKeys: true},
+			query:      "matrix[0][1]=a&matrix[1][0]=b",
+			expected: map[string]any{
+				"matrix": []any{[]any{nil, "a"}, []any{"b"}},
+			},
+		},
+		{
+			desc:       "conflict_object",
+			middleware: &Middleware{NestedKeys: true},
+			query:      "a=1&a[b]=2",
+			wantErr:    "conflicts with another key",
+		},
+		{
+			desc:       "conflict_array",
+			middleware: &Middleware{NestedKeys: true},
+			query:      "a[]=1&a[0]=2",
+			wantErr:    "conflicts with another key",
+		},
+		{
+			desc:       "conflict_dot_bracket",
+			middleware: &Middleware{NestedKeys: true},
+			query:      "a.b=1&a[b]=2",
+			wantErr:    "conflicts with another key",
+		},
+		{
+			desc:       "max_depth",
+			middleware: &Middleware{NestedKeys: true, MaxDepth: 2},
+			query:      "a[b][c][d]=1",
+			wantErr:    "max depth of 2",
+		},
+		{
+			desc:       "within_max_depth",
+			middleware: &Middleware{NestedKeys: true, MaxDepth: 2},
+			query:      "a[b][c]=1",
+			expected:   map[string]any{"a": map[string]any{"b": map[string]any{"c": "1"}}},
+		},
+		{
+			desc:       "max_index",
+			middleware: &Middleware{NestedKeys: true, MaxIndex: 5},
+			query:      "a[6]=1",
+			wantErr:    "max array index of 5",
+		},
+		{
+			desc:       "max_index_append",
+			middleware: &Middleware{NestedKeys: true, MaxIndex: 1},
+			query:      "a[][b]=1&a[][b]=2&a[][b]=3",
+			wantErr:    "max array index of 1",
+		},
+		{
+			desc:       "default_max_index",
+			middleware: &Middleware{NestedKeys: true},
+			query:      "a[21]=1",
+			wantErr:    "max array index of 20",
+		},
+		{
+			desc:       "max_elements",
+			middleware: &Middleware{NestedKeys: true, MaxElements: 25},
+			query:      "a[19]=1&b[4]=2&c[0]=3",
+			wantErr:    "exceeds the max number of array elements of 25",
+		},
+		{
+			desc:       "within_max_elements",
+			middleware: &Middleware{NestedKeys: true, MaxElements: 25},
+			query:      "a[19]=1&b[4]=2",
+			expected: map[string]any{
+				"a": append(make([]any, 19), "1"),
+				"b": []any{nil, nil, nil, nil, "2"},
+			},
+		},
+		{
+			desc:       "default_max_elements",
+			middleware: &Middleware{NestedKeys: true},
+			query:      manySparseKeys(60),
+			wantErr:    "max number of array elements of 1000",
+		},
+		{
+			desc:       "invalid_key",
+			middleware: &Middleware{NestedKeys: true},
+			query:      "a[b=1",
+			wantErr:    "unclosed bracket",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			values, err := url.ParseQuery(c.query)
+			require.NoError(t, err)
+			dst := map[string]any{}
+			err = c.middleware.setValues(dst, values, c.middleware.newElementBudget())
+			if c.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), c.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, c.expected, dst)
+		})
+	}
+}
+
+// manySparseKeys returns a query containing `n` keys with the maximum index.
+func manySparseKeys(n int) string {
+	keys := make([]string, 0, n)
+	for i := range n {
+		keys = append(keys, fmt.Sprintf("k%d[%d]=1", i, defaultMaxIndex))
+	}
+	return strings.Join(keys, "&")
+}
+
+func TestSetFiles(t *testing.T) {
+	files := []fsutil.File{{MIMEType: "text/plain"}, {MIMEType: "image/png"}}
+
+	m := &Middleware{}
+	dst := map[string]any{}
+	require.NoError(t, m.setFiles(dst, "user[avatar]", files, m.newElementBudget()))
+	assert.Equal(t, map[string]any{"user[avatar]": files}, dst)
+
+	m.NestedKeys = true
+	dst = map[string]any{}
+	require.NoError(t, m.setFiles(dst, "user[avatar]", files[:1], m.newElementBudget()))
+	require.NoError(t, m.setFiles(dst, "attachments[]", files, m.newElementBudget()))
+	require.NoError(t, m.setFiles(dst, "documents[][file]", files, m.newElementBudget()))
+	expected := map[string]any{
+		"user":        map[string]any{"avatar": files[:1]},
+		"attachments": files,
+		"documents": []any{
+			map[string]any{"file": files[:1]},
+			map[string]any{"file": files[1:]},
+		},
+	}
+	assert.Equal(t, expected, dst)
+
+	assert.Error(t, m.setFiles(dst, "user[avatar]", files, m.newElementBudget()))
+}
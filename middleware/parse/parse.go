@@ -2,17 +2,47 @@ package parse
 
 import (
 	"bytes"
-	"encoding/json"
 	"fmt"
 	"io"
+	"mime/multipart"
 	"net/http"
 	"net/url"
 	"strings"
 
+	"github.com/samber/lo"
 	"goyave.dev/goyave/v5"
+	"goyave.dev/goyave/v5/config"
+	"goyave.dev/goyave/v5/util/errors"
 	"goyave.dev/goyave/v5/util/fsutil"
 )
 
+// MetaMaxUploadSize overrides the maximum size of the request body for the route or router
+// holding this meta and their children. The value must be an `UploadSize`, which is a number
+// of bytes. Use `MaxUploadSize()` to create it from a byte-size string:
+//
+//	router.SetMeta(parse.MetaMaxUploadSize, parse.MaxUploadSize("10MiB"))
+const MetaMaxUploadSize = "goyave.max-upload-size"
+
+// UploadSize is a maximum request body size in bytes, used as the value of the
+// `MetaMaxUploadSize` meta.
+type UploadSize int64
+
+// MaxUploadSize parses the given byte size (for example "512KiB" or "10MB", see `config.ParseByteSize()`)
+// and returns it as an `UploadSize` that can be used as the value of the `MetaMaxUploadSize` meta.
+// A number without unit is a number of bytes.
+//
+// Panics if the size is invalid or negative, so the error is reported when the routes are registered.
+func MaxUploadSize(size string) UploadSize {
+	n, err := config.ParseByteSize(size)
+	if err != nil {
+		panic(errors.Errorf("parse: invalid max upload size: %w", err))
+	}
+	if n < 0 {
+		panic(errors.Errorf("parse: invalid max upload size: negative size %q", size))
+	}
+	return UploadSize(n)
+}
+
 // Middleware reading the raw request query and body.
 //
 // First, the query is parsed using Go's standard `url.ParseQuery()`. After being flattened
@@ -20,32 +50,109 @@ import (
 // If the parsing fails, returns "400 Bad request".
 //
 // The body is read only if the "Content-Type" header is set. If
-// the body exceeds the configured max upload size (in MiB), "413 Request Entity Too Large"
-// is returned.
-// If the content type is "application/json", the middleware will attempt
-// to unmarshal the body and put the result in the request's `Data`. If it fails, returns "400 Bad request".
-// If the content-type has another value, Go's standard `ParseMultipartForm` is called. The result
-// is put inside the request's `Data` after being flattened.
-// If the form is not a multipart form, attempts `ParseForm`. If `ParseMultipartForm` or `ParseForm` return
-// an error, returns "400 Bad request".
+// the body exceeds the configured max upload size, "413 Request Entity Too Large"
+// is returned. The max upload size can be overridden per route or per router using
+// the `MetaMaxUploadSize` meta. Requests with a "Content-Length" exceeding the limit are
+// rejected before reading the body, so clients sending "Expect: 100-continue" don't send it.
+// The body is also limited for handlers reading `request.Body()` directly.
+// If the content type is "multipart/form-data" or "application/x-www-form-urlencoded", Go's
+// standard `ParseMultipartForm` or `ParseForm` is called. The result is put inside the request's
+// `Data` after being flattened. If the parsing fails, returns "400 Bad request".
+// For other content types, the body is decoded with the `Decoder` registered for the media type
+// and the result is put in the request's `Data`. If it fails, returns "400 Bad request".
+// If no decoder is registered for the media type, returns "415 Unsupported Media Type".
+// If the decoder registered is `nil` (the default for "application/octet-stream"), the body is
+// not read by the middleware and is left to the handler.
+//
+// The following media types are supported by default:
+//   - "application/json"
+//   - "application/x-ndjson", "application/ndjson" (newline-delimited JSON, decoded as a `[]any`)
+//   - "application/xml", "text/xml"
+//   - "application/msgpack", "application/x-msgpack", "application/vnd.msgpack"
+//   - "application/cbor"
+//
+// Media types with a structured syntax suffix ("application/vnd.api+json") use the decoder of the suffix.
+// More decoders can be registered with `RegisterDecoder()` or per middleware with `Decoders`.
 //
 // In `multipart/form-data`, all file parts are automatically converted to `[]fsutil.File`.
 // Inside `request.Data`, a field of type "file" will therefore always be of type `[]fsutil.File`.
 // It is a slice so it support multi-file uploads in a single field.
+//
+// If `NestedKeys` is enabled, the keys of the query and of the forms are parsed using bracket and
+// dot notation so they match the paths used by the validation:
+//   - "user[name]=x" and "user.name=x" result in `{"user": {"name": "x"}}`
+//   - "tags[]=a&tags[]=b" results in `{"tags": ["a", "b"]}`. With "[]", the field is always an array.
+//   - "tags[1]=b&tags[0]=a" results in `{"tags": ["a", "b"]}`. Missing indexes are `nil`.
+//   - "users[][name]=a&users[][name]=b" results in `{"users": [{"name": "a"}, {"name": "b"}]}`:
+//     the n-th value goes to the n-th element of the array.
+//
+// Keys conflicting with each other (such as "a=1&a[b]=2"), keys deeper than `MaxDepth`, indexes
+// greater than `MaxIndex` and requests creating more than `MaxElements` array elements
+// result in "400 Bad request".
+//
+// By default, the whole body is read in memory before being parsed. If `Streaming` is enabled,
+// `multipart/form-data` bodies are parsed part by part as they are received instead:
+// file parts exceeding `MaxMemory` are written to temporary files (in `os.TempDir()`),
+// which are removed when the request ends. If a part exceeds `MaxFileSize` or
+// `MaxFieldSize`, "413 Request Entity Too Large" is returned.
 type Middleware struct {
 	goyave.Component
 
+	// Decoders the body decoders specific to this middleware, indexed by media type.
+	// They take precedence over the decoders registered with `RegisterDecoder()`.
+	Decoders map[string]Decoder
+
 	// MaxUpoadSize the maximum size of the request (in MiB).
-	// Defaults to the value provided in the config "server.maxUploadSize".
+	// Defaults to the value provided in the config "server.maxUploadSize" (see `config.KindByteSize`).
+	// The `MetaMaxUploadSize` route meta takes precedence over this value.
 	MaxUploadSize float64
+
+	// MaxMemory the maximum total size of the file parts kept in memory (in MiB) in
+	// streaming mode. Files that don't fit are written to temporary files.
+	// Defaults to 1 MiB.
+	MaxMemory float64
+
+	// MaxFileSize the maximum size of a single file (in MiB) in streaming mode.
+	// If 0, files are only limited by `MaxUploadSize`.
+	MaxFileSize float64
+
+	// MaxFieldSize the maximum size of a single non-file field (in MiB) in streaming mode.
+	// If 0, fields are only limited by `MaxUploadSize`.
+	MaxFieldSize float64
+
+	// MaxDepth the maximum nesting depth of the keys parsed when `NestedKeys` is enabled.
+	// For example, "user[address][city]" has a depth of 2.
+	// Defaults to 10.
+	MaxDepth int
+
+	// MaxIndex the maximum array index of the keys parsed when `NestedKeys` is enabled.
+	// Missing elements of the arrays are `nil`, so this limits the size of the arrays.
+	// Defaults to 20.
+	MaxIndex int
+
+	// MaxElements the maximum total number of array elements, including the missing
+	// elements set to `nil`, created for the query and body of a request when `NestedKeys`
+	// is enabled. Defaults to 1000.
+	MaxElements int
+
+	// Streaming if true, `multipart/form-data` bodies are parsed while being read
+	// instead of being buffered entirely in memory first.
+	Streaming bool
+
+	// NestedKeys if true, the keys of the query and of url-encoded and multipart forms are
+	// parsed using bracket and dot notation to build nested objects and arrays.
+	NestedKeys bool
 }
 
+const defaultMaxMemory int64 = 1 << 20
+
 // Handle reads the request query and body and parses it if necessary.
 // If the request Data is not nil, the body is not parsed again and the
 // middleware immediately passes after parsing the query.
 func (m *Middleware) Handle(next goyave.Handler) goyave.Handler {
 	return func(response *goyave.Response, r *goyave.Request) {
-		if err := parseQuery(r); err != nil {
+		budget := m.newElementBudget()
+		if err := m.parseQuery(r, budget); err != nil {
 			response.Status(http.StatusBadRequest)
 			r.Extra[goyave.ExtraParseError{}] = fmt.Errorf("%w: %w", goyave.ErrInvalidQuery, err)
 			return
@@ -56,67 +163,161 @@ func (m *Middleware) Handle(next goyave.Handler) goyave.Handler {
 			return
 		}
 
+		maxSize := m.maxUploadSize(r)
+		if r.Request().ContentLength > maxSize {
+			// Reject the request before reading anything so clients sending
+			// "Expect: 100-continue" don't send the body at all.
+			entityTooLarge(response)
+			return
+		}
+		r.Request().Body = http.MaxBytesReader(response, r.Body(), maxSize)
+
 		r.Data = nil
 		contentType := r.Header().Get("Content-Type")
-		if contentType != "" {
-			maxSize := int64(m.getMaxUploadSize() * 1024 * 1024)
-			maxValueBytes := maxSize
-			var bodyBuf bytes.Buffer
-			n, err := io.CopyN(&bodyBuf, r.Body(), maxValueBytes+1)
-			if err == nil || err == io.EOF {
-				maxValueBytes -= n
-				if maxValueBytes < 0 {
-					response.Status(http.StatusRequestEntityTooLarge)
-					return
-				}
-
-				bodyBytes := bodyBuf.Bytes()
-				if strings.HasPrefix(contentType, "application/json") {
-					var body any
-					if err := json.Unmarshal(bodyBytes, &body); err != nil {
-						response.Status(http.StatusBadRequest)
-						r.Extra[goyave.ExtraParseError{}] = fmt.Errorf("%w: %w", goyave.ErrInvalidJSONBody, err)
-					}
-					r.Data = body
-				} else {
-					req := r.Request()
-					req.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
-					r.Data, err = generateFlatMap(req, maxSize)
-					if err != nil {
-						response.Status(http.StatusBadRequest)
-						r.Extra[goyave.ExtraParseError{}] = fmt.Errorf("%w: %w", goyave.ErrInvalidContentForType, err)
-					}
-				}
-			} else {
-				response.Status(http.StatusBadRequest)
-				r.Extra[goyave.ExtraParseError{}] = fmt.Errorf("%w: %w", goyave.ErrErrorInRequestBody, err)
+		if contentType == "" {
+			next(response, r)
+			return
+		}
+
+		mediaType := parseMediaType(contentType)
+		var decoder Decoder
+		if mediaType != "application/x-www-form-urlencoded" && mediaType != "multipart/form-data" {
+			var ok bool
+			decoder, ok = m.decoderFor(mediaType)
+			if !ok {
+				response.Status(http.StatusUnsupportedMediaType)
+				return
+			}
+			if decoder == nil {
+				// Raw body, left to the handler
+				next(response, r)
+				return
 			}
 		}
 
-		if response.GetStatus() != http.StatusBadRequest {
+		if boundary, ok := multipartBoundary(contentType); ok && m.Streaming {
+			if form := m.parseStream(response, r, boundary, budget); form != nil {
+				defer m.removeForm(form)
+			}
+		} else {
+			m.parseBody(response, r, mediaType, decoder, maxSize, budget)
+		}
+
+		if status := response.GetStatus(); status != http.StatusBadRequest && status != http.StatusRequestEntityTooLarge {
 			next(response, r)
 		}
 	}
 }
 
-func (m *Middleware) getMaxUploadSize() float64 {
+// parseBody reads the whole body in memory and parses it. If the decoder is `nil`, the
+// body is parsed as a form.
+func (m *Middleware) parseBody(response *goyave.Response, r *goyave.Request, mediaType string, decoder Decoder, maxSize int64, budget *elementBudget) {
+	var bodyBuf bytes.Buffer
+	if _, err := io.Copy(&bodyBuf, r.Body()); err != nil {
+		if isTooLarge(err) {
+			entityTooLarge(response)
+			return
+		}
+		response.Status(http.StatusBadRequest)
+		r.Extra[goyave.ExtraParseError{}] = fmt.Errorf("%w: %w", goyave.ErrErrorInRequestBody, err)
+		return
+	}
+
+	var err error
+	if decoder == nil {
+		req := r.Request()
+		req.Body = io.NopCloser(&bodyBuf)
+		r.Data, err = m.generateFlatMap(req, maxSize, budget)
+		if err != nil {
+			response.Status(http.StatusBadRequest)
+			r.Extra[goyave.ExtraParseError{}] = fmt.Errorf("%w: %w", goyave.ErrInvalidContentForType, err)
+		}
+		return
+	}
+
+	r.Data, err = decoder(bodyBuf.Bytes())
+	if err != nil {
+		r.Data = nil
+		response.Status(http.StatusBadRequest)
+		parseErr := lo.Ternary(strings.HasSuffix(mediaType, "json"), goyave.ErrInvalidJSONBody, goyave.ErrInvalidContentForType)
+		r.Extra[goyave.ExtraParseError{}] = fmt.Errorf("%w: %w", parseErr, err)
+	}
+}
+
+// parseStream parses the multipart body of the request in streaming mode. Returns the parsed form
+// so its temporary files can be removed once the request is finished, or `nil` if the parsing failed.
+func (m *Middleware) parseStream(response *goyave.Response, r *goyave.Request, boundary string, budget *elementBudget) *multipart.Form {
+	body := &bodyReader{reader: r.Body()}
+	data, form, err := m.parseMultipartStream(body, boundary, budget)
+	switch {
+	case err == nil:
+		r.Data = data
+		return form
+	case isTooLarge(err):
+		entityTooLarge(response)
+	case body.err != nil:
+		response.Status(http.StatusBadRequest)
+		r.Extra[goyave.ExtraParseError{}] = fmt.Errorf("%w: %w", goyave.ErrErrorInRequestBody, body.err)
+	default:
+		response.Status(http.StatusBadRequest)
+		r.Extra[goyave.ExtraParseError{}] = fmt.Errorf("%w: %w", goyave.ErrInvalidContentForType, err)
+	}
+	return nil
+}
+
+func (m *Middleware) removeForm(form *multipart.Form) {
+	if err := form.RemoveAll(); err != nil {
+		m.Logger().Error(errors.New(err))
+	}
+}
+
+// maxUploadSize returns the maximum size of the request in bytes, taking
+// `MetaMaxUploadSize` of the matched route into account.
+func (m *Middleware) maxUploadSize(r *goyave.Request) int64 {
+	if r.Route == nil {
+		return m.getMaxUploadSize()
+	}
+	value, ok := r.Route.LookupMeta(MetaMaxUploadSize)
+	if !ok {
+		return m.getMaxUploadSize()
+	}
+	size, ok := value.(UploadSize)
+	if !ok {
+		panic(errors.Errorf("parse: invalid %q route meta type %T, expected parse.UploadSize", MetaMaxUploadSize, value))
+	}
+	return int64(size)
+}
+
+// entityTooLarge responds with "413 Request Entity Too Large". The connection is closed
+// after the response so the rest of the body doesn't need to be read.
+func entityTooLarge(response *goyave.Response) {
+	response.Header().Set("Connection", "close")
+	response.Status(http.StatusRequestEntityTooLarge)
+}
+
+// getMaxUploadSize returns the maximum size of the request in bytes.
+func (m *Middleware) getMaxUploadSize() int64 {
 	if m.MaxUploadSize == 0 {
-		return m.Config().GetFloat("server.maxUploadSize")
+		return m.Config().GetByteSize("server.maxUploadSize")
 	}
 
-	return m.MaxUploadSize
+	return int64(m.MaxUploadSize * 1024 * 1024)
 }
 
-func parseQuery(request *goyave.Request) error {
+func (m *Middleware) parseQuery(request *goyave.Request, budget *elementBudget) error {
 	queryParams, err := url.ParseQuery(request.URL().RawQuery)
-	if err == nil {
-		request.Query = make(map[string]any, len(queryParams))
-		flatten(request.Query, queryParams)
+	if err != nil {
+		return err
 	}
-	return err
+	query := make(map[string]any, len(queryParams))
+	if err := m.setValues(query, queryParams, budget); err != nil {
+		return err
+	}
+	request.Query = query
+	return nil
 }
 
-func generateFlatMap(request *http.Request, maxSize int64) (map[string]any, error) {
+func (m *Middleware) generateFlatMap(request *http.Request, maxSize int64, budget *elementBudget) (map[string]any, error) {
 	flatMap := make(map[string]any)
 	request.Form = url.Values{} // Prevent Form from being parsed because it would be redundant with our parsing
 	err := request.ParseMultipartForm(maxSize)
@@ -131,18 +332,24 @@ func generateFlatMap(request *http.Request, maxSize int64) (map[string]any, erro
 		}
 	}
 
-	if request.PostForm != nil {
-		flatten(flatMap, request.PostForm)
-	}
-	if request.MultipartForm != nil {
-		flatten(flatMap, request.MultipartForm.Value)
+	if request.MultipartForm == nil {
+		if err := m.setValues(flatMap, request.PostForm, budget); err != nil {
+			return nil, err
+		}
+	} else {
+		// The values of the multipart form are also copied to PostForm
+		if err := m.setValues(flatMap, request.MultipartForm.Value, budget); err != nil {
+			return nil, err
+		}
 
 		for field, headers := range request.MultipartForm.File {
 			files, err := fsutil.ParseMultipartFiles(headers)
 			if err != nil {
 				return nil, err
 			}
-			flatMap[field] = files
+			if err := m.setFiles(flatMap, field, files, budget); err != nil {
+				return nil, err
+			}
 		}
 	}
 
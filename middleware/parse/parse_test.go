@@ -2,9 +2,12 @@ package parse
 
 import (
 	"bytes"
+	"fmt"
+	"io"
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"strings"
 	"testing"
 
@@ -23,15 +26,15 @@ func TestParseMiddleware(t *testing.T) {
 	t.Run("Max Upload Size", func(t *testing.T) {
 		m := &Middleware{}
 		m.Init(server.Server)
-		assert.InEpsilon(t, 10.0, m.getMaxUploadSize(), 0) // Default
+		assert.Equal(t, int64(10<<20), m.getMaxUploadSize()) // Default
 		m.MaxUploadSize = 2.3
-		assert.InEpsilon(t, 2.3, m.getMaxUploadSize(), 0)
+		assert.Equal(t, int64(2411724), m.getMaxUploadSize()) // 2.3MiB
 
 		m = &Middleware{
 			MaxUploadSize: 2.3,
 		}
 		m.Init(server.Server)
-		assert.InEpsilon(t, 2.3, m.getMaxUploadSize(), 0)
+		assert.Equal(t, int64(2411724), m.getMaxUploadSize()) // 2.3MiB
 	})
 
 	t.Run("Parse Query", func(t *testing.T) {
@@ -146,6 +149,112 @@ func TestParseMiddleware(t *testing.T) {
 		}
 	})
 
+	t.Run("Decoders", func(t *testing.T) {
+		cases := []struct {
+			expected    any
+			desc        string
+			contentType string
+			body        []byte
+		}{
+			{
+				desc:        "xml",
+				contentType: "application/xml; charset=utf-8",
+				body:        []byte(`<user id="1"><name>John</name></user>`),
+				expected:    map[string]any{"@id": "1", "name": "John"},
+			},
+			{
+				desc:        "ndjson",
+				contentType: "application/x-ndjson",
+				body:        []byte("{\"a\":\"b\"}\n{\"a\":\"c\"}\n"),
+				expected:    []any{map[string]any{"a": "b"}, map[string]any{"a": "c"}},
+			},
+			{
+				desc:        "msgpack",
+				contentType: "application/msgpack",
+				body:        []byte{0x81, 0xa1, 'a', 0x92, 0x01, 0xa1, 'b'},
+				expected:    map[string]any{"a": []any{1.0, "b"}},
+			},
+			{
+				desc:        "cbor",
+				contentType: "application/cbor",
+				body:        []byte{0xa1, 0x61, 'a', 0x82, 0x01, 0x61, 'b'},
+				expected:    map[string]any{"a": []any{1.0, "b"}},
+			},
+			{
+				desc:        "structured_syntax_suffix",
+				contentType: "application/vnd.api+json",
+				body:        []byte(`{"data":null}`),
+				expected:    map[string]any{"data": nil},
+			},
+		}
+
+		for _, c := range cases {
+			t.Run(c.desc, func(t *testing.T) {
+				request := testutil.NewTestRequest(http.MethodPost, "/parse", bytes.NewReader(c.body))
+				request.Header().Set("Content-Type", c.contentType)
+
+				result := server.TestMiddleware(&Middleware{}, request, func(resp *goyave.Response, req *goyave.Request) {
+					assert.Equal(t, c.expected, req.Data)
+					resp.Status(http.StatusOK)
+				})
+
+				assert.NoError(t, result.Body.Close())
+				assert.Equal(t, http.StatusOK, result.StatusCode)
+			})
+		}
+	})
+
+	t.Run("Custom Decoder", func(t *testing.T) {
+		request := testutil.NewTestRequest(http.MethodPost, "/parse", strings.NewReader("a: b"))
+		request.Header().Set("Content-Type", "application/yaml")
+
+		m := &Middleware{
+			Decoders: map[string]Decoder{
+				"application/yaml": func(body []byte) (any, error) {
+					return map[string]any{"body": string(body)}, nil
+				},
+			},
+		}
+		result := server.TestMiddleware(m, request, func(resp *goyave.Response, req *goyave.Request) {
+			assert.Equal(t, map[string]any{"body": "a: b"}, req.Data)
+			resp.Status(http.StatusOK)
+		})
+
+		assert.NoError(t, result.Body.Close())
+		assert.Equal(t, http.StatusOK, result.StatusCode)
+	})
+
+	t.Run("Invalid Body", func(t *testing.T) {
+		request := testutil.NewTestRequest(http.MethodPost, "/parse", bytes.NewReader([]byte{0xc1}))
+		request.Lang = server.Lang.GetDefault()
+		request.Header().Set("Content-Type", "application/msgpack")
+
+		result := server.TestMiddleware(&Middleware{}, request, func(_ *goyave.Response, _ *goyave.Request) {
+			assert.Fail(t, "Middleware should not pass")
+		})
+
+		assert.NoError(t, result.Body.Close())
+		assert.Equal(t, http.StatusBadRequest, result.StatusCode)
+		assert.Nil(t, request.Data)
+		assert.NotPanics(t, func() {
+			extraError, ok := request.Extra[goyave.ExtraParseError{}].(error)
+			require.True(t, ok)
+			assert.ErrorIs(t, extraError, goyave.ErrInvalidContentForType)
+		})
+	})
+
+	t.Run("Unsupported Media Type", func(t *testing.T) {
+		request := testutil.NewTestRequest(http.MethodPost, "/parse", strings.NewReader("text"))
+		request.Header().Set("Content-Type", "text/plain")
+
+		result := server.TestMiddleware(&Middleware{}, request, func(_ *goyave.Response, _ *goyave.Request) {
+			assert.Fail(t, "Middleware should not pass")
+		})
+
+		assert.NoError(t, result.Body.Close())
+		assert.Equal(t, http.StatusUnsupportedMediaType, result.StatusCode)
+	})
+
 	t.Run("Multipart", func(t *testing.T) {
 		body := &bytes.Buffer{}
 		writer := multipart.NewWriter(body)
@@ -183,6 +292,76 @@ func TestParseMiddleware(t *testing.T) {
 		assert.Equal(t, http.StatusOK, result.StatusCode)
 	})
 
+	t.Run("Nested Keys", func(t *testing.T) {
+		for _, streaming := range []bool{false, true} {
+			t.Run(fmt.Sprintf("streaming_%t", streaming), func(t *testing.T) {
+				body := &bytes.Buffer{}
+				writer := multipart.NewWriter(body)
+				require.NoError(t, testutil.WriteMultipartFile(writer, &osfs.FS{}, "../../resources/img/logo/goyave_16.png", "user[picture]", "goyave_16.png"))
+				require.NoError(t, writer.WriteField("user[name]", "John"))
+				require.NoError(t, writer.WriteField("user.tags[]", "a"))
+				require.NoError(t, writer.WriteField("user.tags[]", "b"))
+				require.NoError(t, writer.Close())
+
+				request := testutil.NewTestRequest(http.MethodPost, "/parse?filter[name]=John&page=1", body)
+				request.Header().Set("Content-Type", writer.FormDataContentType())
+
+				m := &Middleware{NestedKeys: true, Streaming: streaming}
+				result := server.TestMiddleware(m, request, func(resp *goyave.Response, req *goyave.Request) {
+					assert.Equal(t, map[string]any{"filter": map[string]any{"name": "John"}, "page": "1"}, req.Query)
+
+					data, ok := req.Data.(map[string]any)
+					require.True(t, ok)
+					user, ok := data["user"].(map[string]any)
+					require.True(t, ok)
+					assert.Equal(t, "John", user["name"])
+					assert.Equal(t, []string{"a", "b"}, user["tags"])
+					picture, ok := user["picture"].([]fsutil.File)
+					require.True(t, ok)
+					require.Len(t, picture, 1)
+					assert.Equal(t, "goyave_16.png", picture[0].Header.Filename)
+					resp.Status(http.StatusOK)
+				})
+
+				assert.NoError(t, result.Body.Close())
+				assert.Equal(t, http.StatusOK, result.StatusCode)
+			})
+		}
+	})
+
+	t.Run("Nested Keys Errors", func(t *testing.T) {
+		cases := []struct {
+			expected error
+			desc     string
+			url      string
+			body     string
+		}{
+			{desc: "query", url: "/parse?a=1&a[b]=2", expected: goyave.ErrInvalidQuery},
+			{desc: "body", url: "/parse", body: "a[b][c]=1", expected: goyave.ErrInvalidContentForType},
+			{desc: "elements_shared_by_query_and_body", url: "/parse?a[9]=1", body: "b[9]=1", expected: goyave.ErrInvalidContentForType},
+		}
+
+		for _, c := range cases {
+			t.Run(c.desc, func(t *testing.T) {
+				request := testutil.NewTestRequest(http.MethodPost, c.url, strings.NewReader(c.body))
+				request.Lang = server.Lang.GetDefault()
+				request.Header().Set("Content-Type", "application/x-www-form-urlencoded")
+
+				result := server.TestMiddleware(&Middleware{NestedKeys: true, MaxDepth: 1, MaxElements: 15}, request, func(_ *goyave.Response, _ *goyave.Request) {
+					assert.Fail(t, "Middleware should not pass")
+				})
+
+				assert.NoError(t, result.Body.Close())
+				assert.Equal(t, http.StatusBadRequest, result.StatusCode)
+				assert.NotPanics(t, func() {
+					extraError, ok := request.Extra[goyave.ExtraParseError{}].(error)
+					require.True(t, ok)
+					assert.ErrorIs(t, extraError, c.expected)
+				})
+			})
+		}
+	})
+
 	t.Run("Invalid Multipart", func(t *testing.T) {
 		// Write empty body, which is not allowed for content multipart.
 		writer := multipart.NewWriter(nil)
@@ -205,6 +384,130 @@ func TestParseMiddleware(t *testing.T) {
 		})
 	})
 
+	t.Run("Multipart Streaming", func(t *testing.T) {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		require.NoError(t, testutil.WriteMultipartFile(writer, &osfs.FS{}, "../../resources/img/logo/goyave_16.png", "profile_picture", "goyave_16.png"))
+		require.NoError(t, testutil.WriteMultipartFile(writer, &osfs.FS{}, "../../resources/img/logo/goyave_16.png", "profile_picture", "goyave_16_2.png"))
+		require.NoError(t, writer.WriteField("email", "johndoe@example.org"))
+		require.NoError(t, writer.WriteField("tags", "a"))
+		require.NoError(t, writer.WriteField("tags", "b"))
+		require.NoError(t, writer.Close())
+
+		request := testutil.NewTestRequest(http.MethodPost, "/parse", body)
+		request.Header().Set("Content-Type", writer.FormDataContentType())
+
+		var picture []fsutil.File
+		m := &Middleware{Streaming: true, MaxMemory: 0.0001, MaxFileSize: 0.001, MaxFieldSize: 0.001}
+		result := server.TestMiddleware(m, request, func(resp *goyave.Response, req *goyave.Request) {
+			data, ok := req.Data.(map[string]any)
+			if !assert.True(t, ok) {
+				return
+			}
+			assert.Equal(t, "johndoe@example.org", data["email"])
+			assert.Equal(t, []string{"a", "b"}, data["tags"])
+
+			picture, ok = data["profile_picture"].([]fsutil.File)
+			if !assert.True(t, ok) || !assert.Len(t, picture, 2) {
+				return
+			}
+			assert.Equal(t, "image/png", picture[0].MIMEType)
+			assert.Equal(t, "goyave_16.png", picture[0].Header.Filename)
+			assert.Equal(t, int64(630), picture[0].Header.Size)
+			assert.Equal(t, "goyave_16_2.png", picture[1].Header.Filename)
+
+			f, err := picture[1].Header.Open()
+			require.NoError(t, err)
+			assert.NoError(t, f.Close())
+
+			resp.Status(http.StatusOK)
+		})
+
+		assert.NoError(t, result.Body.Close())
+		assert.Equal(t, http.StatusOK, result.StatusCode)
+
+		// The files exceed the memory threshold and are spooled to disk.
+		// Temporary files are removed when the request ends.
+		require.Len(t, picture, 2)
+		_, err := picture[1].Header.Open()
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("Multipart Streaming Memory", func(t *testing.T) {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		require.NoError(t, testutil.WriteMultipartFile(writer, &osfs.FS{}, "../../resources/img/logo/goyave_16.png", "profile_picture", "goyave_16.png"))
+		require.NoError(t, testutil.WriteMultipartFile(writer, &osfs.FS{}, "../../resources/img/logo/goyave_16.png", "profile_picture", "goyave_16_2.png"))
+		require.NoError(t, writer.Close())
+
+		request := testutil.NewTestRequest(http.MethodPost, "/parse", body)
+		request.Header().Set("Content-Type", writer.FormDataContentType())
+
+		var picture []fsutil.File
+		result := server.TestMiddleware(&Middleware{Streaming: true, MaxMemory: 0.001}, request, func(resp *goyave.Response, req *goyave.Request) {
+			picture, _ = req.Data.(map[string]any)["profile_picture"].([]fsutil.File)
+			resp.Status(http.StatusOK)
+		})
+		assert.NoError(t, result.Body.Close())
+		assert.Equal(t, http.StatusOK, result.StatusCode)
+
+		// Only the first file fits in memory, the second one is written to a temporary file.
+		require.Len(t, picture, 2)
+		f, err := picture[0].Header.Open()
+		require.NoError(t, err)
+		assert.NoError(t, f.Close())
+		_, err = picture[1].Header.Open()
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("Multipart Streaming Limits", func(t *testing.T) {
+		cases := []struct {
+			middleware *Middleware
+			desc       string
+			expected   int
+		}{
+			{desc: "file_too_large", middleware: &Middleware{Streaming: true, MaxFileSize: 0.0001}, expected: http.StatusRequestEntityTooLarge},
+			{desc: "field_too_large", middleware: &Middleware{Streaming: true, MaxFieldSize: 0.00001}, expected: http.StatusRequestEntityTooLarge},
+			{desc: "body_too_large", middleware: &Middleware{Streaming: true, MaxUploadSize: 0.0005}, expected: http.StatusRequestEntityTooLarge},
+			{desc: "within_limits", middleware: &Middleware{Streaming: true, MaxFileSize: 0.001, MaxFieldSize: 0.001}, expected: http.StatusOK},
+		}
+
+		for _, c := range cases {
+			t.Run(c.desc, func(t *testing.T) {
+				body := &bytes.Buffer{}
+				writer := multipart.NewWriter(body)
+				require.NoError(t, testutil.WriteMultipartFile(writer, &osfs.FS{}, "../../resources/img/logo/goyave_16.png", "profile_picture", "goyave_16.png"))
+				require.NoError(t, writer.WriteField("email", "johndoe@example.org"))
+				require.NoError(t, writer.Close())
+
+				request := testutil.NewTestRequest(http.MethodPost, "/parse", body)
+				request.Header().Set("Content-Type", writer.FormDataContentType())
+
+				result := server.TestMiddleware(c.middleware, request, func(resp *goyave.Response, _ *goyave.Request) {
+					resp.Status(http.StatusOK)
+				})
+				assert.NoError(t, result.Body.Close())
+				assert.Equal(t, c.expected, result.StatusCode)
+			})
+		}
+	})
+
+	t.Run("Invalid Multipart Streaming", func(t *testing.T) {
+		request := testutil.NewTestRequest(http.MethodPost, "/parse", strings.NewReader("--boundary\r\ninvalid"))
+		request.Lang = server.Lang.GetDefault()
+		request.Header().Set("Content-Type", "multipart/form-data; boundary=boundary")
+
+		result := server.TestMiddleware(&Middleware{Streaming: true}, request, func(resp *goyave.Response, _ *goyave.Request) {
+			resp.Status(http.StatusOK)
+		})
+
+		assert.NoError(t, result.Body.Close())
+		assert.Equal(t, http.StatusBadRequest, result.StatusCode)
+		extraError, ok := request.Extra[goyave.ExtraParseError{}].(error)
+		require.True(t, ok)
+		assert.ErrorIs(t, extraError, goyave.ErrInvalidContentForType)
+	})
+
 	t.Run("Error Reading Request Body", func(t *testing.T) {
 		// Create a test server that sends partial data
 		faultyRequest := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
@@ -292,4 +595,160 @@ func TestParseMiddleware(t *testing.T) {
 		})
 		assert.NoError(t, result.Body.Close())
 	})
+
+	t.Run("Route Max Upload Size Meta", func(t *testing.T) {
+		cases := []struct {
+			size     string
+			desc     string
+			expected UploadSize
+		}{
+			{desc: "bytes", size: "512", expected: 512},
+			{desc: "decimal", size: "10KB", expected: 10000},
+			{desc: "binary", size: "1.5MiB", expected: 1536 * 1024},
+		}
+		for _, c := range cases {
+			t.Run(c.desc, func(t *testing.T) {
+				route := goyave.NewRouter(server.Server).Post("/upload", nil).SetMeta(MetaMaxUploadSize, MaxUploadSize(c.size))
+				value, ok := route.LookupMeta(MetaMaxUploadSize)
+				assert.True(t, ok)
+				assert.Equal(t, c.expected, value)
+			})
+		}
+
+		for _, size := range []string{"", "10 potatoes", "-1"} {
+			assert.Panics(t, func() {
+				MaxUploadSize(size)
+			})
+		}
+	})
+
+	t.Run("Route Max Upload Size", func(t *testing.T) {
+		router := goyave.NewRouter(server.Server)
+		router.SetMeta(MetaMaxUploadSize, MaxUploadSize("1MiB"))
+		subrouter := router.Subrouter("/api")
+		inherited := subrouter.Post("/upload", nil)
+		overridden := subrouter.Post("/small", nil).SetMeta(MetaMaxUploadSize, UploadSize(1048))
+
+		m := &Middleware{MaxUploadSize: 0.01}
+		m.Init(server.Server)
+
+		request := testutil.NewTestRequest(http.MethodPost, "/api/upload", nil)
+		assert.Equal(t, int64(10485), m.maxUploadSize(request))
+		request.Route = inherited
+		assert.Equal(t, int64(1<<20), m.maxUploadSize(request))
+		request.Route = overridden
+		assert.Equal(t, int64(1048), m.maxUploadSize(request))
+
+		request.Route = &goyave.Route{Meta: map[string]any{MetaMaxUploadSize: 1.5}}
+		assert.Panics(t, func() {
+			m.maxUploadSize(request)
+		})
+
+		cases := []struct {
+			route    *goyave.Route
+			desc     string
+			expected int
+		}{
+			{desc: "inherited", route: inherited, expected: http.StatusOK},
+			{desc: "overridden", route: overridden, expected: http.StatusRequestEntityTooLarge},
+		}
+		for _, c := range cases {
+			t.Run(c.desc, func(t *testing.T) {
+				data := map[string]any{"a": strings.Repeat("a", 20*1024)}
+				request := testutil.NewTestRequest(http.MethodPost, "/parse", testutil.ToJSON(data))
+				request.Header().Set("Content-Type", "application/json")
+				request.Route = c.route
+
+				result := server.TestMiddleware(m, request, func(resp *goyave.Response, req *goyave.Request) {
+					assert.Equal(t, data, req.Data)
+					resp.Status(http.StatusOK)
+				})
+
+				assert.NoError(t, result.Body.Close())
+				assert.Equal(t, c.expected, result.StatusCode)
+			})
+		}
+	})
+
+	t.Run("Content-Length Pre-check", func(t *testing.T) {
+		body := &readSpy{Reader: strings.NewReader(strings.Repeat("a", 1024))}
+		request := testutil.NewTestRequest(http.MethodPost, "/parse", body)
+		request.Request().ContentLength = 1024
+		request.Header().Set("Content-Type", "application/json")
+		request.Header().Set("Expect", "100-continue")
+
+		result := server.TestMiddleware(&Middleware{MaxUploadSize: 0.0001}, request, func(_ *goyave.Response, _ *goyave.Request) {
+			assert.Fail(t, "Middleware should not pass")
+		})
+
+		assert.NoError(t, result.Body.Close())
+		assert.Equal(t, http.StatusRequestEntityTooLarge, result.StatusCode)
+		assert.Equal(t, "close", result.Header.Get("Connection"))
+		assert.False(t, body.read)
+	})
+
+	t.Run("Unknown Length Too Large", func(t *testing.T) {
+		data := map[string]any{"a": strings.Repeat("a", 1024)}
+		request := testutil.NewTestRequest(http.MethodPost, "/parse", testutil.ToJSON(data))
+		request.Request().ContentLength = -1
+		request.Header().Set("Content-Type", "application/json")
+
+		result := server.TestMiddleware(&Middleware{MaxUploadSize: 0.0001}, request, func(_ *goyave.Response, _ *goyave.Request) {
+			assert.Fail(t, "Middleware should not pass")
+		})
+
+		assert.NoError(t, result.Body.Close())
+		assert.Equal(t, http.StatusRequestEntityTooLarge, result.StatusCode)
+		assert.Equal(t, "close", result.Header.Get("Connection"))
+	})
+
+	t.Run("Raw Body", func(t *testing.T) {
+		cases := []struct {
+			desc        string
+			contentType string
+			body        string
+			tooLarge    bool
+		}{
+			{desc: "octet_stream", contentType: "application/octet-stream", body: "raw"},
+			{desc: "suffix", contentType: "application/offset+octet-stream", body: "raw"},
+			{desc: "no_content_type", body: "raw"},
+			{desc: "too_large", contentType: "application/octet-stream", body: strings.Repeat("a", 1024), tooLarge: true},
+		}
+
+		for _, c := range cases {
+			t.Run(c.desc, func(t *testing.T) {
+				request := testutil.NewTestRequest(http.MethodPost, "/parse", strings.NewReader(c.body))
+				request.Request().ContentLength = -1
+				if c.contentType != "" {
+					request.Header().Set("Content-Type", c.contentType)
+				}
+
+				result := server.TestMiddleware(&Middleware{MaxUploadSize: 0.0001}, request, func(resp *goyave.Response, req *goyave.Request) {
+					assert.Nil(t, req.Data)
+					body, err := io.ReadAll(req.Body())
+					if c.tooLarge {
+						var maxBytesErr *http.MaxBytesError
+						assert.ErrorAs(t, err, &maxBytesErr)
+					} else {
+						require.NoError(t, err)
+						assert.Equal(t, c.body, string(body))
+					}
+					resp.Status(http.StatusOK)
+				})
+
+				assert.NoError(t, result.Body.Close())
+				assert.Equal(t, http.StatusOK, result.StatusCode)
+			})
+		}
+	})
+}
+
+type readSpy struct {
+	io.Reader
+	read bool
+}
+
+func (r *readSpy) Read(p []byte) (int, error) {
+	r.read = true
+	return r.Reader.Read(p)
 }
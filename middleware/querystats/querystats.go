@@ -0,0 +1,47 @@
+package querystats
+
+import (
+	"goyave.dev/goyave/v5"
+	"goyave.dev/goyave/v5/database"
+)
+
+// Middleware records the database queries executed during each request.
+//
+// A new `*database.QueryStats` is injected into the request's context. Every query
+// executed with this context (`db.WithContext(request.Context())`) is counted and its
+// execution time accumulated. Identical statements executed repeatedly during the same
+// request (potential N+1 patterns) are logged by `database.Logger` once they reach the
+// `database.repeatedQueryThreshold` config entry, along with the name of the route
+// (or its full URI if the route is not named).
+//
+// The stats can be retrieved from the next handlers with `database.GetQueryStats(request.Context())`.
+//
+// If `LogSummary` is true, a summary (number of queries and cumulative execution time) is
+// logged at the DEBUG level at the end of each request that executed at least one query.
+type Middleware struct {
+	goyave.Component
+	LogSummary bool
+}
+
+// Handle implementation of `goyave.Middleware`.
+func (m *Middleware) Handle(next goyave.Handler) goyave.Handler {
+	return func(response *goyave.Response, request *goyave.Request) {
+		route := ""
+		if request.Route != nil {
+			route = request.Route.GetName()
+			if route == "" {
+				route = request.Route.GetFullURI()
+			}
+		}
+		stats := database.NewQueryStats(route)
+		request.WithContext(database.WithQueryStats(request.Context(), stats))
+
+		next(response, request)
+
+		if m.LogSummary {
+			if count := stats.Count(); count > 0 {
+				m.Logger().DebugContext(request.Context(), "database queries", "route", route, "count", count, "duration", stats.Duration())
+			}
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package querystats
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+
+	stdslog "log/slog"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"goyave.dev/goyave/v5"
+	"goyave.dev/goyave/v5/config"
+	"goyave.dev/goyave/v5/database"
+	"goyave.dev/goyave/v5/slog"
+	"goyave.dev/goyave/v5/util/testutil"
+
+	_ "goyave.dev/goyave/v5/database/dialect/sqlite"
+)
+
+type testModel struct {
+	Name string
+	ID   uint
+}
+
+func TestMiddleware(t *testing.T) {
+	cfg := config.LoadDefault()
+	cfg.Set("app.debug", true)
+	cfg.Set("database.connection", "sqlite3")
+	cfg.Set("database.name", "querystats_test.db")
+	cfg.Set("database.options", "mode=memory")
+	cfg.Set("database.repeatedQueryThreshold", 3)
+	buf := &bytes.Buffer{}
+	logger := slog.New(stdslog.NewJSONHandler(buf, &stdslog.HandlerOptions{Level: stdslog.LevelDebug}))
+	server := testutil.NewTestServerWithOptions(t, goyave.Options{Config: cfg, Logger: logger})
+	require.NoError(t, server.DB().AutoMigrate(&testModel{}))
+
+	t.Run("stats", func(t *testing.T) {
+		buf.Reset()
+		request := server.NewTestRequest(http.MethodGet, "/querystats", nil)
+		request.Route = server.Router().Get("/querystats", nil).Name("querystats")
+		executed := false
+		result := server.TestMiddleware(&Middleware{LogSummary: true}, request, func(response *goyave.Response, request *goyave.Request) {
+			executed = true
+			stats := database.GetQueryStats(request.Context())
+			require.NotNil(t, stats)
+			assert.Equal(t, "querystats", stats.Route)
+
+			db := server.DB().WithContext(request.Context())
+			for i := 1; i <= 4; i++ {
+				model := &testModel{}
+				db.Where("id", i).Find(model)
+			}
+			assert.Equal(t, 4, stats.Count())
+			assert.Positive(t, stats.Duration())
+			assert.Equal(t, map[string]int{"SELECT * FROM `test_models` WHERE `id` = ?": 4}, stats.Repeated())
+			response.Status(http.StatusNoContent)
+		})
+		assert.NoError(t, result.Body.Close())
+		assert.True(t, executed)
+
+		logs := buf.String()
+		assert.Equal(t, 1, bytes.Count(buf.Bytes(), []byte("REPEATED SQL (possible N+1) x3 in route \\\"querystats\\\"")), logs)
+		assert.Contains(t, logs, `"msg":"database queries","route":"querystats","count":4`)
+	})
+
+	t.Run("no_summary_without_queries", func(t *testing.T) {
+		buf.Reset()
+		request := server.NewTestRequest(http.MethodGet, "/querystats/unnamed", nil)
+		request.Route = server.Router().Get("/querystats/unnamed", nil)
+		result := server.TestMiddleware(&Middleware{LogSummary: true}, request, func(response *goyave.Response, request *goyave.Request) {
+			stats := database.GetQueryStats(request.Context())
+			require.NotNil(t, stats)
+			assert.Equal(t, "/querystats/unnamed", stats.Route)
+			response.Status(http.StatusNoContent)
+		})
+		assert.NoError(t, result.Body.Close())
+		assert.NotContains(t, buf.String(), "database queries")
+	})
+}
@@ -11,6 +11,7 @@ import (
 	"regexp"
 	"runtime"
 	"testing"
+	"time"
 
 	"github.com/samber/lo"
 	"github.com/stretchr/testify/assert"
@@ -18,6 +19,7 @@ import (
 	"goyave.dev/goyave/v5/config"
 	"goyave.dev/goyave/v5/cors"
 	"goyave.dev/goyave/v5/slog"
+	"goyave.dev/goyave/v5/util/fsutil/osfs"
 	"goyave.dev/goyave/v5/validation"
 
 	_ "goyave.dev/goyave/v5/database/dialect/sqlite"
@@ -27,7 +29,7 @@ type testCtxKey struct{}
 
 func TestMiddlewareHolder(t *testing.T) {
 	m1 := &recoveryMiddleware{}
-	m2 := &languageMiddleware{}
+	m2 := &LanguageMiddleware{}
 	holder := middlewareHolder{
 		middleware: []Middleware{m1, m2},
 	}
@@ -42,14 +44,14 @@ func TestHasMiddleware(t *testing.T) {
 		holder := []Middleware{m}
 
 		assert.Equal(t, m, findMiddleware[*recoveryMiddleware](holder))
-		assert.Nil(t, findMiddleware[*languageMiddleware](holder))
+		assert.Nil(t, findMiddleware[*LanguageMiddleware](holder))
 	})
 
 	t.Run("routeHasMiddleware", func(t *testing.T) {
 		route := &Route{
 			parent: &Router{
 				middlewareHolder: middlewareHolder{
-					middleware: []Middleware{&languageMiddleware{}},
+					middleware: []Middleware{&LanguageMiddleware{}},
 				},
 			},
 			middlewareHolder: middlewareHolder{
@@ -58,7 +60,7 @@ func TestHasMiddleware(t *testing.T) {
 		}
 
 		assert.True(t, routeHasMiddleware[*recoveryMiddleware](route))
-		assert.False(t, routeHasMiddleware[*languageMiddleware](route))
+		assert.False(t, routeHasMiddleware[*LanguageMiddleware](route))
 	})
 
 	t.Run("routerHasMiddleware", func(t *testing.T) {
@@ -68,7 +70,7 @@ func TestHasMiddleware(t *testing.T) {
 					middleware: []Middleware{&testMiddleware{}},
 				},
 				middlewareHolder: middlewareHolder{
-					middleware: []Middleware{&languageMiddleware{}},
+					middleware: []Middleware{&LanguageMiddleware{}},
 				},
 			},
 			globalMiddleware: &middlewareHolder{},
@@ -78,7 +80,7 @@ func TestHasMiddleware(t *testing.T) {
 		}
 
 		assert.True(t, routerHasMiddleware[*recoveryMiddleware](router))
-		assert.True(t, routerHasMiddleware[*languageMiddleware](router))
+		assert.True(t, routerHasMiddleware[*LanguageMiddleware](router))
 		assert.True(t, routerHasMiddleware[*testMiddleware](router))
 		assert.False(t, routerHasMiddleware[*corsMiddleware](router))
 	})
@@ -210,7 +212,7 @@ func TestRecoveryMiddleware(t *testing.T) {
 func TestLanguageMiddleware(t *testing.T) {
 	server, err := New(Options{Config: config.LoadDefault()})
 	require.NoError(t, err)
-	middleware := &languageMiddleware{}
+	middleware := &LanguageMiddleware{}
 	middleware.Init(server)
 
 	cases := []struct {
@@ -242,6 +244,112 @@ func TestLanguageMiddleware(t *testing.T) {
 			assert.True(t, executed)
 		})
 	}
+
+	t.Run("resolvers", func(t *testing.T) {
+		server, err := New(Options{Config: config.LoadDefault()})
+		require.NoError(t, err)
+		require.NoError(t, server.Lang.Load(&osfs.FS{}, "fr-FR", "resources/lang/en-US"))
+		require.NoError(t, server.Lang.Load(&osfs.FS{}, "de-DE", "resources/lang/en-US"))
+		middleware := &LanguageMiddleware{
+			Resolvers: []LanguageResolver{
+				QueryLanguage{},
+				RouteLanguage{},
+				&CookieLanguage{Persist: true, MaxAge: time.Hour},
+				HeaderLanguage{},
+			},
+		}
+		middleware.Init(server)
+
+		cases := []struct {
+			routeParams  map[string]string
+			cookie       *http.Cookie
+			desc         string
+			query        string
+			header       string
+			expected     string
+			expectCookie bool
+		}{
+			{desc: "none", expected: "en-US"},
+			{desc: "header", header: "de-DE", expected: "de-DE"},
+			{desc: "header_unavailable", header: "it-IT", expected: "en-US"},
+			{desc: "cookie", header: "de-DE", cookie: &http.Cookie{Name: "lang", Value: "fr-FR"}, expected: "fr-FR"},
+			{desc: "cookie_unavailable", header: "de-DE", cookie: &http.Cookie{Name: "lang", Value: "it-IT"}, expected: "de-DE"},
+			{desc: "route", routeParams: map[string]string{"lang": "fr"}, cookie: &http.Cookie{Name: "lang", Value: "de-DE"}, expected: "fr-FR", expectCookie: true},
+			{desc: "query", query: "?lang=de", routeParams: map[string]string{"lang": "fr"}, expected: "de-DE", expectCookie: true},
+			{desc: "query_unavailable", query: "?lang=it", routeParams: map[string]string{"lang": "fr"}, expected: "fr-FR", expectCookie: true},
+			{desc: "query_same_as_cookie", query: "?lang=fr-FR", cookie: &http.Cookie{Name: "lang", Value: "fr-FR"}, expected: "fr-FR"},
+		}
+
+		for _, c := range cases {
+			t.Run(c.desc, func(t *testing.T) {
+				handler := middleware.Handle(func(_ *Response, req *Request) {
+					assert.Equal(t, c.expected, req.Lang.Name())
+				})
+
+				request := NewRequest(httptest.NewRequest(http.MethodGet, "/test"+c.query, nil))
+				request.RouteParams = c.routeParams
+				if c.header != "" {
+					request.Header().Set("Accept-Language", c.header)
+				}
+				if c.cookie != nil {
+					request.Request().AddCookie(c.cookie)
+				}
+				recorder := httptest.NewRecorder()
+				response := NewResponse(server, request, recorder)
+
+				handler(response, request)
+
+				assert.Equal(t, c.expected, recorder.Header().Get("Content-Language"))
+				assert.Equal(t, []string{"Cookie", "Accept-Language"}, recorder.Header().Values("Vary"))
+				cookies := recorder.Result().Cookies()
+				if c.expectCookie {
+					require.Len(t, cookies, 1)
+					assert.Equal(t, "lang", cookies[0].Name)
+					assert.Equal(t, c.expected, cookies[0].Value)
+					assert.Equal(t, "/", cookies[0].Path)
+					assert.Equal(t, 3600, cookies[0].MaxAge)
+				} else {
+					assert.Empty(t, cookies)
+				}
+			})
+		}
+	})
+
+	t.Run("user", func(t *testing.T) {
+		server, err := New(Options{Config: config.LoadDefault()})
+		require.NoError(t, err)
+		require.NoError(t, server.Lang.Load(&osfs.FS{}, "fr-FR", "resources/lang/en-US"))
+		middleware := &LanguageMiddleware{Resolvers: []LanguageResolver{UserLanguage{}, HeaderLanguage{}}}
+		middleware.Init(server)
+
+		handler := middleware.Handle(func(_ *Response, _ *Request) {})
+
+		request := NewRequest(httptest.NewRequest(http.MethodGet, "/test", nil))
+		request.User = &testLanguageUser{lang: "fr-FR"}
+		request.Header().Set("Accept-Language", "en-US")
+		recorder := httptest.NewRecorder()
+		response := NewResponse(server, request, recorder)
+		recorder.Header().Set("Vary", "Origin, accept-language")
+		handler(response, request)
+		assert.Equal(t, "fr-FR", request.Lang.Name())
+		assert.Equal(t, []string{"Origin, accept-language"}, recorder.Header().Values("Vary"))
+
+		request.User = &testLanguageUser{}
+		handler(response, request)
+		assert.Equal(t, "en-US", request.Lang.Name())
+
+		request.User = "not a preferrer"
+		handler(response, request)
+		assert.Equal(t, "en-US", request.Lang.Name())
+	})
+}
+
+type testLanguageUser struct {
+	lang string
+}
+
+func (u *testLanguageUser) PreferredLanguage() string {
+	return u.lang
 }
 
 type testValidator struct {
@@ -320,6 +428,7 @@ func TestValidateMiddleware(t *testing.T) {
 				return validation.RuleSet{{Path: "param", Rules: validation.List{validation.Required(), &testValidator{
 					validateFunc: func(v *testValidator, ctx *validation.Context) bool {
 						assert.Equal(t, request, ctx.Extra[validation.ExtraRequest{}])
+						assert.Equal(t, request.RouteParams, ctx.Extra[validation.ExtraRouteParams{}])
 						assert.NotNil(t, v.Config())
 						assert.NotNil(t, v.DB())
 						assert.NotNil(t, v.Logger())
@@ -396,6 +505,7 @@ func TestValidateMiddleware(t *testing.T) {
 				return validation.RuleSet{{Path: "param", Rules: validation.List{validation.Required(), &testValidator{
 					validateFunc: func(v *testValidator, ctx *validation.Context) bool {
 						assert.Equal(t, request, ctx.Extra[validation.ExtraRequest{}])
+						assert.Equal(t, request.RouteParams, ctx.Extra[validation.ExtraRouteParams{}])
 						assert.NotNil(t, v.Config())
 						assert.NotNil(t, v.DB())
 						assert.NotNil(t, v.Logger())
@@ -10,6 +10,7 @@ import (
 	"sync"
 	"time"
 
+	"goyave.dev/goyave/v5/database"
 	"goyave.dev/goyave/v5/lang"
 )
 
@@ -191,6 +192,20 @@ func (r *Request) BearerToken() (string, bool) {
 	return strings.TrimSpace(header[len(schema):]), true
 }
 
+// IfMatchVersion extract the record version from the "If-Match" header, as
+// returned by `database.Version.ETag()`. This version can be assigned to the model
+// before updating it so the update is rejected with a `*database.ConflictError` if the
+// record has been modified since the client fetched it.
+// Returns false if the header is missing, contains more than one entity-tag or if the
+// entity-tag is not a valid version.
+func (r *Request) IfMatchVersion() (database.Version, bool) {
+	header := r.Header().Get("If-Match")
+	if strings.Contains(header, ",") {
+		return 0, false
+	}
+	return database.ParseVersionETag(header)
+}
+
 // Body the request body.
 // Always non-nil, but will return EOF immediately when no body is present.
 // The server will close the request body so handlers don't need to.
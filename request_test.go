@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"goyave.dev/goyave/v5/database"
 )
 
 type requestTestKey struct{}
@@ -75,6 +76,35 @@ func TestRequest(t *testing.T) {
 		assert.False(t, ok)
 	})
 
+	t.Run("IfMatchVersion", func(t *testing.T) {
+		cases := []struct {
+			header string
+			want   database.Version
+			wantOK bool
+		}{
+			{header: `"3"`, want: 3, wantOK: true},
+			{header: ` "12" `, want: 12, wantOK: true},
+			{header: "", want: 0, wantOK: false},
+			{header: "*", want: 0, wantOK: false},
+			{header: `W/"3"`, want: 0, wantOK: false},
+			{header: `"3", "4"`, want: 0, wantOK: false},
+			{header: `"abc"`, want: 0, wantOK: false},
+			{header: `"0"`, want: 0, wantOK: false},
+		}
+
+		for _, c := range cases {
+			t.Run(c.header, func(t *testing.T) {
+				httpReq := httptest.NewRequest(http.MethodPatch, "/test", nil)
+				if c.header != "" {
+					httpReq.Header.Set("If-Match", c.header)
+				}
+				version, ok := NewRequest(httpReq).IfMatchVersion()
+				assert.Equal(t, c.want, version)
+				assert.Equal(t, c.wantOK, ok)
+			})
+		}
+	})
+
 	t.Run("Context", func(t *testing.T) {
 		httpReq := httptest.NewRequest(http.MethodGet, "/test", nil)
 		r := NewRequest(httpReq)
@@ -13,6 +13,7 @@ import (
 	"sync"
 
 	"gorm.io/gorm"
+	"goyave.dev/goyave/v5/database"
 	errorutil "goyave.dev/goyave/v5/util/errors"
 	"goyave.dev/goyave/v5/util/fsutil"
 )
@@ -416,7 +417,8 @@ func (r *Response) error(err any) {
 }
 
 // WriteDBError takes an error and automatically writes HTTP status code 404 Not Found
-// if the error is a `gorm.ErrRecordNotFound` error.
+// if the error is a `gorm.ErrRecordNotFound` error, or 409 Conflict if the error is
+// a `*database.ConflictError` (optimistic locking).
 // Calls `Response.Error()` if there is another type of error.
 //
 // Returns true if there is an error. You can then safely `return` in you controller.
@@ -431,9 +433,13 @@ func (r *Response) error(err any) {
 //	}
 func (r *Response) WriteDBError(err error) bool {
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
+		var conflictErr *database.ConflictError
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
 			r.Status(http.StatusNotFound)
-		} else {
+		case errors.As(err, &conflictErr):
+			r.Status(http.StatusConflict)
+		default:
 			r.Error(errorutil.NewSkip(err, 3))
 		}
 		return true
@@ -17,6 +17,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"gorm.io/gorm"
 	"goyave.dev/goyave/v5/config"
+	"goyave.dev/goyave/v5/database"
 	"goyave.dev/goyave/v5/slog"
 	errorutil "goyave.dev/goyave/v5/util/errors"
 	"goyave.dev/goyave/v5/util/fsutil/osfs"
@@ -522,6 +523,12 @@ func TestResponse(t *testing.T) {
 			assert.Equal(t, http.StatusNotFound, resp.status)
 		})
 
+		t.Run("ConflictError", func(t *testing.T) {
+			resp, _ := newTestReponse()
+			assert.True(t, resp.WriteDBError(fmt.Errorf("%w", &database.ConflictError{Table: "users", Version: 2})))
+			assert.Equal(t, http.StatusConflict, resp.status)
+		})
+
 		t.Run("DBError", func(t *testing.T) {
 			resp, recorder := newTestReponse()
 			logBuffer := &bytes.Buffer{}
@@ -3,6 +3,7 @@ package goyave
 import (
 	"fmt"
 	"net/http"
+	"slices"
 	"strings"
 
 	"github.com/samber/lo"
@@ -192,6 +193,8 @@ func (r *Route) Middleware(middleware ...Middleware) *Route {
 }
 
 // BuildURL build a full URL pointing to this route.
+// The default language is used for the `LanguageParameter` route parameter
+// if it is omitted (see `BuildURI()`).
 // Panics if the amount of parameters doesn't match the amount of
 // actual parameters for this route.
 func (r *Route) BuildURL(parameters ...string) string {
@@ -205,13 +208,42 @@ func (r *Route) BuildProxyURL(parameters ...string) string {
 	return r.parent.server.ProxyBaseURL() + r.BuildURI(parameters...)
 }
 
+// BuildURLForLanguage build a full URL pointing to this route, for the given language.
+// The language is used as the value of the route parameter named `LanguageParameter`
+// ("/{lang}/products"), and must not be part of the given parameters.
+// If the route doesn't have such parameter, the language is ignored.
+// Panics if the amount of parameters doesn't match the amount of
+// actual parameters for this route.
+func (r *Route) BuildURLForLanguage(language string, parameters ...string) string {
+	return r.parent.server.BaseURL() + r.BuildURIForLanguage(language, parameters...)
+}
+
+// BuildURIForLanguage build a full URI pointing to this route, for the given language.
+// See `BuildURLForLanguage()`.
+func (r *Route) BuildURIForLanguage(language string, parameters ...string) string {
+	_, fullParameters := r.GetFullURIAndParameters()
+	i := slices.Index(fullParameters, LanguageParameter)
+	if i == -1 || i > len(parameters) {
+		return r.BuildURI(parameters...)
+	}
+	return r.BuildURI(slices.Insert(slices.Clone(parameters), i, language)...)
+}
+
 // BuildURI build a full URI pointing to this route. The returned
 // string doesn't include the protocol and domain. (e.g. "/user/login")
+// If the route has a parameter named `LanguageParameter` ("/{lang}/products") and
+// it is the only one missing from the given parameters, the default language is used.
 // Panics if the amount of parameters doesn't match the amount of
 // actual parameters for this route.
 func (r *Route) BuildURI(parameters ...string) string {
 	fullURI, fullParameters := r.GetFullURIAndParameters()
 
+	if len(parameters) == len(fullParameters)-1 {
+		if i := slices.Index(fullParameters, LanguageParameter); i != -1 {
+			parameters = slices.Insert(slices.Clone(parameters), i, r.parent.server.Lang.Default)
+		}
+	}
+
 	if len(parameters) != len(fullParameters) {
 		panic(errors.Errorf("BuildURI: route has %d parameters, %d given", len(fullParameters), len(parameters)))
 	}
@@ -185,7 +185,7 @@ func TestRoute(t *testing.T) {
 			},
 		}
 
-		route.Middleware(&recoveryMiddleware{}, &languageMiddleware{})
+		route.Middleware(&recoveryMiddleware{}, &LanguageMiddleware{})
 		assert.Len(t, route.middleware, 2)
 		for _, m := range route.middleware {
 			assert.NotNil(t, m.Server())
@@ -226,6 +226,41 @@ func TestRoute(t *testing.T) {
 		assert.Equal(t, "http://127.0.0.1:8080/product/123/keyboard/accessories", uri)
 	})
 
+	t.Run("BuildURLForLanguage", func(t *testing.T) {
+		router := prepareRouteTest()
+		subrouter := router.Subrouter("/{lang}").Subrouter("/product/{id:[0-9+]}")
+		route := subrouter.Route([]string{http.MethodGet}, "/{name}/accessories", nil)
+
+		assert.Equal(t, "/fr/product/123/keyboard/accessories", route.BuildURIForLanguage("fr", "123", "keyboard"))
+		assert.Equal(t, "http://127.0.0.1:8080/en-US/product/123/keyboard/accessories", route.BuildURLForLanguage("en-US", "123", "keyboard"))
+
+		route = router.Route([]string{http.MethodGet}, "/product/{id}/{lang}", nil)
+		assert.Equal(t, "/product/123/fr", route.BuildURIForLanguage("fr", "123"))
+
+		route = router.Route([]string{http.MethodGet}, "/product/{id}", nil)
+		assert.Equal(t, "/product/123", route.BuildURIForLanguage("fr", "123"))
+
+		t.Run("default_language", func(t *testing.T) {
+			route := subrouter.Route([]string{http.MethodGet}, "/{name}", nil)
+			assert.Equal(t, "/en-US/product/123/keyboard", route.BuildURI("123", "keyboard"))
+			assert.Equal(t, "http://127.0.0.1:8080/en-US/product/123/keyboard", route.BuildURL("123", "keyboard"))
+			assert.Equal(t, "/de/product/123/keyboard", route.BuildURI("de", "123", "keyboard"))
+
+			route = router.Route([]string{http.MethodGet}, "/product/{id}/{lang}", nil)
+			assert.Equal(t, "/product/123/en-US", route.BuildURI("123"))
+		})
+
+		t.Run("invalid_param_count", func(t *testing.T) {
+			route := subrouter.Route([]string{http.MethodGet}, "/{name}", nil)
+			assert.Panics(t, func() {
+				route.BuildURIForLanguage("fr")
+			})
+			assert.Panics(t, func() {
+				route.BuildURIForLanguage("fr", "123", "keyboard", "other")
+			})
+		})
+	})
+
 	t.Run("BuildProxyURL", func(t *testing.T) {
 		router := prepareRouteTest()
 		subrouter := router.Subrouter("/product").Subrouter("/{id:[0-9+]}")
@@ -132,7 +132,7 @@ func NewRouter(server *Server) *Router {
 		router.StatusHandler(&ErrorStatusHandler{}, i)
 	}
 	router.StatusHandler(&ErrorStatusHandler{}, http.StatusNotExtended, http.StatusNetworkAuthenticationRequired)
-	router.GlobalMiddleware(&recoveryMiddleware{}, &languageMiddleware{})
+	router.GlobalMiddleware(&recoveryMiddleware{}, &LanguageMiddleware{})
 	return router
 }
 
@@ -231,6 +231,15 @@ func (r *Router) Middleware(middleware ...Middleware) *Router {
 	return r
 }
 
+// LanguageResolvers set the chain of resolvers used by the global `LanguageMiddleware`
+// to determine the language of the requests. See `LanguageMiddleware` for more details.
+func (r *Router) LanguageResolvers(resolvers ...LanguageResolver) *Router {
+	if m := findMiddleware[*LanguageMiddleware](r.globalMiddleware.middleware); m != nil {
+		m.Resolvers = resolvers
+	}
+	return r
+}
+
 // CORS set the CORS options for this route group.
 // If the options are not `nil`, the CORS middleware is automatically added globally.
 // To disable CORS for this router, subrouters and routes, give `nil` options.
@@ -85,7 +85,7 @@ func TestRouter(t *testing.T) {
 		assert.NotNil(t, router.Meta)
 
 		recoveryMiddleware := findMiddleware[*recoveryMiddleware](router.globalMiddleware.middleware)
-		langMiddleware := findMiddleware[*languageMiddleware](router.globalMiddleware.middleware)
+		langMiddleware := findMiddleware[*LanguageMiddleware](router.globalMiddleware.middleware)
 		if assert.NotNil(t, recoveryMiddleware) {
 			assert.Equal(t, router.server, recoveryMiddleware.server)
 		}
@@ -163,6 +163,14 @@ func TestRouter(t *testing.T) {
 		}
 	})
 
+	t.Run("LanguageResolvers", func(t *testing.T) {
+		router := prepareRouterTest()
+		router.LanguageResolvers(QueryLanguage{}, HeaderLanguage{})
+		m := findMiddleware[*LanguageMiddleware](router.globalMiddleware.middleware)
+		require.NotNil(t, m)
+		assert.Equal(t, []LanguageResolver{QueryLanguage{}, HeaderLanguage{}}, m.Resolvers)
+	})
+
 	t.Run("CORS", func(t *testing.T) {
 		router := prepareRouterTest()
 		opts := cors.Default()
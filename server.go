@@ -148,10 +148,10 @@ func New(opts Options) (*Server, error) {
 	server := &Server{
 		server: &http.Server{
 			Addr:              host,
-			WriteTimeout:      time.Duration(cfg.GetInt("server.writeTimeout")) * time.Second,
-			ReadTimeout:       time.Duration(cfg.GetInt("server.readTimeout")) * time.Second,
-			ReadHeaderTimeout: time.Duration(cfg.GetInt("server.readHeaderTimeout")) * time.Second,
-			IdleTimeout:       time.Duration(cfg.GetInt("server.idleTimeout")) * time.Second,
+			WriteTimeout:      cfg.GetDuration("server.writeTimeout"),
+			ReadTimeout:       cfg.GetDuration("server.readTimeout"),
+			ReadHeaderTimeout: cfg.GetDuration("server.readHeaderTimeout"),
+			IdleTimeout:       cfg.GetDuration("server.idleTimeout"),
 			ConnState:         opts.ConnState,
 			ConnContext:       opts.ConnContext,
 			MaxHeaderBytes:    opts.MaxHeaderBytes,
@@ -494,24 +494,55 @@ func (s *Server) Stop() {
 	<-s.stopChannel // Wait for stop channel before returning
 }
 
-// RegisterSignalHook creates a channel listening on SIGINT and SIGTERM. When receiving such
-// signal, the server is stopped automatically and the listener on these signals is removed.
+// RegisterSignalHook creates a channel listening on SIGINT, SIGTERM and SIGHUP. When receiving
+// SIGINT or SIGTERM, the server is stopped automatically and the listener on these signals is removed.
+// When receiving SIGHUP, the configuration is reloaded (see `config.Config.Reload()`).
 func (s *Server) RegisterSignalHook() {
 	// Sometimes users may not want to have a sigChannel setup
 	// also we don't want it in tests
 	// users will have to manually call this function if they want the shutdown on signal feature
 
 	s.sigChannel = make(chan os.Signal, 64)
-	signal.Notify(s.sigChannel, syscall.SIGINT, syscall.SIGTERM)
+	signal.Notify(s.sigChannel, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
 
 	go func() {
-		_, ok := <-s.sigChannel
-		if ok {
+		for sig := range s.sigChannel {
+			if sig == syscall.SIGHUP {
+				s.ReloadConfig()
+				continue
+			}
 			s.Stop()
+			return
 		}
 	}()
 }
 
+// ReloadConfig reloads the server's configuration and logs the result. The server
+// keeps using its current configuration if the new one is invalid or if it changes
+// entries requiring a restart. See `config.Config.Reload()`.
+func (s *Server) ReloadConfig() {
+	s.logConfigReload(s.config.Reload())
+}
+
+// WatchConfig watches the config files for changes every `interval` and reloads the server's
+// configuration automatically. The watcher is stopped when the server shuts down.
+// See `config.Config.Watch()`.
+func (s *Server) WatchConfig(interval time.Duration) {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.RegisterShutdownHook(func(_ *Server) {
+		cancel()
+	})
+	go s.config.Watch(ctx, interval, s.logConfigReload)
+}
+
+func (s *Server) logConfigReload(changed []string, err error) {
+	if err != nil {
+		s.Logger.Error(errors.New(err))
+		return
+	}
+	s.Logger.Info("Configuration reloaded", "changed", changed)
+}
+
 // errLogWriter is a proxy io.Writer that pipes into the server logger.
 // This is used so the error logger (type `*log.Logger`) of the underlying
 // std HTTP server write to the same logger as the rest of the application.
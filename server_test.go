@@ -519,6 +519,75 @@ func TestServer(t *testing.T) {
 		assert.False(t, server.IsReady())
 	})
 
+	t.Run("ReloadConfig", func(t *testing.T) {
+		cfg, err := config.LoadJSON(`{"app": {"debug": false}}`)
+		require.NoError(t, err)
+		buf := &bytes.Buffer{}
+		server, err := New(Options{Config: cfg, Logger: slog.New(slog.NewHandler(false, buf))})
+		require.NoError(t, err)
+
+		server.ReloadConfig()
+		assert.Contains(t, buf.String(), `"msg":"Configuration reloaded"`)
+		buf.Reset()
+
+		server.Config().Set("server.port", 1234)
+		server.ReloadConfig()
+		assert.Contains(t, buf.String(), `changing \"server.port\" requires a restart`)
+		assert.Equal(t, 1234, server.Config().GetInt("server.port"))
+	})
+
+	t.Run("WatchConfig", func(t *testing.T) {
+		server, err := New(Options{Config: config.LoadDefault(), Logger: slog.New(slog.NewHandler(false, &bytes.Buffer{}))})
+		require.NoError(t, err)
+		server.WatchConfig(time.Millisecond)
+		require.Len(t, server.shutdownHooks, 1)
+		server.shutdownHooks[0](server) // Stops the watcher
+	})
+
+	t.Run("SignalHook_SIGHUP", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("Testing on a windows machine. Cannot test proc signals")
+		}
+		cfg, err := config.LoadJSON(`{"server": {"port": 0}}`)
+		require.NoError(t, err)
+		server, err := New(Options{Config: cfg, Logger: slog.New(slog.NewHandler(false, &bytes.Buffer{}))})
+		require.NoError(t, err)
+
+		reloaded := make(chan struct{}, 1)
+		server.Config().Subscribe(func(_ []string) {
+			reloaded <- struct{}{}
+		})
+		server.Config().Set("app.name", "changed")
+		server.RegisterSignalHook()
+
+		proc, err := os.FindProcess(os.Getpid())
+		require.NoError(t, err)
+		wg := sync.WaitGroup{}
+		wg.Add(2)
+
+		server.RegisterStartupHook(func(_ *Server) {
+			assert.NoError(t, proc.Signal(syscall.SIGHUP))
+			select {
+			case <-reloaded:
+			case <-time.After(2 * time.Second):
+				assert.Fail(t, "config not reloaded")
+			}
+			assert.Equal(t, "goyave", server.Config().GetString("app.name"))
+			assert.True(t, server.IsReady()) // SIGHUP doesn't stop the server
+			server.Stop()
+			wg.Done()
+		})
+
+		go func() {
+			err := server.Start()
+			assert.NoError(t, err)
+			wg.Done()
+		}()
+
+		wg.Wait()
+		assert.False(t, server.IsReady())
+	})
+
 	t.Run("Context", func(t *testing.T) {
 		type baseContextKey struct{}
 		type connContextKey struct{}
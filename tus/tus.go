@@ -0,0 +1,587 @@
+package tus
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	stderrors "errors"
+	"hash"
+	"io"
+	"io/fs"
+	"math"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+	"gorm.io/gorm"
+	"goyave.dev/goyave/v5"
+	"goyave.dev/goyave/v5/middleware/parse"
+	"goyave.dev/goyave/v5/util/errors"
+	"goyave.dev/goyave/v5/util/fsutil"
+	"goyave.dev/goyave/v5/validation"
+)
+
+// Version the supported version of the tus protocol.
+const Version = "1.0.0"
+
+// Protocol headers.
+const (
+	HeaderResumable         = "Tus-Resumable"
+	HeaderVersion           = "Tus-Version"
+	HeaderExtension         = "Tus-Extension"
+	HeaderMaxSize           = "Tus-Max-Size"
+	HeaderChecksumAlgorithm = "Tus-Checksum-Algorithm"
+	HeaderUploadOffset      = "Upload-Offset"
+	HeaderUploadLength      = "Upload-Length"
+	HeaderUploadMetadata    = "Upload-Metadata"
+	HeaderUploadExpires     = "Upload-Expires"
+	HeaderUploadChecksum    = "Upload-Checksum"
+)
+
+// ContentType the content type of the requests sending upload chunks.
+const ContentType = "application/offset+octet-stream"
+
+// StatusChecksumMismatch the status returned when the checksum of a chunk
+// doesn't match the "Upload-Checksum" header.
+const StatusChecksumMismatch = 460
+
+const (
+	defaultExpiration      = 24 * time.Hour
+	defaultCleanupInterval = time.Hour
+)
+
+func init() {
+	// The chunks are raw request bodies, read by the controller
+	parse.RegisterDecoder(ContentType, nil)
+}
+
+var checksumAlgorithms = map[string]func() hash.Hash{
+	"md5":    md5.New,
+	"sha1":   sha1.New,
+	"sha256": sha256.New,
+}
+
+// Controller implementation of the tus resumable upload protocol (https://tus.io), version 1.0.0,
+// with the "creation", "creation-with-upload", "expiration", "checksum" and "termination" extensions.
+//
+// The following routes are registered:
+//   - `OPTIONS /`: the server capabilities
+//   - `POST /`: create an upload
+//   - `HEAD /{uploadID}`: get the offset of an upload
+//   - `PATCH /{uploadID}`: send a chunk
+//   - `DELETE /{uploadID}`: terminate an upload
+//
+// Register the controller on a subrouter, for example:
+//
+//	router.Subrouter("/uploads").Controller(tus.NewController(&osfs.FS{}, "storage/uploads"))
+//
+// The chunks are sent as raw request bodies: the parse middleware leaves them to the controller.
+// `RegisterRoutes()` sets the `parse.MetaMaxUploadSize` meta on the router so the body size
+// limit of the parse middleware matches `MaxSize`.
+//
+// Incomplete uploads expire after `Expiration`. Expired uploads are removed periodically
+// while the server is running and when the server shuts down.
+type Controller struct {
+	goyave.Component
+
+	// FS the file system the uploads are stored in.
+	FS FS
+
+	// MetadataRules optional validation rules applied to the upload metadata when the upload is created.
+	// If the validation fails, "422 Unprocessable Entity" is returned and the upload is not created.
+	MetadataRules goyave.RuleSetFunc
+
+	// OnComplete optional hook executed when the last chunk of an upload has been received,
+	// before the response is written. If the hook sets a status or writes the response,
+	// the default "204 No Content" response is not written. Use `ValidateMetadata()` to validate
+	// the metadata in the hook. The upload is not removed automatically: it is the responsibility
+	// of the hook to move or remove it when it is not needed anymore.
+	OnComplete func(response *goyave.Response, request *goyave.Request, upload *Upload)
+
+	// locked the IDs of the uploads currently receiving a chunk.
+	locked map[string]struct{}
+
+	stop chan struct{}
+
+	// Directory the directory the uploads are stored in.
+	Directory string
+
+	// MaxSize the maximum size of an upload (in MiB). If 0, the size is not limited.
+	MaxSize float64
+
+	// Expiration the duration after which an incomplete upload expires. Defaults to 24 hours.
+	Expiration time.Duration
+
+	// CleanupInterval the interval at which expired uploads are removed while the server
+	// is running. Defaults to 1 hour. If negative, expired uploads are only removed
+	// on shutdown.
+	CleanupInterval time.Duration
+
+	mu sync.Mutex
+}
+
+// NewController create a new tus controller storing the uploads in the given directory.
+func NewController(fs FS, directory string) *Controller {
+	return &Controller{
+		FS:        fs,
+		Directory: directory,
+	}
+}
+
+// Init the controller. Registers a startup hook periodically removing expired uploads
+// and a shutdown hook removing expired uploads.
+func (c *Controller) Init(server *goyave.Server) {
+	c.Component.Init(server)
+	c.locked = map[string]struct{}{}
+	c.stop = make(chan struct{})
+	server.RegisterStartupHook(func(_ *goyave.Server) {
+		if c.CleanupInterval < 0 {
+			return
+		}
+		go c.cleanupLoop(lo.Ternary(c.CleanupInterval == 0, defaultCleanupInterval, c.CleanupInterval))
+	})
+	server.RegisterShutdownHook(func(_ *goyave.Server) {
+		close(c.stop)
+		if err := c.Cleanup(); err != nil {
+			c.Logger().Error(err)
+		}
+	})
+}
+
+// RegisterRoutes registers the tus routes on the given router.
+func (c *Controller) RegisterRoutes(router *goyave.Router) {
+	router.SetMeta(parse.MetaMaxUploadSize, parse.UploadSize(lo.Ternary(c.MaxSize == 0, math.MaxInt64, c.maxSize())))
+	router.Options("/", c.Options)
+	router.Post("/", c.resumable(c.Create))
+	router.Route([]string{http.MethodHead}, "/{uploadID}", c.resumable(c.Head))
+	router.Patch("/{uploadID}", c.resumable(c.Patch))
+	router.Delete("/{uploadID}", c.resumable(c.Delete))
+}
+
+// resumable checks the "Tus-Resumable" header of the request and adds it to the response.
+func (c *Controller) resumable(handler goyave.Handler) goyave.Handler {
+	return func(response *goyave.Response, request *goyave.Request) {
+		response.Header().Set(HeaderResumable, Version)
+		if request.Header().Get(HeaderResumable) != Version {
+			response.Header().Set(HeaderVersion, Version)
+			response.Status(http.StatusPreconditionFailed)
+			return
+		}
+		handler(response, request)
+	}
+}
+
+// Options returns the capabilities of the server.
+func (c *Controller) Options(response *goyave.Response, _ *goyave.Request) {
+	headers := response.Header()
+	headers.Set(HeaderResumable, Version)
+	headers.Set(HeaderVersion, Version)
+	headers.Set(HeaderExtension, "creation,creation-with-upload,expiration,checksum,termination")
+	algorithms := lo.Keys(checksumAlgorithms)
+	slices.Sort(algorithms)
+	headers.Set(HeaderChecksumAlgorithm, strings.Join(algorithms, ","))
+	if maxSize := c.maxSize(); maxSize > 0 {
+		headers.Set(HeaderMaxSize, strconv.FormatInt(maxSize, 10))
+	}
+	response.Status(http.StatusNoContent)
+}
+
+// Create a new upload. If the request has a body, it is stored as the first chunk.
+func (c *Controller) Create(response *goyave.Response, request *goyave.Request) {
+	size, err := strconv.ParseInt(request.Header().Get(HeaderUploadLength), 10, 64)
+	if err != nil || size < 0 {
+		response.Status(http.StatusBadRequest)
+		return
+	}
+	if maxSize := c.maxSize(); maxSize > 0 && size > maxSize {
+		response.Status(http.StatusRequestEntityTooLarge)
+		return
+	}
+	metadata, ok := parseMetadata(request.Header().Get(HeaderUploadMetadata))
+	if !ok {
+		response.Status(http.StatusBadRequest)
+		return
+	}
+
+	upload := &Upload{
+		fs:        c.FS,
+		directory: c.Directory,
+		ID:        strings.ReplaceAll(uuid.NewString(), "-", ""),
+		Size:      size,
+		Metadata:  metadata,
+		Chunks:    []int64{},
+		CreatedAt: time.Now(),
+		ExpiresAt: time.Now().Add(lo.Ternary(c.Expiration == 0, defaultExpiration, c.Expiration)),
+	}
+	if c.MetadataRules != nil {
+		errsBag, errs := c.ValidateMetadata(request, upload, c.MetadataRules(request))
+		if len(errs) != 0 {
+			response.Error(errs)
+			return
+		}
+		if errsBag != nil {
+			request.Extra[goyave.ExtraValidationError{}] = errsBag
+			response.Status(http.StatusUnprocessableEntity)
+			return
+		}
+	}
+
+	if mkdirFS, ok := c.FS.(fsutil.MkdirFS); ok {
+		if err := mkdirFS.MkdirAll(c.Directory, os.ModePerm); err != nil {
+			response.Error(errors.New(err))
+			return
+		}
+	}
+	if err := upload.save(); err != nil {
+		response.Error(err)
+		return
+	}
+	c.lock(upload.ID)
+	defer c.unlock(upload.ID)
+
+	response.Header().Set("Location", strings.TrimSuffix(request.URL().Path, "/")+"/"+upload.ID)
+	if request.Header().Get("Content-Type") == ContentType {
+		if !c.writeChunk(response, request, upload) {
+			return
+		}
+		response.Header().Set(HeaderUploadOffset, strconv.FormatInt(upload.Offset, 10))
+	}
+	response.Header().Set(HeaderUploadExpires, upload.ExpiresAt.UTC().Format(http.TimeFormat))
+	c.complete(response, request, upload, http.StatusCreated)
+}
+
+// Head returns the offset of an upload.
+func (c *Controller) Head(response *goyave.Response, request *goyave.Request) {
+	upload, ok := c.find(response, request)
+	if !ok {
+		return
+	}
+	headers := response.Header()
+	headers.Set("Cache-Control", "no-store")
+	headers.Set(HeaderUploadOffset, strconv.FormatInt(upload.Offset, 10))
+	headers.Set(HeaderUploadLength, strconv.FormatInt(upload.Size, 10))
+	if len(upload.Metadata) > 0 {
+		headers.Set(HeaderUploadMetadata, encodeMetadata(upload.Metadata))
+	}
+	if !upload.IsComplete() {
+		headers.Set(HeaderUploadExpires, upload.ExpiresAt.UTC().Format(http.TimeFormat))
+	}
+	response.WriteHeader(http.StatusOK)
+}
+
+// Patch appends a chunk to an upload. The "Upload-Offset" header must match the current
+// offset of the upload. If the "Upload-Checksum" header is given and the checksum of the
+// chunk doesn't match, the chunk is discarded and "460 Checksum Mismatch" is returned.
+func (c *Controller) Patch(response *goyave.Response, request *goyave.Request) {
+	if request.Header().Get("Content-Type") != ContentType {
+		response.Status(http.StatusUnsupportedMediaType)
+		return
+	}
+	id := request.RouteParams["uploadID"]
+	if !c.lock(id) {
+		response.Status(http.StatusLocked)
+		return
+	}
+	defer c.unlock(id)
+
+	upload, ok := c.find(response, request)
+	if !ok {
+		return
+	}
+	offset, err := strconv.ParseInt(request.Header().Get(HeaderUploadOffset), 10, 64)
+	if err != nil {
+		response.Status(http.StatusBadRequest)
+		return
+	}
+	if offset != upload.Offset {
+		response.Status(http.StatusConflict)
+		return
+	}
+
+	if !c.writeChunk(response, request, upload) {
+		return
+	}
+	response.Header().Set(HeaderUploadOffset, strconv.FormatInt(upload.Offset, 10))
+	if !upload.IsComplete() {
+		response.Header().Set(HeaderUploadExpires, upload.ExpiresAt.UTC().Format(http.TimeFormat))
+	}
+	c.complete(response, request, upload, http.StatusNoContent)
+}
+
+// Delete terminates an upload and removes it from the file system.
+func (c *Controller) Delete(response *goyave.Response, request *goyave.Request) {
+	id := request.RouteParams["uploadID"]
+	if !c.lock(id) {
+		response.Status(http.StatusLocked)
+		return
+	}
+	defer c.unlock(id)
+
+	upload, ok := c.find(response, request)
+	if !ok {
+		return
+	}
+	if err := upload.Remove(); err != nil {
+		response.Error(err)
+		return
+	}
+	response.Status(http.StatusNoContent)
+}
+
+// ValidateMetadata validates the metadata of the given upload using the given rules.
+// Returns the validation errors, if any, and the errors that occurred during the validation process.
+func (c *Controller) ValidateMetadata(request *goyave.Request, upload *Upload, rules validation.RuleSet) (*validation.Errors, []error) {
+	var db *gorm.DB
+	if c.Config().GetString("database.connection") != "none" {
+		db = c.DB().WithContext(request.Context())
+	}
+	opt := &validation.Options{
+		Context:                  request.Context(),
+		Data:                     lo.MapEntries(upload.Metadata, func(k, v string) (string, any) { return k, v }),
+		Rules:                    rules.AsRules(),
+		ConvertSingleValueArrays: true,
+		Language:                 request.Lang,
+		DB:                       db,
+		Config:                   c.Config(),
+		Logger:                   c.Logger(),
+		Extra: map[any]any{
+			validation.ExtraRequest{}: request,
+		},
+	}
+	return validation.Validate(opt)
+}
+
+// Find returns the upload identified by the given ID. Returns an error wrapping `fs.ErrNotExist`
+// if the upload doesn't exist.
+func (c *Controller) Find(id string) (*Upload, error) {
+	if id == "" || strings.ContainsAny(id, "/\\.") {
+		return nil, errors.New(fs.ErrNotExist)
+	}
+	return loadUpload(c.FS, c.Directory, id)
+}
+
+// Cleanup removes all expired uploads.
+func (c *Controller) Cleanup() error {
+	entries, err := c.FS.ReadDir(c.Directory)
+	if err != nil {
+		if stderrors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return errors.New(err)
+	}
+	errs := []error{}
+	for _, entry := range entries {
+		id, ok := strings.CutSuffix(entry.Name(), infoExtension)
+		if entry.IsDir() || !ok {
+			continue
+		}
+		if !c.lock(id) {
+			continue
+		}
+		upload, err := c.Find(id)
+		if err == nil && upload.IsExpired() {
+			err = upload.Remove()
+		}
+		c.unlock(id)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) != 0 {
+		return errors.New(errs)
+	}
+	return nil
+}
+
+func (c *Controller) cleanupLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-ticker.C:
+			if err := c.Cleanup(); err != nil {
+				c.Logger().Error(err)
+			}
+		}
+	}
+}
+
+// find the upload identified by the "uploadID" route parameter. Writes the error response
+// and returns false if the upload doesn't exist or is expired.
+func (c *Controller) find(response *goyave.Response, request *goyave.Request) (*Upload, bool) {
+	upload, err := c.Find(request.RouteParams["uploadID"])
+	if err != nil {
+		if stderrors.Is(err, fs.ErrNotExist) {
+			response.Status(http.StatusNotFound)
+		} else {
+			response.Error(err)
+		}
+		return nil, false
+	}
+	if upload.IsExpired() {
+		if err := upload.Remove(); err != nil {
+			c.Logger().Error(err)
+		}
+		response.Status(http.StatusGone)
+		return nil, false
+	}
+	return upload, true
+}
+
+// writeChunk stores the body of the request as a new chunk of the given upload. Writes the error
+// response and returns false if the chunk could not be stored.
+//
+// If the client disconnects, the bytes received so far are kept so the upload can be resumed,
+// unless a checksum was given.
+func (c *Controller) writeChunk(response *goyave.Response, request *goyave.Request, upload *Upload) bool {
+	h, checksum, ok := parseChecksum(request.Header().Get(HeaderUploadChecksum))
+	if !ok {
+		response.Status(http.StatusBadRequest)
+		return false
+	}
+
+	remaining := upload.Size - upload.Offset
+	path := upload.chunkPath(len(upload.Chunks))
+	n, err := c.copyChunk(path, io.LimitReader(request.Body(), remaining+1), h)
+	switch {
+	case n > remaining:
+		c.discardChunk(path)
+		response.Status(http.StatusRequestEntityTooLarge)
+		return false
+	case err == nil && h != nil && !slices.Equal(h.Sum(nil), checksum):
+		c.discardChunk(path)
+		response.Status(StatusChecksumMismatch)
+		return false
+	case n == 0 || (err != nil && h != nil):
+		c.discardChunk(path)
+	default:
+		upload.Chunks = append(upload.Chunks, n)
+		upload.Offset += n
+		if saveErr := upload.save(); saveErr != nil {
+			response.Error(saveErr)
+			return false
+		}
+	}
+	if err != nil {
+		response.Error(err)
+		return false
+	}
+	return true
+}
+
+// parseChecksum parses the "Upload-Checksum" header: the name of the algorithm and the
+// base64-encoded checksum, separated by a space. Returns a nil hash if the header is empty.
+func parseChecksum(header string) (hash.Hash, []byte, bool) {
+	if header == "" {
+		return nil, nil, true
+	}
+	algorithm, value, _ := strings.Cut(header, " ")
+	newHash, ok := checksumAlgorithms[algorithm]
+	checksum, err := base64.StdEncoding.DecodeString(value)
+	if !ok || err != nil {
+		return nil, nil, false
+	}
+	return newHash(), checksum, true
+}
+
+func (c *Controller) discardChunk(path string) {
+	if err := c.FS.Remove(path); err != nil && !stderrors.Is(err, fs.ErrNotExist) {
+		c.Logger().Error(errors.New(err))
+	}
+}
+
+func (c *Controller) copyChunk(path string, body io.Reader, h hash.Hash) (n int64, err error) {
+	var writer io.ReadWriteCloser
+	writer, err = c.FS.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0660)
+	if err != nil {
+		return 0, errors.New(err)
+	}
+	defer func() {
+		closeError := writer.Close()
+		if err == nil && closeError != nil {
+			err = errors.New(closeError)
+		}
+	}()
+	dst := io.Writer(writer)
+	if h != nil {
+		dst = io.MultiWriter(writer, h)
+	}
+	n, err = io.Copy(dst, body)
+	if err != nil {
+		err = errors.New(err)
+	}
+	return
+}
+
+// complete executes the `OnComplete` hook if the upload is complete and writes
+// the given status if the hook didn't write a response.
+func (c *Controller) complete(response *goyave.Response, request *goyave.Request, upload *Upload, status int) {
+	if upload.IsComplete() && c.OnComplete != nil {
+		c.OnComplete(response, request, upload)
+		if response.GetStatus() != 0 || response.IsHeaderWritten() {
+			return
+		}
+	}
+	response.Status(status)
+}
+
+func (c *Controller) maxSize() int64 {
+	return int64(c.MaxSize * 1024 * 1024)
+}
+
+func (c *Controller) lock(id string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.locked[id]; ok {
+		return false
+	}
+	c.locked[id] = struct{}{}
+	return true
+}
+
+func (c *Controller) unlock(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.locked, id)
+}
+
+// parseMetadata parses the "Upload-Metadata" header: comma-separated key-value pairs,
+// the key and the base64-encoded value being separated by a space. The value is optional.
+func parseMetadata(header string) (map[string]string, bool) {
+	metadata := map[string]string{}
+	if strings.TrimSpace(header) == "" {
+		return metadata, true
+	}
+	for _, pair := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(pair), " ")
+		if key == "" {
+			return nil, false
+		}
+		decoded, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return nil, false
+		}
+		metadata[key] = string(decoded)
+	}
+	return metadata, true
+}
+
+func encodeMetadata(metadata map[string]string) string {
+	keys := lo.Keys(metadata)
+	slices.Sort(keys)
+	return strings.Join(lo.Map(keys, func(k string, _ int) string {
+		if metadata[k] == "" {
+			return k
+		}
+		return k + " " + base64.StdEncoding.EncodeToString([]byte(metadata[k]))
+	}), ",")
+}
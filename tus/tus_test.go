@@ -0,0 +1,330 @@
+package tus
+
+import (
+	"crypto/sha1"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"goyave.dev/goyave/v5"
+	"goyave.dev/goyave/v5/config"
+	"goyave.dev/goyave/v5/middleware/parse"
+	"goyave.dev/goyave/v5/util/fsutil/osfs"
+	"goyave.dev/goyave/v5/util/testutil"
+	"goyave.dev/goyave/v5/validation"
+)
+
+func prepareTusTest(t *testing.T, setup func(c *Controller)) (*testutil.TestServer, *Controller) {
+	server := testutil.NewTestServerWithOptions(t, goyave.Options{Config: config.LoadDefault()})
+	controller := NewController(&osfs.FS{}, t.TempDir()+"/uploads")
+	if setup != nil {
+		setup(controller)
+	}
+	server.RegisterRoutes(func(_ *goyave.Server, router *goyave.Router) {
+		router.Subrouter("/uploads").Controller(controller)
+	})
+	return server, controller
+}
+
+func tusRequest(method, url string, body io.Reader, headers map[string]string) *http.Request {
+	request := httptest.NewRequest(method, url, body)
+	request.Header.Set(HeaderResumable, Version)
+	for k, v := range headers {
+		request.Header.Set(k, v)
+	}
+	return request
+}
+
+func doRequest(t *testing.T, server *testutil.TestServer, request *http.Request) *http.Response {
+	resp := server.TestRequest(request)
+	require.NoError(t, resp.Body.Close())
+	return resp
+}
+
+func createUpload(t *testing.T, server *testutil.TestServer, size string) string {
+	resp := doRequest(t, server, tusRequest(http.MethodPost, "/uploads", nil, map[string]string{
+		HeaderUploadLength:   size,
+		HeaderUploadMetadata: "filename " + base64.StdEncoding.EncodeToString([]byte("video.mp4")) + ",is_public",
+	}))
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	location := resp.Header.Get("Location")
+	require.True(t, strings.HasPrefix(location, "/uploads/"))
+	return location
+}
+
+func sha1Checksum(content string) string {
+	sum := sha1.Sum([]byte(content))
+	return "sha1 " + base64.StdEncoding.EncodeToString(sum[:])
+}
+
+func TestController(t *testing.T) {
+	t.Run("Options", func(t *testing.T) {
+		server, _ := prepareTusTest(t, func(c *Controller) { c.MaxSize = 1 })
+		resp := doRequest(t, server, httptest.NewRequest(http.MethodOptions, "/uploads", nil))
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+		assert.Equal(t, Version, resp.Header.Get(HeaderResumable))
+		assert.Equal(t, Version, resp.Header.Get(HeaderVersion))
+		assert.Equal(t, "creation,creation-with-upload,expiration,checksum,termination", resp.Header.Get(HeaderExtension))
+		assert.Equal(t, "md5,sha1,sha256", resp.Header.Get(HeaderChecksumAlgorithm))
+		assert.Equal(t, "1048576", resp.Header.Get(HeaderMaxSize))
+	})
+
+	t.Run("Unsupported_version", func(t *testing.T) {
+		server, _ := prepareTusTest(t, nil)
+		request := httptest.NewRequest(http.MethodPost, "/uploads", nil)
+		request.Header.Set(HeaderUploadLength, "10")
+		resp := doRequest(t, server, request)
+		assert.Equal(t, http.StatusPreconditionFailed, resp.StatusCode)
+		assert.Equal(t, Version, resp.Header.Get(HeaderVersion))
+	})
+
+	t.Run("Upload", func(t *testing.T) {
+		var completed *Upload
+		var content string
+		server, controller := prepareTusTest(t, func(c *Controller) {
+			c.OnComplete = func(_ *goyave.Response, _ *goyave.Request, upload *Upload) {
+				completed = upload
+				reader := upload.Open()
+				b, err := io.ReadAll(reader)
+				assert.NoError(t, err)
+				assert.NoError(t, reader.Close())
+				content = string(b)
+			}
+		})
+		location := createUpload(t, server, "11")
+
+		resp := doRequest(t, server, tusRequest(http.MethodHead, location, nil, nil))
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "0", resp.Header.Get(HeaderUploadOffset))
+		assert.Equal(t, "11", resp.Header.Get(HeaderUploadLength))
+		assert.Equal(t, "no-store", resp.Header.Get("Cache-Control"))
+		assert.Equal(t, "filename dmlkZW8ubXA0,is_public", resp.Header.Get(HeaderUploadMetadata))
+		assert.NotEmpty(t, resp.Header.Get(HeaderUploadExpires))
+
+		resp = doRequest(t, server, tusRequest(http.MethodPatch, location, strings.NewReader("hello "), map[string]string{
+			"Content-Type":       ContentType,
+			HeaderUploadOffset:   "0",
+			HeaderUploadChecksum: sha1Checksum("hello "),
+		}))
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+		assert.Equal(t, "6", resp.Header.Get(HeaderUploadOffset))
+		assert.Equal(t, Version, resp.Header.Get(HeaderResumable))
+
+		// Wrong offset
+		resp = doRequest(t, server, tusRequest(http.MethodPatch, location, strings.NewReader("world"), map[string]string{
+			"Content-Type":     ContentType,
+			HeaderUploadOffset: "0",
+		}))
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+		// Checksum mismatch: the chunk is discarded
+		resp = doRequest(t, server, tusRequest(http.MethodPatch, location, strings.NewReader("wrong"), map[string]string{
+			"Content-Type":       ContentType,
+			HeaderUploadOffset:   "6",
+			HeaderUploadChecksum: sha1Checksum("world"),
+		}))
+		assert.Equal(t, StatusChecksumMismatch, resp.StatusCode)
+
+		// Unsupported checksum algorithm
+		resp = doRequest(t, server, tusRequest(http.MethodPatch, location, strings.NewReader("world"), map[string]string{
+			"Content-Type":       ContentType,
+			HeaderUploadOffset:   "6",
+			HeaderUploadChecksum: "crc32 AAAA",
+		}))
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		// Wrong content type
+		resp = doRequest(t, server, tusRequest(http.MethodPatch, location, strings.NewReader("world"), map[string]string{
+			"Content-Type":     "text/plain",
+			HeaderUploadOffset: "6",
+		}))
+		assert.Equal(t, http.StatusUnsupportedMediaType, resp.StatusCode)
+
+		assert.Nil(t, completed)
+		resp = doRequest(t, server, tusRequest(http.MethodPatch, location, strings.NewReader("world"), map[string]string{
+			"Content-Type":     ContentType,
+			HeaderUploadOffset: "6",
+		}))
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+		assert.Equal(t, "11", resp.Header.Get(HeaderUploadOffset))
+		assert.Empty(t, resp.Header.Get(HeaderUploadExpires))
+
+		require.NotNil(t, completed)
+		assert.Equal(t, "hello world", content)
+		assert.Equal(t, []int64{6, 5}, completed.Chunks)
+		assert.Equal(t, map[string]string{"filename": "video.mp4", "is_public": ""}, completed.Metadata)
+		assert.True(t, completed.IsComplete())
+
+		upload, err := controller.Find(strings.TrimPrefix(location, "/uploads/"))
+		require.NoError(t, err)
+		assert.Equal(t, int64(11), upload.Offset)
+	})
+
+	t.Run("Creation_with_upload", func(t *testing.T) {
+		server, _ := prepareTusTest(t, nil)
+		resp := doRequest(t, server, tusRequest(http.MethodPost, "/uploads", strings.NewReader("hello"), map[string]string{
+			"Content-Type":     ContentType,
+			HeaderUploadLength: "11",
+		}))
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		assert.Equal(t, "5", resp.Header.Get(HeaderUploadOffset))
+		assert.NotEmpty(t, resp.Header.Get("Location"))
+	})
+
+	t.Run("Create_errors", func(t *testing.T) {
+		server, _ := prepareTusTest(t, func(c *Controller) {
+			c.MaxSize = 0.001
+			c.MetadataRules = func(_ *goyave.Request) validation.RuleSet {
+				return validation.RuleSet{
+					{Path: "filename", Rules: validation.List{validation.Required(), validation.String()}},
+				}
+			}
+		})
+
+		cases := []struct {
+			headers  map[string]string
+			desc     string
+			expected int
+		}{
+			{desc: "missing_length", headers: map[string]string{}, expected: http.StatusBadRequest},
+			{desc: "invalid_length", headers: map[string]string{HeaderUploadLength: "-1"}, expected: http.StatusBadRequest},
+			{desc: "too_large", headers: map[string]string{HeaderUploadLength: "2000"}, expected: http.StatusRequestEntityTooLarge},
+			{desc: "invalid_metadata", headers: map[string]string{HeaderUploadLength: "10", HeaderUploadMetadata: "filename %%%"}, expected: http.StatusBadRequest},
+			{desc: "metadata_validation", headers: map[string]string{HeaderUploadLength: "10", HeaderUploadMetadata: "other b3RoZXI="}, expected: http.StatusUnprocessableEntity},
+			{desc: "valid", headers: map[string]string{HeaderUploadLength: "10", HeaderUploadMetadata: "filename dmlkZW8ubXA0"}, expected: http.StatusCreated},
+		}
+		for _, c := range cases {
+			t.Run(c.desc, func(t *testing.T) {
+				resp := doRequest(t, server, tusRequest(http.MethodPost, "/uploads", nil, c.headers))
+				assert.Equal(t, c.expected, resp.StatusCode)
+			})
+		}
+	})
+
+	t.Run("Chunk_too_large", func(t *testing.T) {
+		server, _ := prepareTusTest(t, nil)
+		location := createUpload(t, server, "3")
+		resp := doRequest(t, server, tusRequest(http.MethodPatch, location, strings.NewReader("hello"), map[string]string{
+			"Content-Type":     ContentType,
+			HeaderUploadOffset: "0",
+		}))
+		assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+
+		resp = doRequest(t, server, tusRequest(http.MethodHead, location, nil, nil))
+		assert.Equal(t, "0", resp.Header.Get(HeaderUploadOffset))
+	})
+
+	t.Run("Parse_middleware", func(t *testing.T) {
+		cases := []struct {
+			desc    string
+			maxSize float64
+		}{
+			{desc: "max_size", maxSize: 0.01},
+			{desc: "unlimited", maxSize: 0},
+		}
+		for _, c := range cases {
+			t.Run(c.desc, func(t *testing.T) {
+				server := testutil.NewTestServerWithOptions(t, goyave.Options{Config: config.LoadDefault()})
+				controller := NewController(&osfs.FS{}, t.TempDir()+"/uploads")
+				controller.MaxSize = c.maxSize
+				server.RegisterRoutes(func(_ *goyave.Server, router *goyave.Router) {
+					// The chunks are larger than the max upload size of the middleware
+					router.GlobalMiddleware(&parse.Middleware{MaxUploadSize: 0.001})
+					router.Subrouter("/uploads").Controller(controller)
+				})
+
+				chunk := strings.Repeat("a", 4096)
+				resp := doRequest(t, server, tusRequest(http.MethodPost, "/uploads", strings.NewReader(chunk), map[string]string{
+					"Content-Type":     ContentType,
+					HeaderUploadLength: "8192",
+				}))
+				require.Equal(t, http.StatusCreated, resp.StatusCode)
+				assert.Equal(t, "4096", resp.Header.Get(HeaderUploadOffset))
+
+				resp = doRequest(t, server, tusRequest(http.MethodPatch, resp.Header.Get("Location"), strings.NewReader(chunk), map[string]string{
+					"Content-Type":     ContentType,
+					HeaderUploadOffset: "4096",
+				}))
+				assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+				assert.Equal(t, "8192", resp.Header.Get(HeaderUploadOffset))
+			})
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		server, _ := prepareTusTest(t, nil)
+		location := createUpload(t, server, "11")
+		resp := doRequest(t, server, tusRequest(http.MethodPatch, location, strings.NewReader("hello"), map[string]string{
+			"Content-Type":     ContentType,
+			HeaderUploadOffset: "0",
+		}))
+		require.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+		resp = doRequest(t, server, tusRequest(http.MethodDelete, location, nil, nil))
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+		resp = doRequest(t, server, tusRequest(http.MethodHead, location, nil, nil))
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		resp = doRequest(t, server, tusRequest(http.MethodDelete, location, nil, nil))
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		resp = doRequest(t, server, tusRequest(http.MethodHead, "/uploads/..", nil, nil))
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("Expiration", func(t *testing.T) {
+		server, controller := prepareTusTest(t, func(c *Controller) {
+			c.Expiration = time.Millisecond
+		})
+		expired := createUpload(t, server, "11")
+		complete := createUpload(t, server, "0")
+		time.Sleep(5 * time.Millisecond)
+
+		resp := doRequest(t, server, tusRequest(http.MethodHead, expired, nil, nil))
+		assert.Equal(t, http.StatusGone, resp.StatusCode)
+		resp = doRequest(t, server, tusRequest(http.MethodHead, expired, nil, nil))
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+		other := createUpload(t, server, "11")
+		time.Sleep(5 * time.Millisecond)
+		require.NoError(t, controller.Cleanup())
+		_, err := controller.Find(strings.TrimPrefix(other, "/uploads/"))
+		require.Error(t, err)
+
+		// Complete uploads don't expire
+		_, err = controller.Find(strings.TrimPrefix(complete, "/uploads/"))
+		require.NoError(t, err)
+	})
+
+	t.Run("Cleanup_on_shutdown", func(t *testing.T) {
+		cfg := config.LoadDefault()
+		cfg.Set("server.port", 0)
+		server := testutil.NewTestServerWithOptions(t, goyave.Options{Config: cfg})
+		controller := NewController(&osfs.FS{}, t.TempDir())
+		controller.Expiration = time.Millisecond
+		controller.CleanupInterval = time.Hour
+		server.RegisterRoutes(func(_ *goyave.Server, router *goyave.Router) {
+			router.Subrouter("/uploads").Controller(controller)
+		})
+		location := createUpload(t, server, "11")
+		time.Sleep(5 * time.Millisecond)
+
+		server.RegisterStartupHook(func(s *goyave.Server) {
+			s.Stop()
+		})
+		require.NoError(t, server.Start())
+
+		_, err := controller.Find(strings.TrimPrefix(location, "/uploads/"))
+		require.Error(t, err)
+	})
+
+	t.Run("Cleanup_missing_directory", func(t *testing.T) {
+		_, controller := prepareTusTest(t, nil)
+		require.NoError(t, controller.Cleanup())
+	})
+}
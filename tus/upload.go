@@ -0,0 +1,177 @@
+package tus
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"io"
+	"io/fs"
+	"os"
+	pathutil "path"
+	"strconv"
+	"time"
+
+	"goyave.dev/goyave/v5/util/errors"
+	"goyave.dev/goyave/v5/util/fsutil"
+)
+
+const infoExtension = ".info"
+
+// FS the file system used to store the uploads.
+type FS interface {
+	fsutil.FS
+	fsutil.WritableFS
+	fsutil.RemoveFS
+}
+
+// Upload a resumable upload. The content of the upload is stored as a sequence of chunks,
+// one per `PATCH` request, next to a ".info" JSON file describing the upload.
+type Upload struct {
+	// ExpiresAt the time after which the upload is removed if it is not complete.
+	ExpiresAt time.Time `json:"expiresAt"`
+
+	// CreatedAt the time at which the upload was created.
+	CreatedAt time.Time `json:"createdAt"`
+
+	fs FS
+
+	// Metadata the decoded "Upload-Metadata" sent by the client when creating the upload.
+	Metadata map[string]string `json:"metadata"`
+
+	// ID the unique identifier of the upload.
+	ID string `json:"id"`
+
+	directory string
+
+	// Chunks the sizes of the stored chunks, in order.
+	Chunks []int64 `json:"chunks"`
+
+	// Size the total size of the upload, in bytes.
+	Size int64 `json:"size"`
+
+	// Offset the number of bytes received so far.
+	Offset int64 `json:"offset"`
+}
+
+// IsComplete returns true if all the bytes of the upload have been received.
+func (u *Upload) IsComplete() bool {
+	return u.Offset == u.Size
+}
+
+// IsExpired returns true if the upload is not complete and its expiration time has passed.
+func (u *Upload) IsExpired() bool {
+	return !u.IsComplete() && !u.ExpiresAt.IsZero() && time.Now().After(u.ExpiresAt)
+}
+
+// Open returns a reader for the content received so far. The chunks are opened lazily.
+// The returned reader must be closed.
+func (u *Upload) Open() io.ReadCloser {
+	return &chunkReader{upload: u}
+}
+
+// Remove deletes the upload and all its chunks from the file system.
+func (u *Upload) Remove() error {
+	for i := range u.Chunks {
+		if err := u.fs.Remove(u.chunkPath(i)); err != nil && !stderrors.Is(err, fs.ErrNotExist) {
+			return errors.New(err)
+		}
+	}
+	if err := u.fs.Remove(u.infoPath()); err != nil && !stderrors.Is(err, fs.ErrNotExist) {
+		return errors.New(err)
+	}
+	return nil
+}
+
+func (u *Upload) infoPath() string {
+	return pathutil.Join(u.directory, u.ID+infoExtension)
+}
+
+func (u *Upload) chunkPath(index int) string {
+	return pathutil.Join(u.directory, u.ID+"."+strconv.Itoa(index))
+}
+
+func (u *Upload) save() (err error) {
+	var writer io.ReadWriteCloser
+	writer, err = u.fs.OpenFile(u.infoPath(), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0660)
+	if err != nil {
+		return errors.New(err)
+	}
+	defer func() {
+		closeError := writer.Close()
+		if err == nil && closeError != nil {
+			err = errors.New(closeError)
+		}
+	}()
+	if err = json.NewEncoder(writer).Encode(u); err != nil {
+		err = errors.New(err)
+	}
+	return
+}
+
+func loadUpload(filesystem FS, directory, id string) (upload *Upload, err error) {
+	upload = &Upload{fs: filesystem, directory: directory, ID: id}
+	var f fs.File
+	f, err = filesystem.Open(upload.infoPath())
+	if err != nil {
+		return nil, errors.New(err)
+	}
+	defer func() {
+		closeError := f.Close()
+		if err == nil && closeError != nil {
+			err = errors.New(closeError)
+		}
+	}()
+	if err = json.NewDecoder(f).Decode(upload); err != nil {
+		return nil, errors.New(err)
+	}
+	upload.ID = id
+	return upload, nil
+}
+
+// chunkReader reads the chunks of an upload one after the other.
+type chunkReader struct {
+	upload  *Upload
+	current fs.File
+	index   int
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for {
+		if r.current == nil {
+			if r.index >= len(r.upload.Chunks) {
+				return 0, io.EOF
+			}
+			f, err := r.upload.fs.Open(r.upload.chunkPath(r.index))
+			if err != nil {
+				return 0, errors.New(err)
+			}
+			r.current = f
+		}
+		n, err := r.current.Read(p)
+		if err == io.EOF {
+			closeErr := r.current.Close()
+			r.current = nil
+			r.index++
+			if closeErr != nil {
+				return n, errors.New(closeErr)
+			}
+			if n == 0 {
+				continue
+			}
+			return n, nil
+		}
+		return n, err
+	}
+}
+
+func (r *chunkReader) Close() error {
+	if r.current == nil {
+		return nil
+	}
+	err := r.current.Close()
+	r.current = nil
+	r.index = len(r.upload.Chunks)
+	if err != nil {
+		return errors.New(err)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package tus
+
+import (
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"goyave.dev/goyave/v5/util/fsutil/osfs"
+)
+
+func TestUpload(t *testing.T) {
+	newUpload := func(t *testing.T, chunks ...string) *Upload {
+		upload := &Upload{fs: &osfs.FS{}, directory: t.TempDir(), ID: "abc", Chunks: []int64{}}
+		for i, c := range chunks {
+			require.NoError(t, os.WriteFile(upload.chunkPath(i), []byte(c), 0660))
+			upload.Chunks = append(upload.Chunks, int64(len(c)))
+			upload.Offset += int64(len(c))
+		}
+		upload.Size = upload.Offset
+		require.NoError(t, upload.save())
+		return upload
+	}
+
+	t.Run("Open", func(t *testing.T) {
+		upload := newUpload(t, "hello", " ", "world")
+		reader := upload.Open()
+		content, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		assert.Equal(t, "hello world", string(content))
+		assert.NoError(t, reader.Close())
+
+		// Close before the end
+		reader = upload.Open()
+		_, err = reader.Read(make([]byte, 2))
+		require.NoError(t, err)
+		assert.NoError(t, reader.Close())
+		_, err = reader.Read(make([]byte, 2))
+		assert.ErrorIs(t, err, io.EOF)
+	})
+
+	t.Run("Open_missing_chunk", func(t *testing.T) {
+		upload := newUpload(t, "hello", "world")
+		require.NoError(t, os.Remove(upload.chunkPath(1)))
+		reader := upload.Open()
+		_, err := io.ReadAll(reader)
+		require.ErrorIs(t, err, os.ErrNotExist)
+		assert.NoError(t, reader.Close())
+	})
+
+	t.Run("Remove", func(t *testing.T) {
+		upload := newUpload(t, "hello", "world")
+		loaded, err := loadUpload(upload.fs, upload.directory, upload.ID)
+		require.NoError(t, err)
+		assert.Equal(t, upload.Chunks, loaded.Chunks)
+
+		require.NoError(t, upload.Remove())
+		assert.NoFileExists(t, upload.infoPath())
+		assert.NoFileExists(t, upload.chunkPath(0))
+		assert.NoFileExists(t, upload.chunkPath(1))
+		require.NoError(t, upload.Remove()) // Already removed
+	})
+
+	t.Run("IsExpired", func(t *testing.T) {
+		upload := &Upload{Size: 10, Offset: 5, ExpiresAt: time.Now().Add(-time.Second)}
+		assert.True(t, upload.IsExpired())
+		upload.ExpiresAt = time.Now().Add(time.Hour)
+		assert.False(t, upload.IsExpired())
+		upload.ExpiresAt = time.Now().Add(-time.Second)
+		upload.Offset = 10
+		assert.False(t, upload.IsExpired())
+	})
+}
@@ -0,0 +1,167 @@
+package outbox
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"goyave.dev/goyave/v5"
+	"goyave.dev/goyave/v5/slog"
+	"goyave.dev/goyave/v5/util/errors"
+)
+
+// Dispatcher periodically delivers the pending messages of an `Outbox` to a `Publisher`.
+//
+// Messages are published one by one, in order of insertion. If the publisher returns an error,
+// the message is retried after a delay given by `Backoff`, until `MaxAttempts` is reached. Messages
+// that reached the maximum number of attempts are left in the outbox and are not retried anymore.
+//
+// Only a single dispatcher should run for a given outbox table. Running several dispatchers (for
+// example if multiple instances of the application are running) may result in more duplicate deliveries.
+type Dispatcher struct {
+	outbox    *Outbox
+	publisher Publisher
+
+	// Logger used to report delivery errors. If `nil`, errors are not reported.
+	Logger *slog.Logger
+
+	// Backoff returns the delay before the next delivery attempt of a message
+	// that already failed the given number of times.
+	Backoff func(attempts int) time.Duration
+
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	// PollInterval the delay between two dispatch passes. Defaults to 1 second.
+	PollInterval time.Duration
+
+	// BatchSize the maximum number of messages delivered per dispatch pass. Defaults to 100.
+	BatchSize int
+
+	// MaxAttempts the maximum number of delivery attempts of a message. Zero or
+	// less means unlimited. Defaults to 10.
+	MaxAttempts int
+
+	mu      sync.Mutex
+	stopped bool
+}
+
+// NewDispatcher create a new `Dispatcher` delivering the messages of the given outbox
+// using the given publisher.
+func NewDispatcher(outbox *Outbox, publisher Publisher) *Dispatcher {
+	return &Dispatcher{
+		outbox:       outbox,
+		publisher:    publisher,
+		Backoff:      ExponentialBackoff(time.Second, 5*time.Minute),
+		PollInterval: time.Second,
+		BatchSize:    100,
+		MaxAttempts:  10,
+	}
+}
+
+// ExponentialBackoff returns a backoff function doubling the delay for each failed
+// attempt, starting with `base` and never exceeding `limit`.
+func ExponentialBackoff(base, limit time.Duration) func(attempts int) time.Duration {
+	return func(attempts int) time.Duration {
+		delay := base
+		for i := 1; i < attempts && delay < limit; i++ {
+			delay *= 2
+		}
+		return min(delay, limit)
+	}
+}
+
+// RegisterHooks registers a startup hook starting the dispatcher and a shutdown hook
+// stopping it on the given server. The server's logger is used if the dispatcher doesn't
+// have a logger yet.
+func (d *Dispatcher) RegisterHooks(server *goyave.Server) {
+	if d.Logger == nil {
+		d.Logger = server.Logger
+	}
+	server.RegisterStartupHook(func(_ *goyave.Server) {
+		d.Start()
+	})
+	server.RegisterShutdownHook(func(_ *goyave.Server) {
+		d.Stop()
+	})
+}
+
+// Start the background delivery of the messages. Does nothing if the dispatcher
+// is already running or has been stopped. A stopped dispatcher cannot be restarted.
+func (d *Dispatcher) Start() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.stopped || d.cancel != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	d.cancel = cancel
+	d.done = make(chan struct{})
+	go d.run(ctx)
+}
+
+// Stop the background delivery of the messages and wait for the current dispatch
+// pass to finish.
+func (d *Dispatcher) Stop() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.stopped = true
+	if d.cancel == nil {
+		return
+	}
+	d.cancel()
+	<-d.done
+	d.cancel = nil
+}
+
+func (d *Dispatcher) run(ctx context.Context) {
+	defer close(d.done)
+	ticker := time.NewTicker(d.PollInterval)
+	defer ticker.Stop()
+	for {
+		if _, err := d.Dispatch(ctx); err != nil && ctx.Err() == nil {
+			d.logError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Dispatch executes a single dispatch pass: fetches a batch of pending messages and publishes them.
+// Returns the number of successfully delivered messages. Publication errors don't stop the pass,
+// they are logged and the message is scheduled for a later attempt.
+func (d *Dispatcher) Dispatch(ctx context.Context) (int, error) {
+	messages, err := d.outbox.Pending(ctx, d.BatchSize, d.MaxAttempts)
+	if err != nil {
+		return 0, err
+	}
+
+	delivered := 0
+	for _, message := range messages {
+		if ctx.Err() != nil {
+			return delivered, errors.New(ctx.Err())
+		}
+		if err := d.publisher.Publish(ctx, message); err != nil {
+			d.logError(errors.Errorf("outbox message %q (attempt %d) could not be published: %w", message.IdempotencyKey, message.Attempts+1, err))
+			if err := d.outbox.markFailed(ctx, message, err, time.Now().Add(d.Backoff(message.Attempts+1))); err != nil {
+				return delivered, err
+			}
+			continue
+		}
+		if err := d.outbox.markDelivered(ctx, message); err != nil {
+			return delivered, err
+		}
+		delivered++
+	}
+	return delivered, nil
+}
+
+func (d *Dispatcher) logError(err error) {
+	if d.Logger != nil {
+		d.Logger.Error(err)
+	}
+}
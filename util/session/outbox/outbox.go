@@ -0,0 +1,156 @@
+// Package outbox implements the transactional outbox pattern on top of `session.Gorm`.
+//
+// Messages are enqueued inside a business transaction (see `session.Session.Transaction()`)
+// and persisted in the same database transaction as the business data. They are therefore only
+// visible once the transaction is committed, and discarded if it is rolled back.
+// A `Dispatcher` running in the background then delivers the persisted messages to a `Publisher`
+// (a message broker, a webhook, ...), retrying in case of failure.
+//
+// Delivery is "at least once": a message may be published more than once (for example if the
+// dispatcher is interrupted right after publishing but before marking the message as delivered).
+// Consumers should use the message's idempotency key to discard duplicates.
+//
+// The `Message` model must be migrated before using the outbox:
+//
+//	db.AutoMigrate(&outbox.Message{})
+package outbox
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"goyave.dev/goyave/v5/util/errors"
+	"goyave.dev/goyave/v5/util/session"
+)
+
+// Message a message persisted in the outbox, waiting to be delivered.
+type Message struct {
+	CreatedAt time.Time `json:"createdAt"`
+
+	// AvailableAt the time after which the dispatcher is allowed to (re)try to deliver the message.
+	AvailableAt time.Time `json:"availableAt" gorm:"index"`
+
+	// DeliveredAt the time the message was successfully published. `nil` if not delivered yet.
+	DeliveredAt *time.Time `json:"deliveredAt" gorm:"index"`
+
+	// Topic identifies the kind of message or the destination channel. Its meaning is up
+	// to the publisher.
+	Topic string `json:"topic"`
+
+	// IdempotencyKey unique key identifying the message. Enqueuing a message with a key that
+	// is already in the outbox is a no-op. It should be forwarded by the publisher so consumers
+	// can discard duplicate deliveries.
+	IdempotencyKey string `json:"idempotencyKey" gorm:"uniqueIndex;size:255"`
+
+	// LastError the error returned by the publisher during the last delivery attempt.
+	LastError string `json:"lastError"`
+
+	Payload []byte `json:"payload"`
+
+	ID int64 `json:"id" gorm:"primaryKey"`
+
+	// Attempts the number of delivery attempts.
+	Attempts int `json:"attempts"`
+}
+
+// TableName returns the name of the outbox table.
+func (Message) TableName() string {
+	return "outbox_messages"
+}
+
+// Outbox stores messages in the database, using the transaction of the current session if any.
+type Outbox struct {
+	db *gorm.DB
+}
+
+// New create a new Outbox using the given database. This database is used as fallback
+// when enqueuing messages outside of a session transaction.
+func New(db *gorm.DB) *Outbox {
+	return &Outbox{db: db}
+}
+
+// Enqueue persists the given message in the outbox. If the given context contains a transaction
+// (see `session.DB()`), the message is inserted in this transaction and will only be delivered
+// once it is committed.
+//
+// If the message doesn't have an idempotency key, a random one is generated. If a message with the
+// same idempotency key already exists in the outbox, nothing is inserted and no error is returned.
+//
+//	err := sess.Transaction(ctx, func(ctx context.Context) error {
+//		if err := s.repo.Create(ctx, order); err != nil {
+//			return err
+//		}
+//		return s.outbox.Enqueue(ctx, &outbox.Message{Topic: "order.created", Payload: payload})
+//	})
+func (o *Outbox) Enqueue(ctx context.Context, message *Message) error {
+	if message.IdempotencyKey == "" {
+		key, err := generateKey()
+		if err != nil {
+			return errors.New(err)
+		}
+		message.IdempotencyKey = key
+	}
+	now := time.Now()
+	if message.CreatedAt.IsZero() {
+		message.CreatedAt = now
+	}
+	if message.AvailableAt.IsZero() {
+		message.AvailableAt = message.CreatedAt
+	}
+
+	db := session.DB(ctx, o.db).WithContext(ctx)
+	err := db.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "idempotency_key"}}, DoNothing: true}).Create(message).Error
+	return errors.New(err)
+}
+
+// Pending returns at most `limit` messages that are not delivered yet, are available for delivery
+// and haven't reached the given number of attempts, ordered by ID.
+func (o *Outbox) Pending(ctx context.Context, limit int, maxAttempts int) ([]*Message, error) {
+	messages := make([]*Message, 0, limit)
+	db := o.db.WithContext(ctx).
+		Where("delivered_at IS NULL").
+		Where("available_at <= ?", time.Now())
+	if maxAttempts > 0 {
+		db = db.Where("attempts < ?", maxAttempts)
+	}
+	err := db.Order("id").Limit(limit).Find(&messages).Error
+	return messages, errors.New(err)
+}
+
+// Purge deletes the messages delivered before the given time.
+// Returns the number of deleted messages.
+func (o *Outbox) Purge(ctx context.Context, before time.Time) (int64, error) {
+	db := o.db.WithContext(ctx).Where("delivered_at IS NOT NULL AND delivered_at < ?", before).Delete(&Message{})
+	return db.RowsAffected, errors.New(db.Error)
+}
+
+func (o *Outbox) markDelivered(ctx context.Context, message *Message) error {
+	now := time.Now()
+	err := o.db.WithContext(ctx).Model(message).Updates(map[string]any{
+		"delivered_at": now,
+		"attempts":     message.Attempts + 1,
+		"last_error":   "",
+	}).Error
+	return errors.New(err)
+}
+
+func (o *Outbox) markFailed(ctx context.Context, message *Message, reason error, retryAt time.Time) error {
+	err := o.db.WithContext(ctx).Model(message).Updates(map[string]any{
+		"attempts":     message.Attempts + 1,
+		"last_error":   reason.Error(),
+		"available_at": retryAt,
+	}).Error
+	return errors.New(err)
+}
+
+func generateKey() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
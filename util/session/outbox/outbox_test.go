@@ -0,0 +1,364 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"goyave.dev/goyave/v5"
+	"goyave.dev/goyave/v5/config"
+	"goyave.dev/goyave/v5/database"
+	"goyave.dev/goyave/v5/slog"
+	"goyave.dev/goyave/v5/util/session"
+)
+
+type testOrder struct {
+	Name string
+	ID   int64 `gorm:"primaryKey"`
+}
+
+func init() {
+	database.RegisterDialect("sqlite3_outbox_test", "file:{name}?{options}", sqlite.Open)
+}
+
+func prepareTestConfig(t *testing.T) *config.Config {
+	cfg := config.LoadDefault()
+	cfg.Set("app.debug", false)
+	cfg.Set("database.connection", "sqlite3_outbox_test")
+	cfg.Set("database.name", fmt.Sprintf("outbox_test_%s.db", t.Name()))
+	cfg.Set("database.options", "mode=memory&cache=shared")
+	return cfg
+}
+
+func openTestDB(t *testing.T) *gorm.DB {
+	db, err := database.New(prepareTestConfig(t), nil)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		sqlDB, err := db.DB()
+		require.NoError(t, err)
+		require.NoError(t, sqlDB.Close())
+	})
+	require.NoError(t, db.AutoMigrate(&Message{}, &testOrder{}))
+	return db
+}
+
+func countMessages(t *testing.T, db *gorm.DB) int64 {
+	var count int64
+	require.NoError(t, db.Model(&Message{}).Count(&count).Error)
+	return count
+}
+
+func TestOutbox(t *testing.T) {
+	t.Run("Enqueue_commit", func(t *testing.T) {
+		db := openTestDB(t)
+		sess := session.GORM(db, nil)
+		outbox := New(db)
+
+		message := &Message{Topic: "order.created", Payload: []byte(`{"id":1}`)}
+		err := sess.Transaction(context.Background(), func(ctx context.Context) error {
+			if err := session.DB(ctx, db).Create(&testOrder{Name: "order"}).Error; err != nil {
+				return err
+			}
+			return outbox.Enqueue(ctx, message)
+		})
+		require.NoError(t, err)
+		assert.NotEmpty(t, message.IdempotencyKey)
+		assert.NotZero(t, message.ID)
+		assert.False(t, message.CreatedAt.IsZero())
+		assert.Equal(t, message.CreatedAt, message.AvailableAt)
+
+		pending, err := outbox.Pending(context.Background(), 10, 0)
+		require.NoError(t, err)
+		require.Len(t, pending, 1)
+		assert.Equal(t, "order.created", pending[0].Topic)
+		assert.Equal(t, []byte(`{"id":1}`), pending[0].Payload)
+		assert.Equal(t, message.IdempotencyKey, pending[0].IdempotencyKey)
+	})
+
+	t.Run("Enqueue_rollback", func(t *testing.T) {
+		db := openTestDB(t)
+		sess := session.GORM(db, nil)
+		outbox := New(db)
+
+		err := sess.Transaction(context.Background(), func(ctx context.Context) error {
+			if err := outbox.Enqueue(ctx, &Message{Topic: "order.created"}); err != nil {
+				return err
+			}
+			return fmt.Errorf("business error")
+		})
+		require.Error(t, err)
+		assert.Equal(t, int64(0), countMessages(t, db))
+	})
+
+	t.Run("Enqueue_idempotency_key", func(t *testing.T) {
+		db := openTestDB(t)
+		outbox := New(db)
+
+		require.NoError(t, outbox.Enqueue(context.Background(), &Message{Topic: "a", IdempotencyKey: "key"}))
+		require.NoError(t, outbox.Enqueue(context.Background(), &Message{Topic: "b", IdempotencyKey: "key"}))
+		assert.Equal(t, int64(1), countMessages(t, db))
+
+		stored := &Message{}
+		require.NoError(t, db.First(stored).Error)
+		assert.Equal(t, "a", stored.Topic)
+	})
+
+	t.Run("Pending", func(t *testing.T) {
+		db := openTestDB(t)
+		outbox := New(db)
+		ctx := context.Background()
+
+		now := time.Now()
+		require.NoError(t, outbox.Enqueue(ctx, &Message{Topic: "delivered", DeliveredAt: &now}))
+		require.NoError(t, outbox.Enqueue(ctx, &Message{Topic: "later", AvailableAt: now.Add(time.Hour)}))
+		require.NoError(t, outbox.Enqueue(ctx, &Message{Topic: "exhausted", Attempts: 3}))
+		require.NoError(t, outbox.Enqueue(ctx, &Message{Topic: "pending1"}))
+		require.NoError(t, outbox.Enqueue(ctx, &Message{Topic: "pending2"}))
+
+		pending, err := outbox.Pending(ctx, 10, 3)
+		require.NoError(t, err)
+		topics := make([]string, 0, len(pending))
+		for _, m := range pending {
+			topics = append(topics, m.Topic)
+		}
+		assert.Equal(t, []string{"pending1", "pending2"}, topics)
+
+		pending, err = outbox.Pending(ctx, 1, 0)
+		require.NoError(t, err)
+		require.Len(t, pending, 1)
+		assert.Equal(t, "exhausted", pending[0].Topic)
+	})
+
+	t.Run("Purge", func(t *testing.T) {
+		db := openTestDB(t)
+		outbox := New(db)
+		ctx := context.Background()
+
+		old := time.Now().Add(-48 * time.Hour)
+		recent := time.Now()
+		require.NoError(t, outbox.Enqueue(ctx, &Message{Topic: "old", DeliveredAt: &old}))
+		require.NoError(t, outbox.Enqueue(ctx, &Message{Topic: "recent", DeliveredAt: &recent}))
+		require.NoError(t, outbox.Enqueue(ctx, &Message{Topic: "pending"}))
+
+		deleted, err := outbox.Purge(ctx, time.Now().Add(-24*time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), deleted)
+		assert.Equal(t, int64(2), countMessages(t, db))
+	})
+}
+
+func TestDispatcher(t *testing.T) {
+	t.Run("NewDispatcher", func(t *testing.T) {
+		outbox := New(nil)
+		publisher := NewMemoryPublisher()
+		d := NewDispatcher(outbox, publisher)
+		assert.Equal(t, outbox, d.outbox)
+		assert.Equal(t, publisher, d.publisher)
+		assert.Equal(t, time.Second, d.PollInterval)
+		assert.Equal(t, 100, d.BatchSize)
+		assert.Equal(t, 10, d.MaxAttempts)
+		assert.NotNil(t, d.Backoff)
+	})
+
+	t.Run("ExponentialBackoff", func(t *testing.T) {
+		backoff := ExponentialBackoff(time.Second, 10*time.Second)
+		assert.Equal(t, time.Second, backoff(1))
+		assert.Equal(t, 2*time.Second, backoff(2))
+		assert.Equal(t, 4*time.Second, backoff(3))
+		assert.Equal(t, 8*time.Second, backoff(4))
+		assert.Equal(t, 10*time.Second, backoff(5))
+		assert.Equal(t, 10*time.Second, backoff(50))
+	})
+
+	t.Run("Dispatch", func(t *testing.T) {
+		db := openTestDB(t)
+		outbox := New(db)
+		ctx := context.Background()
+		require.NoError(t, outbox.Enqueue(ctx, &Message{Topic: "a"}))
+		require.NoError(t, outbox.Enqueue(ctx, &Message{Topic: "b"}))
+
+		publisher := NewMemoryPublisher()
+		d := NewDispatcher(outbox, publisher)
+		delivered, err := d.Dispatch(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 2, delivered)
+
+		messages := publisher.Messages()
+		require.Len(t, messages, 2)
+		assert.Equal(t, "a", messages[0].Topic)
+		assert.Equal(t, "b", messages[1].Topic)
+
+		stored := []*Message{}
+		require.NoError(t, db.Order("id").Find(&stored).Error)
+		for _, m := range stored {
+			assert.NotNil(t, m.DeliveredAt)
+			assert.Equal(t, 1, m.Attempts)
+		}
+
+		// Nothing left
+		delivered, err = d.Dispatch(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 0, delivered)
+		assert.Len(t, publisher.Messages(), 2)
+	})
+
+	t.Run("Dispatch_retry", func(t *testing.T) {
+		db := openTestDB(t)
+		outbox := New(db)
+		ctx := context.Background()
+		require.NoError(t, outbox.Enqueue(ctx, &Message{Topic: "a", IdempotencyKey: "key-a"}))
+
+		memory := NewMemoryPublisher()
+		failures := 2
+		publisher := PublisherFunc(func(ctx context.Context, message *Message) error {
+			if failures > 0 {
+				failures--
+				return fmt.Errorf("broker unavailable")
+			}
+			return memory.Publish(ctx, message)
+		})
+
+		logBuffer := &bytes.Buffer{}
+		d := NewDispatcher(outbox, publisher)
+		d.Logger = slog.New(slog.NewHandler(false, logBuffer))
+		d.Backoff = func(_ int) time.Duration { return 0 }
+
+		delivered, err := d.Dispatch(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 0, delivered)
+		assert.Contains(t, logBuffer.String(), `outbox message \"key-a\" (attempt 1) could not be published: broker unavailable`)
+
+		stored := &Message{}
+		require.NoError(t, db.First(stored).Error)
+		assert.Equal(t, 1, stored.Attempts)
+		assert.Equal(t, "broker unavailable", stored.LastError)
+		assert.Nil(t, stored.DeliveredAt)
+
+		delivered, err = d.Dispatch(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 0, delivered)
+
+		delivered, err = d.Dispatch(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, delivered)
+		require.Len(t, memory.Messages(), 1)
+		assert.Equal(t, "key-a", memory.Messages()[0].IdempotencyKey)
+
+		require.NoError(t, db.First(stored).Error)
+		assert.Equal(t, 3, stored.Attempts)
+		assert.Empty(t, stored.LastError)
+		assert.NotNil(t, stored.DeliveredAt)
+	})
+
+	t.Run("Dispatch_max_attempts_and_backoff", func(t *testing.T) {
+		db := openTestDB(t)
+		outbox := New(db)
+		ctx := context.Background()
+		require.NoError(t, outbox.Enqueue(ctx, &Message{Topic: "a"}))
+
+		calls := 0
+		d := NewDispatcher(outbox, PublisherFunc(func(_ context.Context, _ *Message) error {
+			calls++
+			return fmt.Errorf("broker unavailable")
+		}))
+		d.MaxAttempts = 2
+
+		_, err := d.Dispatch(ctx)
+		require.NoError(t, err)
+		// Backoff: not available for the next pass
+		_, err = d.Dispatch(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, calls)
+
+		d.Backoff = func(_ int) time.Duration { return 0 }
+		require.NoError(t, db.Model(&Message{}).Where("1 = 1").Update("available_at", time.Now()).Error)
+		for range 3 {
+			_, err = d.Dispatch(ctx)
+			require.NoError(t, err)
+		}
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("Start_Stop", func(t *testing.T) {
+		db := openTestDB(t)
+		outbox := New(db)
+		ctx := context.Background()
+		require.NoError(t, outbox.Enqueue(ctx, &Message{Topic: "a"}))
+
+		published := make(chan struct{}, 1)
+		d := NewDispatcher(outbox, PublisherFunc(func(_ context.Context, _ *Message) error {
+			published <- struct{}{}
+			return nil
+		}))
+		d.PollInterval = 10 * time.Millisecond
+		d.Start()
+		d.Start() // No-op
+		select {
+		case <-published:
+		case <-time.After(time.Second):
+			assert.Fail(t, "timeout waiting for the message to be published")
+		}
+		d.Stop()
+		d.Stop() // No-op
+
+		// Stopped dispatcher cannot be restarted
+		d.Start()
+		assert.Nil(t, d.cancel)
+	})
+
+	t.Run("RegisterHooks", func(t *testing.T) {
+		cfg := prepareTestConfig(t)
+		cfg.Set("server.port", 0)
+		server, err := goyave.New(goyave.Options{Config: cfg})
+		require.NoError(t, err)
+		require.NoError(t, server.DB().AutoMigrate(&Message{}))
+		server.RegisterRoutes(func(_ *goyave.Server, _ *goyave.Router) {})
+
+		outbox := New(server.DB())
+		require.NoError(t, outbox.Enqueue(context.Background(), &Message{Topic: "a"}))
+
+		var publishedCount atomic.Int32
+		published := make(chan struct{}, 1)
+		d := NewDispatcher(outbox, PublisherFunc(func(_ context.Context, _ *Message) error {
+			publishedCount.Add(1)
+			published <- struct{}{}
+			return nil
+		}))
+		d.PollInterval = 10 * time.Millisecond
+		d.RegisterHooks(server)
+		assert.Equal(t, server.Logger, d.Logger)
+
+		server.RegisterStartupHook(func(s *goyave.Server) {
+			select {
+			case <-published:
+			case <-time.After(time.Second):
+				assert.Fail(t, "timeout waiting for the message to be published")
+			}
+			s.Stop()
+		})
+
+		require.NoError(t, server.Start())
+		assert.True(t, d.stopped)
+		assert.Nil(t, d.cancel)
+		assert.Equal(t, int32(1), publishedCount.Load())
+	})
+}
+
+func TestMemoryPublisher(t *testing.T) {
+	p := NewMemoryPublisher()
+	require.NoError(t, p.Publish(context.Background(), &Message{IdempotencyKey: "a", Topic: "first"}))
+	require.NoError(t, p.Publish(context.Background(), &Message{IdempotencyKey: "b"}))
+	require.NoError(t, p.Publish(context.Background(), &Message{IdempotencyKey: "a", Topic: "duplicate"}))
+
+	messages := p.Messages()
+	require.Len(t, messages, 2)
+	assert.Equal(t, "first", messages[0].Topic)
+	assert.Equal(t, "b", messages[1].IdempotencyKey)
+}
@@ -0,0 +1,60 @@
+package outbox
+
+import (
+	"context"
+	"slices"
+	"sync"
+)
+
+// Publisher delivers outbox messages to their destination (message broker, webhook, etc).
+// If `Publish` returns an error, the delivery is retried later by the `Dispatcher`.
+type Publisher interface {
+	Publish(ctx context.Context, message *Message) error
+}
+
+// PublisherFunc an adapter allowing the use of ordinary functions as `Publisher`.
+type PublisherFunc func(ctx context.Context, message *Message) error
+
+// Publish calls f(ctx, message).
+func (f PublisherFunc) Publish(ctx context.Context, message *Message) error {
+	return f(ctx, message)
+}
+
+// MemoryPublisher a local `Publisher` keeping the published messages in memory.
+// Messages having an idempotency key that was already published are ignored, the
+// same way an idempotent consumer would. Mostly useful for tests.
+//
+// This publisher is safe for concurrent use.
+type MemoryPublisher struct {
+	published map[string]struct{}
+	messages  []*Message
+	mu        sync.Mutex
+}
+
+// NewMemoryPublisher create a new empty `MemoryPublisher`.
+func NewMemoryPublisher() *MemoryPublisher {
+	return &MemoryPublisher{
+		published: map[string]struct{}{},
+		messages:  []*Message{},
+	}
+}
+
+// Publish stores the given message, unless a message with the same idempotency key
+// was already published.
+func (p *MemoryPublisher) Publish(_ context.Context, message *Message) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.published[message.IdempotencyKey]; ok {
+		return nil
+	}
+	p.published[message.IdempotencyKey] = struct{}{}
+	p.messages = append(p.messages, message)
+	return nil
+}
+
+// Messages returns the published messages, in order of publication.
+func (p *MemoryPublisher) Messages() []*Message {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return slices.Clone(p.messages)
+}
@@ -0,0 +1,35 @@
+package testutil
+
+import (
+	"strings"
+	"testing"
+
+	"goyave.dev/goyave/v5/lang"
+)
+
+// AssertLanguagesComplete reports a test error for each available language that doesn't
+// define all the lines of the default language, or that defines lines the default language
+// doesn't. Returns true if all languages are complete.
+//
+//	func TestLanguages(t *testing.T) {
+//		languages := lang.New()
+//		if err := languages.LoadAllAvailableLanguages(&osfs.FS{}); err != nil {
+//			t.Fatal(err)
+//		}
+//		testutil.AssertLanguagesComplete(t, languages)
+//	}
+func AssertLanguagesComplete(t testing.TB, languages *lang.Languages) bool {
+	t.Helper()
+	complete := true
+	for _, diff := range languages.DiffAll() {
+		if len(diff.Missing) > 0 {
+			complete = false
+			t.Errorf("Language %q is missing %d line(s):\n\t%s", diff.Language, len(diff.Missing), strings.Join(diff.Missing, "\n\t"))
+		}
+		if len(diff.Unused) > 0 {
+			complete = false
+			t.Errorf("Language %q has %d line(s) not defined in the default language %q:\n\t%s", diff.Language, len(diff.Unused), languages.Default, strings.Join(diff.Unused, "\n\t"))
+		}
+	}
+	return complete
+}
@@ -0,0 +1,45 @@
+package testutil
+
+import (
+	"fmt"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"goyave.dev/goyave/v5/lang"
+)
+
+type mockTB struct {
+	testing.TB
+	errors []string
+}
+
+func (t *mockTB) Helper() {}
+
+func (t *mockTB) Errorf(format string, args ...any) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func TestAssertLanguagesComplete(t *testing.T) {
+	fs := fstest.MapFS{
+		"en-US/locale.json": &fstest.MapFile{Data: []byte(`{"custom-line": "custom line"}`)},
+		"fr-FR/locale.json": &fstest.MapFile{Data: []byte(`{"custom-line": "ligne", "stale-line": "obsolète"}`)},
+	}
+
+	languages := lang.New()
+	require.NoError(t, languages.LoadDirectory(fs, "."))
+
+	mock := &mockTB{}
+	assert.False(t, AssertLanguagesComplete(mock, languages))
+	require.Len(t, mock.errors, 2)
+	assert.Contains(t, mock.errors[0], "Language \"fr-FR\" is missing")
+	assert.Contains(t, mock.errors[0], "\n\tvalidation.rules.required\n")
+	assert.NotContains(t, mock.errors[0], "custom-line")
+	assert.Equal(t, "Language \"fr-FR\" has 1 line(s) not defined in the default language \"en-US\":\n\tstale-line", mock.errors[1])
+
+	languages = lang.New()
+	mock = &mockTB{}
+	assert.True(t, AssertLanguagesComplete(mock, languages))
+	assert.Empty(t, mock.errors)
+}
@@ -116,11 +116,15 @@ func Exists(scope func(db *gorm.DB, val any) *gorm.DB) *ExistsValidator {
 // If provided, the `Transform` function is called on every array element to transform
 // them into a raw expression. For example to transform a number into `(123::int)` for
 // Postgres to prevent some type errors.
+//
+// If `SoftDeleteColumn` is not empty, the records for which this column is not `NULL`
+// are considered as non-existing.
 type ExistsArrayValidator[T any] struct {
 	BaseValidator
-	Transform func(val T) clause.Expr
-	Table     string
-	Column    string
+	Transform        func(val T) clause.Expr
+	Table            string
+	Column           string
+	SoftDeleteColumn string
 }
 
 // Validate checks the field under validation satisfies this validator's criteria.
@@ -162,11 +166,12 @@ func (v *ExistsArrayValidator[T]) buildQuery(values []T, condition bool) (*gorm.
 	column := db.Statement.Quote(v.Column)
 
 	sql := fmt.Sprintf(
-		"WITH ctx_values(id, i) AS (SELECT * FROM (VALUES %s) t%s) SELECT i FROM ctx_values LEFT JOIN %s ON %s.%s = ctx_values.id WHERE %s.%s IS %s NULL",
+		"WITH ctx_values(id, i) AS (SELECT * FROM (VALUES %s) t%s) SELECT i FROM ctx_values LEFT JOIN %s ON %s.%s = ctx_values.id%s WHERE %s.%s IS %s NULL",
 		strings.Join(questionMarks, ","),
 		lo.Ternary(dbType == "mssql", "(id,i)", ""),
 		table,
 		table, column,
+		v.softDeleteCondition(db, table),
 		table, column,
 		lo.Ternary(condition, "", "NOT"),
 	)
@@ -197,17 +202,25 @@ func (v *ExistsArrayValidator[T]) buildClickhouseQuery(values []T, condition boo
 	column := db.Statement.Quote(v.Column)
 
 	sql := fmt.Sprintf(
-		"WITH ctx_values(id, i) AS (SELECT * FROM (VALUES 'id %s, i Int64', %s)) SELECT i FROM ctx_values INNER JOIN %s ON %s.%s = ctx_values.id WHERE %s.%s IS %s NULL",
+		"WITH ctx_values(id, i) AS (SELECT * FROM (VALUES 'id %s, i Int64', %s)) SELECT i FROM ctx_values INNER JOIN %s ON %s.%s = ctx_values.id%s WHERE %s.%s IS %s NULL",
 		paramType,
 		strings.Join(questionMarks, ","),
 		table,
 		table, column,
+		v.softDeleteCondition(db, table),
 		table, column,
 		lo.Ternary(condition, "", "NOT"),
 	)
 	return db.Raw(sql, params...), nil
 }
 
+func (v *ExistsArrayValidator[T]) softDeleteCondition(db *gorm.DB, table string) string {
+	if v.SoftDeleteColumn == "" {
+		return ""
+	}
+	return fmt.Sprintf(" AND %s.%s IS NULL", table, db.Statement.Quote(v.SoftDeleteColumn))
+}
+
 func (v *ExistsArrayValidator[T]) validate(ctx *Context, condition bool) bool {
 	values, ok := ctx.Value.([]T)
 	if ctx.Invalid || !ok {
@@ -220,9 +233,9 @@ func (v *ExistsArrayValidator[T]) validate(ctx *Context, condition bool) bool {
 		return false
 	}
 
-	timeout := v.Config().GetInt("database.defaultReadQueryTimeout")
+	timeout := v.Config().GetDuration("database.defaultReadQueryTimeout")
 	if _, hasDeadline := db.Statement.Context.Deadline(); !hasDeadline && timeout > 0 {
-		timeoutCtx, cancel := context.WithTimeout(db.Statement.Context, time.Duration(timeout)*time.Millisecond)
+		timeoutCtx, cancel := context.WithTimeout(db.Statement.Context, timeout)
 		defer cancel()
 		db = db.WithContext(timeoutCtx)
 	}
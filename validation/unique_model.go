@@ -0,0 +1,427 @@
+package validation
+
+import (
+	"reflect"
+	"strconv"
+	"sync"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+	"goyave.dev/goyave/v5/util/errors"
+)
+
+var deletedAtType = reflect.TypeOf(gorm.DeletedAt{})
+
+func parseModelSchema[M any](db *gorm.DB) (*schema.Schema, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(new(M)); err != nil {
+		return nil, errors.New(err)
+	}
+	return stmt.Schema, nil
+}
+
+func lookUpModelField(s *schema.Schema, name string) (*schema.Field, error) {
+	field := s.LookUpField(name)
+	if field == nil || field.DBName == "" {
+		return nil, errors.Errorf("model %q has no column %q", s.Name, name)
+	}
+	return field, nil
+}
+
+func softDeleteField(s *schema.Schema) *schema.Field {
+	for _, f := range s.Fields {
+		if f.FieldType == deletedAtType && f.DBName != "" {
+			return f
+		}
+	}
+	return nil
+}
+
+// convertRouteParam converts a route parameter to the type of the given field
+// so it can safely be compared in a query, regardless of the dialect.
+func convertRouteParam(field *schema.Field, param string) (any, error) {
+	switch field.DataType {
+	case schema.Int:
+		i, err := strconv.ParseInt(param, 10, 64)
+		if err != nil {
+			return nil, errors.Errorf("route parameter %q cannot be converted to integer for column %q", param, field.DBName)
+		}
+		return i, nil
+	case schema.Uint:
+		i, err := strconv.ParseUint(param, 10, 64)
+		if err != nil {
+			return nil, errors.Errorf("route parameter %q cannot be converted to unsigned integer for column %q", param, field.DBName)
+		}
+		return i, nil
+	}
+	return param, nil
+}
+
+// UniqueForValidator validates the field under validation must have a unique value in database.
+// The table and the column are resolved from the GORM schema of the model `M`.
+// Uniqueness is checked using a COUNT query.
+//
+// Soft-deleted records (using `gorm.DeletedAt`) are ignored unless `WithTrashed()` is used.
+type UniqueForValidator[M any] struct {
+	BaseValidator
+
+	// Column the name of the column (or of the model's field) compared with the value under validation.
+	Column string
+
+	// IgnoreRouteParam if not empty, the record for which `IgnoreColumn` equals this route
+	// parameter is excluded from the check. This is useful to ignore the record being updated.
+	IgnoreRouteParam string
+
+	// IgnoreColumn the name of the column (or of the model's field) compared with `IgnoreRouteParam`.
+	// Defaults to the model's primary key.
+	IgnoreColumn string
+
+	// With the names of other fields of the parent object (siblings of the field under validation)
+	// that must be part of the uniqueness check. Each name is both the key of the field in the parent
+	// object and the name of the column (or of the model's field).
+	With []string
+
+	// Trashed if true, soft-deleted records are also taken into account.
+	Trashed bool
+}
+
+// Validate checks the field under validation satisfies this validator's criteria.
+func (v *UniqueForValidator[M]) Validate(ctx *Context) bool {
+	if ctx.Invalid {
+		return true
+	}
+
+	db, s, err := buildModelQuery[M](v.DB(), ctx, v.Column, v.With, v.Trashed)
+	if err == nil {
+		db, err = v.ignore(db, s, ctx)
+	}
+	if err != nil {
+		ctx.AddError(err)
+		return false
+	}
+
+	count := int64(0)
+	if err := db.Count(&count).Error; err != nil {
+		ctx.AddError(errors.New(err))
+		return false
+	}
+	return count == 0
+}
+
+// ignore excludes the record identified by the `IgnoreRouteParam` route parameter from the query.
+func (v *UniqueForValidator[M]) ignore(db *gorm.DB, s *schema.Schema, ctx *Context) (*gorm.DB, error) {
+	if v.IgnoreRouteParam == "" {
+		return db, nil
+	}
+	params, _ := ctx.Extra[ExtraRouteParams{}].(map[string]string)
+	param, ok := params[v.IgnoreRouteParam]
+	if !ok {
+		return db, nil
+	}
+
+	ignoreField := s.PrioritizedPrimaryField
+	if v.IgnoreColumn != "" {
+		var err error
+		ignoreField, err = lookUpModelField(s, v.IgnoreColumn)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if ignoreField == nil {
+		return nil, errors.Errorf("model %q has no primary key, an ignore column must be specified", s.Name)
+	}
+	value, err := convertRouteParam(ignoreField, param)
+	if err != nil {
+		return nil, err
+	}
+	return db.Where(clause.Neq{Column: clause.Column{Table: clause.CurrentTable, Name: ignoreField.DBName}, Value: value}), nil
+}
+
+// buildModelQuery returns a query on the model `M` matching the records for which the given
+// column equals the value under validation and the `with` columns equal the sibling fields.
+func buildModelQuery[M any](db *gorm.DB, ctx *Context, column string, with []string, trashed bool) (*gorm.DB, *schema.Schema, error) {
+	s, err := parseModelSchema[M](db)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	db = db.Model(new(M))
+	if trashed {
+		db = db.Unscoped()
+	}
+
+	field, err := lookUpModelField(s, column)
+	if err != nil {
+		return nil, nil, err
+	}
+	db = db.Where(clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, Value: ctx.Value})
+
+	parent, _ := ctx.Parent.(map[string]any)
+	for _, name := range with {
+		f, err := lookUpModelField(s, name)
+		if err != nil {
+			return nil, nil, err
+		}
+		db = db.Where(clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: f.DBName}, Value: parent[name]})
+	}
+	return db, s, nil
+}
+
+// Name returns the string name of the validator.
+func (v *UniqueForValidator[M]) Name() string { return "unique" }
+
+// Ignore exclude from the check the record whose primary key equals the given route parameter.
+// Returns the same instance of the validator so this method can be chained.
+//
+//	// PATCH /users/{userID:[0-9]+}
+//	v.UniqueFor[model.User]("email").Ignore("userID")
+func (v *UniqueForValidator[M]) Ignore(routeParam string) *UniqueForValidator[M] {
+	v.IgnoreRouteParam = routeParam
+	return v
+}
+
+// IgnoreBy exclude from the check the record for which the given column equals the given route parameter.
+// Returns the same instance of the validator so this method can be chained.
+func (v *UniqueForValidator[M]) IgnoreBy(column, routeParam string) *UniqueForValidator[M] {
+	v.IgnoreColumn = column
+	v.IgnoreRouteParam = routeParam
+	return v
+}
+
+// WithFields add other fields of the parent object to the uniqueness check (composite uniqueness).
+// If one of these fields is missing from the parent object, its column is expected to be `NULL`.
+// Returns the same instance of the validator so this method can be chained.
+//
+//	// The pair (tenant_id, email) must be unique
+//	v.UniqueFor[model.User]("email").WithFields("tenant_id")
+func (v *UniqueForValidator[M]) WithFields(fields ...string) *UniqueForValidator[M] {
+	v.With = append(v.With, fields...)
+	return v
+}
+
+// WithTrashed take soft-deleted records into account.
+// Returns the same instance of the validator so this method can be chained.
+func (v *UniqueForValidator[M]) WithTrashed() *UniqueForValidator[M] {
+	v.Trashed = true
+	return v
+}
+
+// UniqueFor validates the field under validation must have a unique value in database.
+// The table and the column are resolved from the GORM schema of the model `M`, so the given
+// column can either be a column name or the name of a field of the model.
+// Uniqueness is checked using a COUNT query.
+//
+//	v.UniqueFor[model.User]("email")
+//
+//	// Ignore the record being updated and soft-deleted records.
+//	// PATCH /users/{userID:[0-9]+}
+//	v.UniqueFor[model.User]("email").Ignore("userID")
+func UniqueFor[M any](column string) *UniqueForValidator[M] {
+	return &UniqueForValidator[M]{Column: column}
+}
+
+//------------------------------
+
+// ExistsForValidator validates the field under validation must exist in database.
+// The table and the column are resolved from the GORM schema of the model `M`.
+// Existence is checked using a COUNT query.
+//
+// Soft-deleted records (using `gorm.DeletedAt`) are considered non-existing unless
+// `WithTrashed()` is used.
+type ExistsForValidator[M any] struct {
+	BaseValidator
+
+	// Column the name of the column (or of the model's field) compared with the value under validation.
+	Column string
+
+	// With the names of other fields of the parent object (siblings of the field under validation)
+	// that must be part of the existence check. Each name is both the key of the field in the parent
+	// object and the name of the column (or of the model's field).
+	With []string
+
+	// Trashed if true, soft-deleted records are also taken into account.
+	Trashed bool
+}
+
+// Validate checks the field under validation satisfies this validator's criteria.
+func (v *ExistsForValidator[M]) Validate(ctx *Context) bool {
+	if ctx.Invalid {
+		return true
+	}
+
+	db, _, err := buildModelQuery[M](v.DB(), ctx, v.Column, v.With, v.Trashed)
+	if err != nil {
+		ctx.AddError(err)
+		return false
+	}
+
+	count := int64(0)
+	if err := db.Count(&count).Error; err != nil {
+		ctx.AddError(errors.New(err))
+		return false
+	}
+	return count > 0
+}
+
+// Name returns the string name of the validator.
+func (v *ExistsForValidator[M]) Name() string { return "exists" }
+
+// WithFields add other fields of the parent object to the existence check.
+// If one of these fields is missing from the parent object, its column is expected to be `NULL`.
+// Returns the same instance of the validator so this method can be chained.
+func (v *ExistsForValidator[M]) WithFields(fields ...string) *ExistsForValidator[M] {
+	v.With = append(v.With, fields...)
+	return v
+}
+
+// WithTrashed take soft-deleted records into account.
+// Returns the same instance of the validator so this method can be chained.
+func (v *ExistsForValidator[M]) WithTrashed() *ExistsForValidator[M] {
+	v.Trashed = true
+	return v
+}
+
+// ExistsFor validates the field under validation must exist in database.
+// The table and the column are resolved from the GORM schema of the model `M`, so the given
+// column can either be a column name or the name of a field of the model.
+// Existence is checked using a COUNT query.
+//
+//	v.ExistsFor[model.Category]("id")
+func ExistsFor[M any](column string) *ExistsForValidator[M] {
+	return &ExistsForValidator[M]{Column: column}
+}
+
+//------------------------------
+
+// ExistsArrayForValidator validates the field under validation must be an array and all
+// of its elements must exist. The table and the column are resolved from the GORM schema
+// of the model `M`. The type `T` is the type of the elements of the array under validation.
+//
+// Soft-deleted records (using `gorm.DeletedAt`) are considered non-existing unless
+// `Trashed` is `true`.
+//
+// See `ExistsArrayValidator` for more details.
+type ExistsArrayForValidator[M, T any] struct {
+	ExistsArrayValidator[T]
+	resolver arrayResolver[M, T]
+	Trashed  bool
+}
+
+// Validate checks the field under validation satisfies this validator's criteria.
+func (v *ExistsArrayForValidator[M, T]) Validate(ctx *Context) bool {
+	if err := v.resolver.resolve(&v.ExistsArrayValidator, v.Trashed); err != nil {
+		ctx.AddError(err)
+		return false
+	}
+	return v.ExistsArrayValidator.Validate(ctx)
+}
+
+// WithTrashed take soft-deleted records into account.
+// Returns the same instance of the validator so this method can be chained.
+func (v *ExistsArrayForValidator[M, T]) WithTrashed() *ExistsArrayForValidator[M, T] {
+	v.Trashed = true
+	return v
+}
+
+// ExistsArrayFor validates the field under validation must be an array and all
+// of its elements must exist. The table and the column are resolved from the GORM schema
+// of the model `M`, so the given column can either be a column name or the name of a field
+// of the model. The type `T` is the type of the elements of the array under validation.
+//
+//	v.ExistsArrayFor[model.Tag, uint]("id", nil)
+//
+// See `ExistsArray` for more details.
+func ExistsArrayFor[M, T any](column string, transform func(val T) clause.Expr) *ExistsArrayForValidator[M, T] {
+	return &ExistsArrayForValidator[M, T]{
+		ExistsArrayValidator: ExistsArrayValidator[T]{
+			Column:    column,
+			Transform: transform,
+		},
+	}
+}
+
+//------------------------------
+
+// UniqueArrayForValidator validates the field under validation must be an array and all
+// of its elements must not already exist. The table and the column are resolved from
+// the GORM schema of the model `M`. The type `T` is the type of the elements of the array
+// under validation.
+//
+// Soft-deleted records (using `gorm.DeletedAt`) are ignored unless `Trashed` is `true`.
+//
+// See `UniqueArrayValidator` for more details.
+type UniqueArrayForValidator[M, T any] struct {
+	UniqueArrayValidator[T]
+	resolver arrayResolver[M, T]
+	Trashed  bool
+}
+
+// Validate checks the field under validation satisfies this validator's criteria.
+func (v *UniqueArrayForValidator[M, T]) Validate(ctx *Context) bool {
+	if err := v.resolver.resolve(&v.ExistsArrayValidator, v.Trashed); err != nil {
+		ctx.AddError(err)
+		return false
+	}
+	return v.UniqueArrayValidator.Validate(ctx)
+}
+
+// WithTrashed take soft-deleted records into account.
+// Returns the same instance of the validator so this method can be chained.
+func (v *UniqueArrayForValidator[M, T]) WithTrashed() *UniqueArrayForValidator[M, T] {
+	v.Trashed = true
+	return v
+}
+
+// UniqueArrayFor validates the field under validation must be an array and all
+// of its elements must not already exist. The table and the column are resolved from
+// the GORM schema of the model `M`, so the given column can either be a column name or the
+// name of a field of the model. The type `T` is the type of the elements of the array under
+// validation.
+//
+//	v.UniqueArrayFor[model.User, string]("email", nil)
+//
+// See `UniqueArray` for more details.
+func UniqueArrayFor[M, T any](column string, transform func(val T) clause.Expr) *UniqueArrayForValidator[M, T] {
+	return &UniqueArrayForValidator[M, T]{
+		UniqueArrayValidator: UniqueArrayValidator[T]{
+			ExistsArrayValidator: ExistsArrayValidator[T]{
+				Column:    column,
+				Transform: transform,
+			},
+		},
+	}
+}
+
+// arrayResolver resolves the table and the columns of an array validator from the GORM schema
+// of the model `M`. The resolution only happens the first time the validator is used.
+type arrayResolver[M, T any] struct {
+	err  error
+	once sync.Once
+}
+
+func (r *arrayResolver[M, T]) resolve(v *ExistsArrayValidator[T], trashed bool) error {
+	r.once.Do(func() {
+		r.err = resolveArrayValidator[M](v, trashed)
+	})
+	return r.err
+}
+
+func resolveArrayValidator[M, T any](v *ExistsArrayValidator[T], trashed bool) error {
+	s, err := parseModelSchema[M](v.DB())
+	if err != nil {
+		return err
+	}
+	field, err := lookUpModelField(s, v.Column)
+	if err != nil {
+		return err
+	}
+	v.Table = s.Table
+	v.Column = field.DBName
+	if !trashed {
+		if f := softDeleteField(s); f != nil {
+			v.SoftDeleteColumn = f.DBName
+		}
+	}
+	return nil
+}
@@ -0,0 +1,352 @@
+package validation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type uniqueForTestModel struct {
+	DeletedAt gorm.DeletedAt
+	Email     string
+	ID        uint
+	TenantID  uint
+}
+
+func (m uniqueForTestModel) TableName() string {
+	return "unique_for_models"
+}
+
+type noPrimaryKeyTestModel struct {
+	Name string
+}
+
+func prepareUniqueForTest(t *testing.T) *Options {
+	opts := prepareUniqueTest(t)
+	require.NoError(t, opts.DB.AutoMigrate(&uniqueForTestModel{}))
+	records := []*uniqueForTestModel{
+		{ID: 1, Email: "a@example.org", TenantID: 1},
+		{ID: 2, Email: "b@example.org", TenantID: 1},
+		{ID: 3, Email: "a@example.org", TenantID: 2},
+		{ID: 4, Email: "deleted@example.org", TenantID: 1, DeletedAt: gorm.DeletedAt{Time: time.Now(), Valid: true}},
+	}
+	require.NoError(t, opts.DB.Create(records).Error)
+	return opts
+}
+
+func TestUniqueForValidator(t *testing.T) {
+	t.Run("Constructor", func(t *testing.T) {
+		v := UniqueFor[uniqueForTestModel]("email")
+		assert.NotNil(t, v)
+		assert.Equal(t, "unique", v.Name())
+		assert.False(t, v.IsType())
+		assert.False(t, v.IsTypeDependent())
+		assert.Empty(t, v.MessagePlaceholders(&Context{}))
+		assert.Equal(t, "email", v.Column)
+
+		v.Ignore("userID").WithFields("tenant_id").WithTrashed()
+		assert.Equal(t, "userID", v.IgnoreRouteParam)
+		assert.Empty(t, v.IgnoreColumn)
+		assert.Equal(t, []string{"tenant_id"}, v.With)
+		assert.True(t, v.Trashed)
+
+		v.IgnoreBy("email", "email")
+		assert.Equal(t, "email", v.IgnoreRouteParam)
+		assert.Equal(t, "email", v.IgnoreColumn)
+	})
+
+	cases := []struct {
+		validator      *UniqueForValidator[uniqueForTestModel]
+		value          any
+		parent         any
+		routeParams    map[string]string
+		desc           string
+		expectedErrors []string
+		valid          bool
+		expected       bool
+	}{
+		{desc: "OK", validator: UniqueFor[uniqueForTestModel]("email"), value: "c@example.org", valid: true, expected: true},
+		{desc: "NOK", validator: UniqueFor[uniqueForTestModel]("email"), value: "a@example.org", valid: true, expected: false},
+		{desc: "field_name", validator: UniqueFor[uniqueForTestModel]("Email"), value: "a@example.org", valid: true, expected: false},
+		{desc: "soft_deleted", validator: UniqueFor[uniqueForTestModel]("email"), value: "deleted@example.org", valid: true, expected: true},
+		{desc: "with_trashed", validator: UniqueFor[uniqueForTestModel]("email").WithTrashed(), value: "deleted@example.org", valid: true, expected: false},
+		{
+			desc:        "ignore_route_param",
+			validator:   UniqueFor[uniqueForTestModel]("email").Ignore("userID"),
+			value:       "b@example.org",
+			routeParams: map[string]string{"userID": "2"},
+			valid:       true,
+			expected:    true,
+		},
+		{
+			desc:        "ignore_route_param_other_record",
+			validator:   UniqueFor[uniqueForTestModel]("email").Ignore("userID"),
+			value:       "b@example.org",
+			routeParams: map[string]string{"userID": "1"},
+			valid:       true,
+			expected:    false,
+		},
+		{
+			desc:        "ignore_route_param_missing",
+			validator:   UniqueFor[uniqueForTestModel]("email").Ignore("userID"),
+			value:       "b@example.org",
+			routeParams: map[string]string{},
+			valid:       true,
+			expected:    false,
+		},
+		{
+			desc:           "ignore_route_param_invalid",
+			validator:      UniqueFor[uniqueForTestModel]("email").Ignore("userID"),
+			value:          "b@example.org",
+			routeParams:    map[string]string{"userID": "abc"},
+			valid:          true,
+			expected:       false,
+			expectedErrors: []string{`route parameter "abc" cannot be converted to unsigned integer for column "id"`},
+		},
+		{
+			desc:        "ignore_by",
+			validator:   UniqueFor[uniqueForTestModel]("email").IgnoreBy("email", "email"),
+			value:       "b@example.org",
+			routeParams: map[string]string{"email": "b@example.org"},
+			valid:       true,
+			expected:    true,
+		},
+		{
+			desc:      "composite_OK",
+			validator: UniqueFor[uniqueForTestModel]("email").WithFields("tenant_id"),
+			value:     "b@example.org",
+			parent:    map[string]any{"email": "b@example.org", "tenant_id": 2},
+			valid:     true,
+			expected:  true,
+		},
+		{
+			desc:      "composite_NOK",
+			validator: UniqueFor[uniqueForTestModel]("email").WithFields("tenant_id"),
+			value:     "a@example.org",
+			parent:    map[string]any{"email": "a@example.org", "tenant_id": 2},
+			valid:     true,
+			expected:  false,
+		},
+		{
+			desc:           "unknown_column",
+			validator:      UniqueFor[uniqueForTestModel]("not_a_column"),
+			value:          "a@example.org",
+			valid:          true,
+			expected:       false,
+			expectedErrors: []string{`model "uniqueForTestModel" has no column "not_a_column"`},
+		},
+		{
+			desc:           "unknown_composite_column",
+			validator:      UniqueFor[uniqueForTestModel]("email").WithFields("not_a_column"),
+			value:          "a@example.org",
+			valid:          true,
+			expected:       false,
+			expectedErrors: []string{`model "uniqueForTestModel" has no column "not_a_column"`},
+		},
+		{desc: "ctx_invalid", validator: UniqueFor[uniqueForTestModel]("email"), value: "a@example.org", valid: false, expected: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			opts := prepareUniqueForTest(t)
+			c.validator.init(opts)
+
+			ctx := &Context{
+				Invalid: !c.valid,
+				Value:   c.value,
+				Parent:  c.parent,
+				Extra:   map[any]any{ExtraRouteParams{}: c.routeParams},
+			}
+			assert.Equal(t, c.expected, c.validator.Validate(ctx))
+			expectedErrors := lo.Ternary(c.expectedErrors == nil, []string{}, c.expectedErrors)
+			assert.Equal(t, expectedErrors, lo.Map(ctx.errors, func(e error, _ int) string { return e.Error() }))
+		})
+	}
+
+	t.Run("no_primary_key", func(t *testing.T) {
+		opts := prepareUniqueForTest(t)
+		v := UniqueFor[noPrimaryKeyTestModel]("name").Ignore("id")
+		v.init(opts)
+		ctx := &Context{
+			Value: "a",
+			Extra: map[any]any{ExtraRouteParams{}: map[string]string{"id": "1"}},
+		}
+		assert.False(t, v.Validate(ctx))
+		assert.Equal(t, []string{`model "noPrimaryKeyTestModel" has no primary key, an ignore column must be specified`}, lo.Map(ctx.errors, func(e error, _ int) string { return e.Error() }))
+	})
+}
+
+func TestExistsForValidator(t *testing.T) {
+	t.Run("Constructor", func(t *testing.T) {
+		v := ExistsFor[uniqueForTestModel]("id")
+		assert.NotNil(t, v)
+		assert.Equal(t, "exists", v.Name())
+		assert.False(t, v.IsType())
+		assert.False(t, v.IsTypeDependent())
+		assert.Equal(t, "id", v.Column)
+
+		v.WithFields("tenant_id").WithTrashed()
+		assert.Equal(t, []string{"tenant_id"}, v.With)
+		assert.True(t, v.Trashed)
+
+		// Ignoring records is specific to uniqueness and must not be available for existence checks.
+		_, ok := any(v).(interface {
+			Ignore(routeParam string) *UniqueForValidator[uniqueForTestModel]
+		})
+		assert.False(t, ok)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		v := ExistsFor[uniqueForTestModel]("id")
+		ctx := &Context{Value: 5, Invalid: true}
+		assert.True(t, v.Validate(ctx))
+		assert.Empty(t, ctx.errors)
+	})
+
+	t.Run("unknown_column", func(t *testing.T) {
+		opts := prepareUniqueForTest(t)
+		v := ExistsFor[uniqueForTestModel]("not_a_column")
+		v.init(opts)
+
+		ctx := &Context{Value: 1}
+		assert.False(t, v.Validate(ctx))
+		assert.Equal(t, []string{`model "uniqueForTestModel" has no column "not_a_column"`}, lo.Map(ctx.errors, func(e error, _ int) string { return e.Error() }))
+	})
+
+	cases := []struct {
+		validator *ExistsForValidator[uniqueForTestModel]
+		value     any
+		parent    any
+		desc      string
+		expected  bool
+	}{
+		{desc: "OK", validator: ExistsFor[uniqueForTestModel]("id"), value: 1, expected: true},
+		{desc: "NOK", validator: ExistsFor[uniqueForTestModel]("id"), value: 5, expected: false},
+		{desc: "soft_deleted", validator: ExistsFor[uniqueForTestModel]("id"), value: 4, expected: false},
+		{desc: "with_trashed", validator: ExistsFor[uniqueForTestModel]("id").WithTrashed(), value: 4, expected: true},
+		{desc: "composite_OK", validator: ExistsFor[uniqueForTestModel]("id").WithFields("tenant_id"), value: 3, parent: map[string]any{"tenant_id": 2}, expected: true},
+		{desc: "composite_NOK", validator: ExistsFor[uniqueForTestModel]("id").WithFields("tenant_id"), value: 3, parent: map[string]any{"tenant_id": 1}, expected: false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			opts := prepareUniqueForTest(t)
+			c.validator.init(opts)
+
+			ctx := &Context{
+				Value:  c.value,
+				Parent: c.parent,
+			}
+			assert.Equal(t, c.expected, c.validator.Validate(ctx))
+			assert.Empty(t, ctx.errors)
+		})
+	}
+}
+
+func TestExistsArrayForValidator(t *testing.T) {
+	t.Run("Constructor", func(t *testing.T) {
+		v := ExistsArrayFor[uniqueForTestModel, uint]("id", nil)
+		assert.NotNil(t, v)
+		assert.Equal(t, "exists", v.Name())
+		assert.Equal(t, "id", v.Column)
+		assert.Empty(t, v.Table)
+		assert.True(t, v.WithTrashed().Trashed)
+	})
+
+	t.Run("Validate", func(t *testing.T) {
+		opts := prepareUniqueForTest(t)
+		v := ExistsArrayFor[uniqueForTestModel, uint]("ID", nil)
+		v.init(opts)
+
+		ctx := &Context{Value: []uint{1, 4, 5, 3}}
+		assert.True(t, v.Validate(ctx))
+		assert.Empty(t, ctx.errors)
+		assert.Equal(t, []int{1, 2}, ctx.arrayElementErrors)
+		assert.Equal(t, "unique_for_models", v.Table)
+		assert.Equal(t, "id", v.Column)
+		assert.Equal(t, "deleted_at", v.SoftDeleteColumn)
+	})
+
+	t.Run("resolved_once", func(t *testing.T) {
+		opts := prepareUniqueForTest(t)
+		v := ExistsArrayFor[uniqueForTestModel, uint]("ID", nil)
+		v.init(opts)
+
+		assert.True(t, v.Validate(&Context{Value: []uint{1}}))
+		assert.Equal(t, "deleted_at", v.SoftDeleteColumn)
+
+		v.SoftDeleteColumn = ""
+		ctx := &Context{Value: []uint{1, 4}}
+		assert.True(t, v.Validate(ctx))
+		assert.Empty(t, v.SoftDeleteColumn)
+		assert.Empty(t, ctx.arrayElementErrors)
+	})
+
+	t.Run("Validate_with_trashed", func(t *testing.T) {
+		opts := prepareUniqueForTest(t)
+		v := ExistsArrayFor[uniqueForTestModel, uint]("id", nil).WithTrashed()
+		v.init(opts)
+
+		ctx := &Context{Value: []uint{1, 4, 5, 3}}
+		assert.True(t, v.Validate(ctx))
+		assert.Empty(t, ctx.errors)
+		assert.Equal(t, []int{2}, ctx.arrayElementErrors)
+		assert.Empty(t, v.SoftDeleteColumn)
+	})
+
+	t.Run("unknown_column", func(t *testing.T) {
+		opts := prepareUniqueForTest(t)
+		v := ExistsArrayFor[uniqueForTestModel, uint]("not_a_column", nil)
+		v.init(opts)
+
+		ctx := &Context{Value: []uint{1}}
+		assert.False(t, v.Validate(ctx))
+		assert.Equal(t, []string{`model "uniqueForTestModel" has no column "not_a_column"`}, lo.Map(ctx.errors, func(e error, _ int) string { return e.Error() }))
+	})
+}
+
+func TestUniqueArrayForValidator(t *testing.T) {
+	t.Run("Constructor", func(t *testing.T) {
+		v := UniqueArrayFor[uniqueForTestModel, string]("email", nil)
+		assert.NotNil(t, v)
+		assert.Equal(t, "unique", v.Name())
+		assert.Equal(t, "email", v.Column)
+		assert.True(t, v.WithTrashed().Trashed)
+	})
+
+	t.Run("Validate", func(t *testing.T) {
+		opts := prepareUniqueForTest(t)
+		v := UniqueArrayFor[uniqueForTestModel, string]("email", nil)
+		v.init(opts)
+
+		ctx := &Context{Value: []string{"c@example.org", "b@example.org", "deleted@example.org"}}
+		assert.True(t, v.Validate(ctx))
+		assert.Empty(t, ctx.errors)
+		assert.Equal(t, []int{1}, ctx.arrayElementErrors)
+	})
+
+	t.Run("Validate_with_trashed", func(t *testing.T) {
+		opts := prepareUniqueForTest(t)
+		v := UniqueArrayFor[uniqueForTestModel, string]("email", nil).WithTrashed()
+		v.init(opts)
+
+		ctx := &Context{Value: []string{"c@example.org", "b@example.org", "deleted@example.org"}}
+		assert.True(t, v.Validate(ctx))
+		assert.Empty(t, ctx.errors)
+		assert.Equal(t, []int{1, 2}, ctx.arrayElementErrors)
+	})
+
+	t.Run("unknown_column", func(t *testing.T) {
+		opts := prepareUniqueForTest(t)
+		v := UniqueArrayFor[uniqueForTestModel, string]("not_a_column", nil)
+		v.init(opts)
+
+		ctx := &Context{Value: []string{"a"}}
+		assert.False(t, v.Validate(ctx))
+		assert.Len(t, ctx.errors, 1)
+	})
+}
@@ -26,6 +26,10 @@ const (
 // request's information is accessible to validation rules
 type ExtraRequest struct{}
 
+// ExtraRouteParams extra key used when validating a request so the
+// route parameters (`map[string]string`) are accessible to validation rules.
+type ExtraRouteParams struct{}
+
 // FieldType returned by the GetFieldType function.
 const (
 	FieldTypeNumeric     = "numeric"
@@ -2,7 +2,6 @@ package websocket
 
 import (
 	"net/http"
-	"time"
 
 	stderrors "errors"
 
@@ -229,7 +228,7 @@ func (u *Upgrader) Handler() goyave.Handler {
 }
 
 func (u *Upgrader) serve(c *ws.Conn, request *goyave.Request, handler func(*Conn, *goyave.Request) error) {
-	conn := newConn(c, time.Duration(u.Config().GetInt("server.websocketCloseTimeout"))*time.Second)
+	conn := newConn(c, u.Config().GetDuration("server.websocketCloseTimeout"))
 	panicked := true
 	var err error
 	defer func() { // Panic recovery